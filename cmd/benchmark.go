@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"codegraphgen/db"
+	"codegraphgen/internal/core"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchmarkIterations int
+	benchmarkWarmup     int
+)
+
+// memorySampleInterval is how often the heap is sampled to find the peak
+// memory usage of a benchmark run
+const memorySampleInterval = 10 * time.Millisecond
+
+// benchmarkRun holds the measurements of one analysis of the benchmarked directory
+type benchmarkRun struct {
+	Duration time.Duration
+	Files    int
+	Entities int
+	Bytes    int64
+	PeakHeap uint64
+}
+
+// benchmarkCmd represents the benchmark command
+var benchmarkCmd = &cobra.Command{
+	Use:   "benchmark [directory]",
+	Short: "Measure the analysis throughput on a codebase",
+	Long: `Analyze a codebase directory several times, storing each result in a fresh
+in-memory database, and print per run and on average the files, entities and
+source megabytes analyzed per second and the peak heap usage. Warmup runs are
+printed but left out of the averages. Log output is discarded during the runs.
+
+Examples:
+  codegraphgen benchmark .
+  codegraphgen benchmark ./my-project --iterations 5 --warmup 2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dirPath := args[0]
+
+		if benchmarkIterations < 1 {
+			log.Fatalf("--iterations must be at least 1")
+		}
+		if benchmarkWarmup < 0 {
+			log.Fatalf("--warmup must not be negative")
+		}
+
+		database := db.NewInMemoryDatabase()
+		generator := core.NewKnowledgeGraphGenerator(core.NewTextProcessor(), database)
+
+		fmt.Printf("⏱️ Benchmarking analysis of %s: %d warmup and %d measured runs\n\n",
+			dirPath, benchmarkWarmup, benchmarkIterations)
+
+		var runs []benchmarkRun
+		for i := 0; i < benchmarkWarmup+benchmarkIterations; i++ {
+			run, err := runBenchmark(database, generator, dirPath)
+			if err != nil {
+				log.Fatalf("Benchmark run %d failed: %v", i+1, err)
+			}
+			runs = append(runs, run)
+		}
+
+		printBenchmarkRuns(runs, benchmarkWarmup)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(benchmarkCmd)
+	benchmarkCmd.Flags().IntVar(&benchmarkIterations, "iterations", 3, "Number of measured runs")
+	benchmarkCmd.Flags().IntVar(&benchmarkWarmup, "warmup", 1, "Number of runs before the measured runs, left out of the averages")
+}
+
+// runBenchmark clears the database, then analyzes a directory once and stores
+// the result with the generator, silencing the output of both
+func runBenchmark(database *db.InMemoryDatabase, generator *core.KnowledgeGraphGenerator, dirPath string) (benchmarkRun, error) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return benchmarkRun{}, err
+	}
+	defer devNull.Close()
+
+	stdout := os.Stdout
+	os.Stdout = devNull
+	log.SetOutput(io.Discard)
+	defer func() {
+		os.Stdout = stdout
+		log.SetOutput(os.Stderr)
+	}()
+
+	if err := database.ClearDatabase(); err != nil {
+		return benchmarkRun{}, fmt.Errorf("failed to clear database: %w", err)
+	}
+
+	// Start from a collected heap so the peak reflects this run only
+	runtime.GC()
+	peak, stop := sampleHeap()
+
+	start := time.Now()
+	entities, relationships, err := core.NewCodeProcessor().AnalyzeCodebase(dirPath)
+	if err == nil {
+		err = generator.BatchStoreKnowledgeGraph(context.Background(), entities, relationships, batchSize)
+	}
+	duration := time.Since(start)
+	stop()
+	if err != nil {
+		return benchmarkRun{}, err
+	}
+
+	run := benchmarkRun{
+		Duration: duration,
+		Entities: len(entities),
+		PeakHeap: *peak,
+	}
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeFile {
+			continue
+		}
+		run.Files++
+		run.Bytes += fileSize(entity.Properties["size"])
+	}
+	return run, nil
+}
+
+// sampleHeap samples the heap in use until stop is called and returns the
+// highest value seen, which is only final once stop has returned
+func sampleHeap() (*uint64, func()) {
+	var peak uint64
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	sample := func() {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > peak {
+			peak = stats.HeapAlloc
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			sample()
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return &peak, func() {
+		close(done)
+		wg.Wait()
+		sample()
+	}
+}
+
+// fileSize returns the size property of a file entity, which is a float64 when
+// read back from the analysis cache
+func fileSize(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// printBenchmarkRuns prints the measurements of each run and the averages of
+// the runs after the warmup runs
+func printBenchmarkRuns(runs []benchmarkRun, warmup int) {
+	const megabyte = 1024 * 1024
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tDURATION\tFILES/S\tENTITIES/S\tMB/S\tPEAK HEAP (MB)")
+	row := func(name string, duration time.Duration, files, entities int, bytes int64, peakHeap uint64) {
+		seconds := duration.Seconds()
+		fmt.Fprintf(w, "%s\t%s\t%.1f\t%.1f\t%.2f\t%.1f\n", name, duration.Round(time.Millisecond),
+			float64(files)/seconds, float64(entities)/seconds,
+			float64(bytes)/megabyte/seconds, float64(peakHeap)/megabyte)
+	}
+
+	var total benchmarkRun
+	for i, run := range runs {
+		name := fmt.Sprintf("%d", i+1)
+		if i < warmup {
+			name += " (warmup)"
+		} else {
+			total.Duration += run.Duration
+			total.Files += run.Files
+			total.Entities += run.Entities
+			total.Bytes += run.Bytes
+			total.PeakHeap += run.PeakHeap
+		}
+		row(name, run.Duration, run.Files, run.Entities, run.Bytes, run.PeakHeap)
+	}
+
+	// Throughputs are averaged over the total time of the measured runs
+	measured := len(runs) - warmup
+	row("average", total.Duration/time.Duration(measured), total.Files/measured,
+		total.Entities/measured, total.Bytes/int64(measured), total.PeakHeap/uint64(measured))
+	w.Flush()
+}
@@ -1,15 +1,37 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
 
-	"codegraphgen/db"
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/config"
 	"codegraphgen/internal/core"
+	"codegraphgen/internal/core/cache"
+	"codegraphgen/internal/core/graph"
+	"codegraphgen/internal/git"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
+var (
+	outputDir    string
+	dryRun       bool
+	concurrency  int
+	coverageFile string
+	sinceCommit  string
+	buildTags    []string
+	useCache     bool
+	showProgress bool
+	tsconfigPath string
+	excludeDirs  []string
+)
+
 // codebaseCmd represents the codebase command
 var codebaseCmd = &cobra.Command{
 	Use:   "codebase [directory]",
@@ -22,57 +44,260 @@ and optionally store them in a database.
 Examples:
   codegraphgen codebase .
   codegraphgen codebase ./my-project --memgraph
-  codegraphgen codebase /path/to/code --memgraph`,
+  codegraphgen codebase /path/to/code --memgraph
+  codegraphgen codebase . --output-dir ./graph-out
+  codegraphgen codebase . --dry-run
+  codegraphgen codebase . --concurrency 8
+  codegraphgen codebase . --coverage-file cover.out
+  codegraphgen codebase . --memgraph --since-commit HEAD~1
+  codegraphgen codebase . --build-tag linux --build-tag amd64
+  codegraphgen codebase . --cache
+  codegraphgen codebase . --progress
+  codegraphgen codebase . --tsconfig tsconfig.app.json
+  codegraphgen codebase . --exclude-dir generated --exclude-dir 'fixtures-*'`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		dirPath := args[0]
 
 		if verbose {
 			fmt.Printf("🔍 Analyzing codebase at: %s\n", dirPath)
-			if useMemgraph {
-				fmt.Println("🔗 Using Memgraph database")
-			} else {
-				fmt.Println("🧠 Using in-memory database")
+			fmt.Println(databaseDescription())
+		}
+
+		if concurrency < 1 {
+			log.Fatalf("--concurrency must be at least 1")
+		}
+
+		projectConfig := loadProjectConfig(dirPath)
+		codeProcessor := core.NewCodeProcessor()
+		if projectConfig != nil {
+			applyProjectConfig(codeProcessor, projectConfig)
+		}
+		codeProcessor.AddSkipDirectories(excludeDirs...)
+
+		if dryRun {
+			files, err := codeProcessor.ScanCodebase(dirPath)
+			if err != nil {
+				log.Fatalf("Failed to scan codebase: %v", err)
 			}
+			printDryRun(files)
+			return
 		}
 
 		// Initialize components
 		textProcessor := core.NewTextProcessor()
 
-		var database db.DatabaseConnection
-		if useMemgraph {
-			memgraphDB := db.NewMemgraphDatabase("bolt://localhost:7687", "", "")
-			if err := memgraphDB.Connect(); err != nil {
-				log.Fatalf("Failed to connect to Memgraph: %v", err)
+		applyProjectMemgraphConfig(projectConfig)
+		database := openDatabase()
+		defer database.Disconnect()
+
+		codeProcessor.Concurrency = concurrency
+		codeProcessor.BuildTags = buildTags
+		if tsconfigPath != "" {
+			aliases, err := analysis.LoadTypeScriptPathAliases(tsconfigPath)
+			if err != nil {
+				log.Fatalf("Failed to load TypeScript path aliases: %v", err)
 			}
-			database = memgraphDB
-			defer memgraphDB.Disconnect()
-		} else {
-			database = db.NewInMemoryDatabase()
-			if err := database.Connect(); err != nil {
-				log.Fatalf("Failed to connect to in-memory database: %v", err)
+			codeProcessor.TypeScriptPathAliases = aliases
+		}
+		if useCache {
+			analysisCache, err := cache.NewSQLiteCache(filepath.Join(dirPath, cache.DefaultPath))
+			if err != nil {
+				log.Fatalf("Failed to open analysis cache: %v", err)
 			}
+			defer analysisCache.Close()
+			codeProcessor.WithAnalysisCache(analysisCache)
 		}
-
-		codeProcessor := core.NewCodeProcessor()
 		generator := core.NewKnowledgeGraphGenerator(textProcessor, database)
 
-		// Analyze the codebase
-		kg, err := analyzeCodebase(codeProcessor, dirPath)
+		// Write each file's results as soon as it has been analyzed
+		var outputter *core.FileOutputter
+		if outputDir != "" {
+			var err error
+			outputter, err = core.NewFileOutputter(outputDir, dirPath)
+			if err != nil {
+				log.Fatalf("Failed to prepare output directory: %v", err)
+			}
+			codeProcessor.SetProgressFunc(outputter.Write)
+		}
+
+		// Report the progress instead of each file, by default on a terminal
+		terminal := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+		var progress *analysisProgress
+		if showProgress || (terminal && !cmd.Flags().Changed("progress")) {
+			progress = newAnalysisProgress(os.Stdout, terminal)
+			codeProcessor.LogFiles = false
+			codeProcessor.SetStartFunc(progress.Start)
+			codeProcessor.SetProgressFunc(func(file graph.CodeFile, entities []graph.Entity, relationships []graph.Relationship) {
+				if outputter != nil {
+					outputter.Write(file, entities, relationships)
+				}
+				progress.Update(file, entities, relationships)
+			})
+		}
+
+		// Analyze the codebase, or only the files changed since a commit
+		var kg *graph.KnowledgeGraph
+		var changed []string
+		var err error
+		if sinceCommit != "" {
+			kg, changed, err = analyzeChangedFiles(codeProcessor, dirPath, sinceCommit)
+		} else {
+			kg, err = analyzeCodebase(codeProcessor, dirPath, os.Stdout)
+		}
+		if progress != nil {
+			progress.Finish()
+		}
 		if err != nil {
 			log.Fatalf("Failed to analyze codebase: %v", err)
 		}
 
+		if coverageFile != "" {
+			coverageRelationships, err := analysis.ParseGoCoverProfile(coverageFile, kg.Entities)
+			if err != nil {
+				log.Fatalf("Failed to read coverage: %v", err)
+			}
+			kg.Relationships = append(kg.Relationships, coverageRelationships...)
+		}
+
+		kg = kg.FilterByConfidence(confidenceThreshold)
+
 		// Store in database
-		err = generator.StoreKnowledgeGraph(kg.Entities, kg.Relationships)
+		ctx := context.Background()
+
+		if sinceCommit != "" {
+			// Replace the graph of the changed files, keeping the links of unchanged files to them
+			err = generator.ReplaceSourceFiles(ctx, changed, kg.Entities, kg.Relationships)
+		} else {
+			err = generator.BatchStoreKnowledgeGraph(ctx, kg.Entities, kg.Relationships, batchSize)
+		}
 		if err != nil {
 			log.Fatalf("Failed to store knowledge graph: %v", err)
 		}
 
 		printKnowledgeGraph(kg)
+
+		if outputter != nil {
+			fmt.Printf("\n📁 Wrote %d per-file results to %s\n", outputter.Written(), outputDir)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(codebaseCmd)
+	codebaseCmd.Flags().StringVar(&outputDir, "output-dir", "", "Write per-file analysis results (<filename>.graph.json) to this directory")
+	codebaseCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files that would be analyzed without analyzing them")
+	codebaseCmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of files to analyze in parallel")
+	codebaseCmd.Flags().StringVar(&sinceCommit, "since-commit", "", "Only re-analyze the files changed since this git commit and remove the entities of deleted files")
+	codebaseCmd.Flags().StringArrayVar(&buildTags, "build-tag", nil, "Skip Go files whose build constraints aren't satisfied by these tags (repeatable)")
+	codebaseCmd.Flags().BoolVar(&useCache, "cache", false, "Reuse the analysis results of unchanged files from .codegraphgen/cache.db in the analyzed directory")
+	codebaseCmd.Flags().BoolVar(&showProgress, "progress", false, "Show a progress bar instead of a line per file (the default when stdout is a terminal; a line every 10 files otherwise)")
+	codebaseCmd.Flags().StringVar(&tsconfigPath, "tsconfig", "", "tsconfig.json whose compilerOptions.paths aliases resolve TypeScript imports (default: tsconfig.json in the analyzed directory)")
+	codebaseCmd.Flags().StringArrayVar(&excludeDirs, "exclude-dir", nil, "Skip directories with this name or matching this glob pattern, in addition to the defaults such as node_modules (repeatable)")
+	codebaseCmd.Flags().StringVar(&coverageFile, "coverage-file", "", "Go cover profile (go test -coverprofile) to annotate functions with their test coverage")
+}
+
+// analyzeChangedFiles analyzes the files of a git repository changed between
+// commitRef and HEAD. It returns the graph of the files that still
+// exist and all changed files, whose stored graph the result replaces, so that
+// code removed from changed files and the entities of deleted files disappear.
+func analyzeChangedFiles(processor *core.CodeProcessor, dirPath, commitRef string) (*graph.KnowledgeGraph, []string, error) {
+	changed, err := git.ChangedFilesSince(dirPath, commitRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	deleted, err := git.DeletedFilesSince(dirPath, commitRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list deleted files: %w", err)
+	}
+
+	isDeleted := make(map[string]bool, len(deleted))
+	for _, path := range deleted {
+		isDeleted[path] = true
+	}
+	var modified []string
+	for _, path := range changed {
+		if !isDeleted[path] {
+			modified = append(modified, path)
+		}
+	}
+	fmt.Printf("🔀 %d files added or modified and %d deleted since %s\n", len(modified), len(deleted), commitRef)
+
+	entities, relationships, err := processor.AnalyzeFiles(dirPath, modified)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to process changed files: %w", err)
+	}
+
+	fmt.Printf("✅ Found %d entities and %d relationships\n", len(entities), len(relationships))
+
+	return &graph.KnowledgeGraph{
+		Entities:      entities,
+		Relationships: relationships,
+	}, changed, nil
+}
+
+// loadProjectConfig returns the .codegraphgen.yaml of a directory, or nil if it
+// has none
+func loadProjectConfig(dirPath string) *config.Config {
+	configPath := filepath.Join(dirPath, config.FileName)
+	if _, err := os.Stat(configPath); err != nil {
+		return nil
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load project config: %v", err)
+	}
+	return cfg
+}
+
+// newProjectCodeProcessor returns a code processor for a directory that applies
+// the include and exclude patterns, file size limit and skipped directories of
+// its .codegraphgen.yaml, if it has one
+func newProjectCodeProcessor(dirPath string) *core.CodeProcessor {
+	processor := core.NewCodeProcessor()
+	if cfg := loadProjectConfig(dirPath); cfg != nil {
+		applyProjectConfig(processor, cfg)
+	}
+	return processor
+}
+
+// applyProjectConfig applies the file selection of a project config to a code processor
+func applyProjectConfig(processor *core.CodeProcessor, cfg *config.Config) {
+	processor.AddSkipDirectories(cfg.SkipDirectories...)
+	processor.IncludePatterns = cfg.Include
+	processor.ExcludePatterns = cfg.Exclude
+	processor.MaxFileSize = int64(cfg.MaxFileSizeMB) * 1024 * 1024
+}
+
+// applyProjectMemgraphConfig uses the Memgraph settings of a project config for
+// the connection settings given neither by flag nor by environment variable
+func applyProjectMemgraphConfig(cfg *config.Config) {
+	if cfg == nil || cfg.Memgraph == nil {
+		return
+	}
+	if memgraphURI == "" && os.Getenv("MEMGRAPH_URI") == "" {
+		memgraphURI = cfg.Memgraph.URI
+	}
+	if memgraphUser == "" && os.Getenv("MEMGRAPH_USER") == "" {
+		memgraphUser = cfg.Memgraph.User
+	}
+	memgraphTLS = memgraphTLS || cfg.Memgraph.TLS
+}
+
+// printDryRun prints the files that would be analyzed and a summary
+func printDryRun(files []graph.CodeFile) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tLANGUAGE\tSIZE")
+
+	languages := make(map[string]bool)
+	var totalSize int64
+	for _, file := range files {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", file.Path, file.Language, file.Size)
+		languages[file.Language] = true
+		totalSize += file.Size
+	}
+	w.Flush()
+
+	fmt.Printf("\n🧪 Would analyze %d files across %d languages (%.2f MB total)\n",
+		len(files), len(languages), float64(totalSize)/(1024*1024))
 }
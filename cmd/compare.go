@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareFormat       string
+	compareBreakingOnly bool
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare [dir-a] [dir-b]",
+	Short: "Compare the architecture of two codebases",
+	Long: `Analyze two directories and compare the resulting knowledge graphs.
+Entities are matched by label and type, relationships by their endpoints and type.
+This is useful for comparing two versions of a library's API surface.
+
+The table lists the entities and relationships found in only one directory and
+counts the entities found in both; --verbose lists those too. The json format
+always includes the common entities.
+
+With --breaking-only, only changes that break callers are reported: exported
+functions and public methods removed from B, parameters added to them, and
+changed parameter or return types.
+
+Examples:
+  codegraphgen compare ./v1 ./v2
+  codegraphgen compare ./v1 ./v2 --verbose
+  codegraphgen compare ./v1 ./v2 --format json
+  codegraphgen compare ./v1 ./v2 --format dot > diff.dot
+  codegraphgen compare ./v1 ./v2 --breaking-only`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dirA, dirB := args[0], args[1]
+
+		switch compareFormat {
+		case "table", "json", "dot":
+		default:
+			log.Fatalf("Unsupported format %q: expected table, json or dot", compareFormat)
+		}
+		if compareBreakingOnly && compareFormat == "dot" {
+			log.Fatalf("--breaking-only supports the table and json formats")
+		}
+
+		if verbose {
+			fmt.Printf("🔀 Comparing %s with %s\n", dirA, dirB)
+		}
+
+		// Keep progress output out of machine-readable formats
+		var out io.Writer = os.Stdout
+		if compareFormat != "table" {
+			out = os.Stderr
+		}
+
+		kgA, err := analyzeCodebase(newProjectCodeProcessor(dirA), dirA, out)
+		if err != nil {
+			log.Fatalf("Failed to analyze %s: %v", dirA, err)
+		}
+
+		kgB, err := analyzeCodebase(newProjectCodeProcessor(dirB), dirB, out)
+		if err != nil {
+			log.Fatalf("Failed to analyze %s: %v", dirB, err)
+		}
+
+		if compareBreakingOnly {
+			changes := analysis.DetectBreakingChanges(kgA, kgB)
+			if compareFormat == "json" {
+				if changes == nil {
+					changes = []analysis.BreakingChange{}
+				}
+				printJSON(changes)
+			} else {
+				printBreakingChanges(changes)
+			}
+			return
+		}
+
+		diff := analysis.CompareGraphs(kgA, kgB)
+
+		switch compareFormat {
+		case "json":
+			printJSON(diff)
+		case "dot":
+			printDiffDOT(diff, kgA, kgB)
+		default:
+			printDiff(diff, kgA, kgB, dirA, dirB)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().StringVar(&compareFormat, "format", "table", "Output format (table, json, dot)")
+	compareCmd.Flags().BoolVar(&compareBreakingOnly, "breaking-only", false, "Only report API changes that break callers")
+}
+
+// printDiff prints a human-readable summary of a graph diff
+func printDiff(diff *analysis.GraphDiff, kgA, kgB *graph.KnowledgeGraph, dirA, dirB string) {
+	fmt.Println("\n🔀 Comparison Results:")
+	fmt.Printf("A: %s\n", dirA)
+	fmt.Printf("B: %s\n", dirB)
+
+	printDiffEntities("Entities only in A", diff.EntitiesOnlyInA)
+	printDiffEntities("Entities only in B", diff.EntitiesOnlyInB)
+	printDiffRelationships("Relationships only in A", diff.RelationshipsOnlyInA, kgA)
+	printDiffRelationships("Relationships only in B", diff.RelationshipsOnlyInB, kgB)
+
+	if verbose {
+		printDiffEntities("Common entities", diff.CommonEntities)
+	} else {
+		fmt.Printf("\n🤝 Common entities: %d (listed with --verbose)\n", len(diff.CommonEntities))
+	}
+}
+
+// printBreakingChanges prints breaking API changes as a table
+func printBreakingChanges(changes []analysis.BreakingChange) {
+	if len(changes) == 0 {
+		fmt.Println("\n✅ No breaking changes")
+		return
+	}
+
+	fmt.Printf("\n💥 Breaking changes (%d):\n", len(changes))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tENTITY\tDETAILS")
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", change.Type, change.EntityLabel, change.Details)
+	}
+	w.Flush()
+}
+
+// printDiffEntities prints one section of entities of a graph diff
+func printDiffEntities(title string, entities []graph.Entity) {
+	fmt.Printf("\n📦 %s (%d):\n", title, len(entities))
+	for _, entity := range entities {
+		fmt.Printf("  %s (%s)\n", entity.Label, entity.Type)
+	}
+}
+
+// printDiffRelationships prints one section of relationships of a graph diff
+func printDiffRelationships(title string, relationships []graph.Relationship, kg *graph.KnowledgeGraph) {
+	labels := entityLabels(kg)
+
+	fmt.Printf("\n🔗 %s (%d):\n", title, len(relationships))
+	for _, rel := range relationships {
+		fmt.Printf("  %s -[%s]-> %s\n", labels[rel.Source], rel.Type, labels[rel.Target])
+	}
+}
+
+// printDiffDOT prints a graph diff in Graphviz DOT format.
+// Entities and relationships only in A are red, those only in B are green.
+func printDiffDOT(diff *analysis.GraphDiff, kgA, kgB *graph.KnowledgeGraph) {
+	const colorA, colorB = "red", "green"
+
+	fmt.Println("digraph compare {")
+	fmt.Println("  node [shape=box, style=filled, fillcolor=white];")
+
+	nodes := make(map[string]bool)
+	writeNode := func(entity graph.Entity, color string) {
+		key := analysis.EntityKey(entity)
+		if nodes[key] {
+			return
+		}
+		nodes[key] = true
+		if color == "" {
+			fmt.Printf("  %s [label=%s];\n", dotQuote(key), dotQuote(entity.Label))
+		} else {
+			fmt.Printf("  %s [label=%s, fillcolor=%s];\n", dotQuote(key), dotQuote(entity.Label), color)
+		}
+	}
+
+	for _, entity := range diff.EntitiesOnlyInA {
+		writeNode(entity, colorA)
+	}
+	for _, entity := range diff.EntitiesOnlyInB {
+		writeNode(entity, colorB)
+	}
+
+	writeEdges := func(relationships []graph.Relationship, kg *graph.KnowledgeGraph, color string) {
+		byID := make(map[string]graph.Entity, len(kg.Entities))
+		for _, entity := range kg.Entities {
+			byID[entity.ID] = entity
+		}
+		for _, rel := range relationships {
+			source, target := byID[rel.Source], byID[rel.Target]
+			// Endpoints that exist in both graphs are drawn uncolored
+			writeNode(source, "")
+			writeNode(target, "")
+			fmt.Printf("  %s -> %s [label=%s, color=%s];\n",
+				dotQuote(analysis.EntityKey(source)), dotQuote(analysis.EntityKey(target)),
+				dotQuote(string(rel.Type)), color)
+		}
+	}
+
+	writeEdges(diff.RelationshipsOnlyInA, kgA, colorA)
+	writeEdges(diff.RelationshipsOnlyInB, kgB, colorB)
+
+	fmt.Println("}")
+}
+
+// entityLabels maps entity IDs to labels
+func entityLabels(kg *graph.KnowledgeGraph) map[string]string {
+	labels := make(map[string]string, len(kg.Entities))
+	for _, entity := range kg.Entities {
+		labels[entity.ID] = entity.Label
+	}
+	return labels
+}
+
+// dotQuote quotes a string for use as a DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"codegraphgen/internal/core"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate the completion script of codegraphgen for the given shell and print
+it to stdout.
+
+Bash (requires the bash-completion package):
+  # Current session
+  source <(codegraphgen completion bash)
+  # Every session, on Linux
+  codegraphgen completion bash > /etc/bash_completion.d/codegraphgen
+  # Every session, on macOS with Homebrew
+  codegraphgen completion bash > $(brew --prefix)/etc/bash_completion.d/codegraphgen
+
+Zsh:
+  # Enable completion once, if it is not already
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+  # Every session
+  codegraphgen completion zsh > "${fpath[1]}/_codegraphgen"
+
+Fish:
+  # Current session
+  codegraphgen completion fish | source
+  # Every session
+  codegraphgen completion fish > ~/.config/fish/completions/codegraphgen.fish
+
+PowerShell:
+  # Current session
+  codegraphgen completion powershell | Out-String | Invoke-Expression
+  # Every session: add the line above to your $PROFILE
+
+Start a new shell for the changes to take effect.`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletion(os.Stdout)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletion(os.Stdout)
+		}
+		if err != nil {
+			log.Fatalf("Failed to generate %s completion: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	// The completion command replaces the one Cobra adds by default
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+}
+
+// completeLanguages completes the value of a language flag with the languages
+// files are detected as
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var languages []string
+	for _, language := range core.NewCodeProcessor().Languages() {
+		if strings.HasPrefix(language, toComplete) {
+			languages = append(languages, language)
+		}
+	}
+	return languages, cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerLanguageCompletion completes the values of the named language flag of cmd
+func registerLanguageCompletion(cmd *cobra.Command, flag string) {
+	if err := cmd.RegisterFlagCompletionFunc(flag, completeLanguages); err != nil {
+		log.Fatalf("Failed to register completion of --%s: %v", flag, err)
+	}
+}
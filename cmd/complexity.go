@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"codegraphgen/internal/analysis"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	complexityThreshold int
+	complexityTop       int
+)
+
+// complexityCmd represents the complexity command
+var complexityCmd = &cobra.Command{
+	Use:   "complexity [directory]",
+	Short: "List the most complex functions of a codebase",
+	Long: `Analyze a codebase directory and print the functions with the highest
+cyclomatic complexity, with the file and line they are defined at.
+No database is needed. Cyclomatic complexity is currently calculated for Go.
+
+Examples:
+  codegraphgen complexity .
+  codegraphgen complexity ./my-project --threshold 10 --top 50`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dirPath := args[0]
+
+		if complexityTop < 1 {
+			log.Fatalf("--top must be at least 1")
+		}
+
+		// Only the report goes to stdout
+		kg, err := analyzeCodebase(newProjectCodeProcessor(dirPath), dirPath, os.Stderr)
+		if err != nil {
+			log.Fatalf("Failed to analyze codebase: %v", err)
+		}
+
+		functions := analysis.RankByComplexity(kg.Entities, complexityThreshold, complexityTop)
+		if len(functions) == 0 {
+			fmt.Printf("No functions with a cyclomatic complexity of %d or more\n", complexityThreshold)
+			return
+		}
+
+		printComplexityTable(functions)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(complexityCmd)
+	complexityCmd.Flags().IntVar(&complexityThreshold, "threshold", 0, "Only show functions with at least this cyclomatic complexity")
+	complexityCmd.Flags().IntVar(&complexityTop, "top", 20, "Number of functions to show")
+}
+
+// printComplexityTable prints functions with their complexity as a table
+func printComplexityTable(functions []analysis.FunctionComplexity) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPLEXITY\tFUNCTION\tFILE\tLINE")
+	for _, fn := range functions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", fn.CyclomaticComplexity, fn.Label, fn.SourceFile, fn.LineNumber)
+	}
+	w.Flush()
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/core/graph"
+	"codegraphgen/internal/export"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat  string
+	exportBaseURI string
+	exportOutput  string
+
+	exportDirectories bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [directory]",
+	Short: "Export the knowledge graph of a codebase",
+	Long: `Analyze a codebase directory and export the resulting knowledge graph.
+
+Supported formats:
+  json     - The knowledge graph as plain JSON
+  jsonld   - JSON-LD, for SPARQL queries and integration with other knowledge graphs
+  gexf     - GEXF, for Gephi (file modification times drive the timeline)
+  plantuml - PlantUML class diagram of the classes and interfaces
+
+With --directories the export holds the directory dependency graph instead: a
+node per directory and a DEPENDS_ON edge between two directories whose weight
+counts the relationships from entities in one to entities in the other.
+
+Examples:
+  codegraphgen export ./my-project > graph.json
+  codegraphgen export ./my-project --format jsonld --base-uri https://example.com/code
+  codegraphgen export . --format jsonld --output graph.jsonld
+  codegraphgen export . --format gexf --output graph.gexf
+  codegraphgen export . --format plantuml --output classes.puml
+  codegraphgen export . --directories --format gexf --output directories.gexf`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dirPath := args[0]
+
+		switch exportFormat {
+		case "json", "jsonld", "gexf", "plantuml":
+		default:
+			log.Fatalf("Unsupported format %q: expected json, jsonld, gexf or plantuml", exportFormat)
+		}
+
+		// Keep progress output out of the exported document
+		kg, err := analyzeCodebase(newProjectCodeProcessor(dirPath), dirPath, os.Stderr)
+		if err != nil {
+			log.Fatalf("Failed to analyze codebase: %v", err)
+		}
+		kg = kg.FilterByConfidence(confidenceThreshold)
+		if exportDirectories {
+			kg = analysis.BuildDirectoryDependencyGraph(kg.Entities, kg.Relationships)
+		}
+
+		data, err := exportGraph(kg)
+		if err != nil {
+			log.Fatalf("Failed to export knowledge graph: %v", err)
+		}
+
+		if exportOutput == "" {
+			fmt.Println(string(data))
+			return
+		}
+		if err := os.WriteFile(exportOutput, data, 0644); err != nil {
+			log.Fatalf("Failed to write %s: %v", exportOutput, err)
+		}
+		fmt.Printf("📁 Exported %d entities and %d relationships to %s\n",
+			len(kg.Entities), len(kg.Relationships), exportOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "Export format (json, jsonld, gexf, plantuml)")
+	exportCmd.Flags().StringVar(&exportBaseURI, "base-uri", "https://example.com/code", "Base URI for JSON-LD node identifiers")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to this file instead of stdout")
+	exportCmd.Flags().BoolVar(&exportDirectories, "directories", false, "Export the dependencies between directories, weighted by coupling")
+}
+
+// exportGraph serializes a knowledge graph in the selected export format
+func exportGraph(kg *graph.KnowledgeGraph) ([]byte, error) {
+	switch exportFormat {
+	case "jsonld":
+		return export.ExportJSONLD(kg, exportBaseURI)
+	case "gexf":
+		return export.ExportGEXF(kg)
+	case "plantuml":
+		diagram, err := export.ExportPlantUML(kg)
+		return []byte(diagram), err
+	default:
+		return json.MarshalIndent(kg, "", "  ")
+	}
+}
@@ -1,12 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path/filepath"
 	"strings"
 
-	"codegraphgen/db"
 	"codegraphgen/internal/core"
 	"codegraphgen/internal/core/graph"
 
@@ -36,20 +36,8 @@ Examples:
 		textProcessor := core.NewTextProcessor()
 		codeProcessor := core.NewCodeProcessor()
 
-		var database db.DatabaseConnection
-		if useMemgraph {
-			memgraphDB := db.NewMemgraphDatabase("bolt://localhost:7687", "", "")
-			if err := memgraphDB.Connect(); err != nil {
-				log.Fatalf("Failed to connect to Memgraph: %v", err)
-			}
-			database = memgraphDB
-			defer memgraphDB.Disconnect()
-		} else {
-			database = db.NewInMemoryDatabase()
-			if err := database.Connect(); err != nil {
-				log.Fatalf("Failed to connect to in-memory database: %v", err)
-			}
-		}
+		database := openDatabase()
+		defer database.Disconnect()
 
 		generator := core.NewKnowledgeGraphGenerator(textProcessor, database)
 
@@ -57,7 +45,7 @@ Examples:
 		var kg *graph.KnowledgeGraph
 		var err error
 
-		if isCodeFile(filePath) {
+		if isCodeFile(codeProcessor, filePath) {
 			// Process as a code file
 			if verbose {
 				fmt.Println("🔍 Extracting entities and relationships...")
@@ -68,14 +56,16 @@ Examples:
 				log.Fatalf("Failed to process code file: %v", err)
 			}
 
-			// Store in database
-			if err := generator.StoreKnowledgeGraph(entities, relationships); err != nil {
-				log.Fatalf("Failed to store knowledge graph: %v", err)
-			}
-
-			kg = &graph.KnowledgeGraph{
+			kg = (&graph.KnowledgeGraph{
 				Entities:      entities,
 				Relationships: relationships,
+			}).FilterByConfidence(confidenceThreshold)
+
+			// Store in database
+			ctx := context.Background()
+
+			if err := generator.BatchStoreKnowledgeGraph(ctx, kg.Entities, kg.Relationships, batchSize); err != nil {
+				log.Fatalf("Failed to store knowledge graph: %v", err)
 			}
 		} else {
 			// Process as a text file
@@ -83,6 +73,7 @@ Examples:
 			if err != nil {
 				log.Fatalf("Failed to process text file: %v", err)
 			}
+			kg = kg.FilterByConfidence(confidenceThreshold)
 		}
 
 		printKnowledgeGraph(kg)
@@ -93,8 +84,13 @@ func init() {
 	rootCmd.AddCommand(fileCmd)
 }
 
-// isCodeFile determines if a file is a source code file based on its extension
-func isCodeFile(filePath string) bool {
+// isCodeFile determines if a file is a source code file based on its extension,
+// or on its name for files such as Makefiles
+func isCodeFile(codeProcessor *core.CodeProcessor, filePath string) bool {
+	if codeProcessor.FileNameLanguage(filepath.Base(filePath)) != "" {
+		return true
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
 	codeExtensions := map[string]bool{
 		".go":    true,
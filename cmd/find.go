@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"codegraphgen/internal/core"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	findType  string
+	findLang  string
+	findExact bool
+)
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find [label-substring]",
+	Short: "Find entities by label",
+	Long: `Find entities whose label contains the given text and print where they are defined.
+Results can be narrowed down by entity type and language, or with --exact to
+entities whose label matches the given text exactly.
+
+Examples:
+  codegraphgen find NewServer --memgraph
+  codegraphgen find Analyze --type FUNCTION --lang go --memgraph
+  codegraphgen find NewServer --exact --type FUNCTION --memgraph`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := args[0]
+
+		if verbose {
+			fmt.Printf("🔎 Searching for entities matching: %s\n", query)
+		}
+
+		database := openDatabase()
+		defer database.Disconnect()
+
+		generator := core.NewKnowledgeGraphGenerator(core.NewTextProcessor(), database)
+
+		ctx := context.Background()
+
+		var entities []graph.Entity
+		var err error
+		if findExact {
+			entities, err = generator.GetEntitiesByExactLabel(ctx, query, findType, findLang)
+		} else {
+			entities, err = generator.FindEntitiesByLabel(ctx, query, findType, findLang)
+		}
+		if err != nil {
+			log.Fatalf("Failed to search entities: %v", err)
+		}
+
+		if len(entities) == 0 {
+			fmt.Printf("No entities found matching %q\n", query)
+			return
+		}
+
+		printEntityTable(entities)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+	findCmd.Flags().StringVar(&findType, "type", "", "Only show entities of this type (e.g. FUNCTION)")
+	findCmd.Flags().StringVar(&findLang, "lang", "", "Only show entities of this language (e.g. go)")
+	registerLanguageCompletion(findCmd, "lang")
+	findCmd.Flags().BoolVar(&findExact, "exact", false, "Match the label exactly instead of as a substring")
+}
+
+// printEntityTable prints entities with their location as a tab-separated table
+func printEntityTable(entities []graph.Entity) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tLABEL\tSOURCE FILE\tLINE")
+	for _, entity := range entities {
+		sourceFile := entity.Properties["sourceFile"]
+		if sourceFile == nil {
+			sourceFile = entity.Properties["path"]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entity.ID, entity.Type, entity.Label,
+			formatOptional(sourceFile), formatOptional(entity.Properties["lineNumber"]))
+	}
+	w.Flush()
+}
+
+// formatOptional formats a property value, printing nothing for missing values
+func formatOptional(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"codegraphgen/db"
+	"codegraphgen/internal/core"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	getShowRelationships bool
+	getFormat            string
+)
+
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:   "get [entity-id]",
+	Short: "Show a single entity by ID",
+	Long: `Show a single entity from the knowledge graph by its ID.
+Use --relationships to also list every entity connected to it.
+
+The in-memory database does not persist between runs, so this command is
+most useful against a graph stored in Memgraph.
+
+Examples:
+  codegraphgen get <entity-id> --memgraph
+  codegraphgen get <entity-id> --memgraph --relationships
+  codegraphgen get <entity-id> --memgraph --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		entityID := args[0]
+
+		if verbose {
+			fmt.Printf("🔎 Looking up entity: %s\n", entityID)
+		}
+
+		database := openDatabase()
+		defer database.Disconnect()
+
+		ctx := context.Background()
+
+		entity, err := database.GetEntityByID(ctx, entityID)
+		if err != nil {
+			log.Fatalf("Failed to get entity: %v", err)
+		}
+
+		var connections []db.QueryResult
+		if getShowRelationships {
+			generator := core.NewKnowledgeGraphGenerator(core.NewTextProcessor(), database)
+			connections, err = generator.GetEntityConnections(ctx, entityID)
+			if err != nil {
+				log.Fatalf("Failed to get entity connections: %v", err)
+			}
+		}
+
+		switch getFormat {
+		case "json":
+			output := map[string]interface{}{"entity": entity}
+			if getShowRelationships {
+				output["connections"] = connections
+			}
+			printJSON(output)
+		case "table":
+			printEntity(entity)
+			if getShowRelationships {
+				printConnections(connections)
+			}
+		default:
+			log.Fatalf("Unsupported format %q: expected table or json", getFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+	getCmd.Flags().BoolVar(&getShowRelationships, "relationships", false, "Also show connected entities")
+	getCmd.Flags().StringVar(&getFormat, "format", "table", "Output format (table, json)")
+}
+
+// printEntity prints an entity and its properties as an aligned table
+func printEntity(entity *db.Entity) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", entity.ID)
+	fmt.Fprintf(w, "Label:\t%s\n", entity.Label)
+	fmt.Fprintf(w, "Type:\t%s\n", entity.Type)
+	fmt.Fprintf(w, "Confidence:\t%.2f\n", entity.Confidence)
+
+	keys := make([]string, 0, len(entity.Properties))
+	for key := range entity.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		fmt.Fprintln(w, "Properties:\t")
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %s\t%v\n", key, entity.Properties[key])
+		}
+	}
+	w.Flush()
+}
+
+// printConnections prints the results of GetEntityConnections as a table
+func printConnections(connections []db.QueryResult) {
+	fmt.Printf("\n🔗 Connections (%d):\n", len(connections))
+	if len(connections) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RELATIONSHIP\tLABEL\tTYPE\tID")
+	for _, result := range connections {
+		relType, connected := connectionSummary(result)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", relType, connected.Label, connected.Type, connected.ID)
+	}
+	w.Flush()
+}
+
+// connectionSummary extracts the relationship type and connected entity from a connection result.
+// The in-memory backend returns Entity and Relationship values while Memgraph returns maps.
+func connectionSummary(result db.QueryResult) (db.RelationshipType, db.Entity) {
+	var relType db.RelationshipType
+
+	switch r := result["r"].(type) {
+	case db.Relationship:
+		relType = r.Type
+	case map[string]interface{}:
+		if t, ok := r["type"].(string); ok {
+			relType = db.RelationshipType(t)
+		}
+	}
+
+	connected, _ := db.ToEntity(result["m"])
+	return relType, connected
+}
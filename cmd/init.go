@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"codegraphgen/db"
+	"codegraphgen/internal/config"
+	"codegraphgen/internal/core"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/spf13/cobra"
+)
+
+var forceInit bool
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init [directory]",
+	Short: "Create a .codegraphgen.yaml config for a project",
+	Long: `Scan a project directory for the languages it contains and write a .codegraphgen.yaml
+config file with sensible defaults: the detected languages are included, common build
+and dependency directories are excluded and files larger than 1 MB are skipped.
+
+With --memgraph the Memgraph connection settings are written to the config as well.
+The password is never written; keep it in MEMGRAPH_PASSWORD.
+
+Examples:
+  codegraphgen init
+  codegraphgen init ./my-project
+  codegraphgen init . --memgraph --memgraph-uri bolt://db:7687`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dirPath := "."
+		if len(args) > 0 {
+			dirPath = args[0]
+		}
+
+		configPath := filepath.Join(dirPath, config.FileName)
+		if _, err := os.Stat(configPath); err == nil && !forceInit {
+			log.Fatalf("%s already exists (use --force to overwrite)", configPath)
+		}
+
+		files, err := core.NewCodeProcessor().ScanCodebase(dirPath)
+		if err != nil {
+			log.Fatalf("Failed to scan directory: %v", err)
+		}
+
+		cfg := config.Default()
+		languages := detectLanguages(files)
+		for _, lang := range languages {
+			for _, ext := range lang.extensions {
+				cfg.Include = append(cfg.Include, "**/*"+ext)
+			}
+		}
+
+		if useMemgraph {
+			cfg.Memgraph = &config.MemgraphConfig{
+				URI:  db.MemgraphSetting(memgraphURI, "MEMGRAPH_URI", "bolt://localhost:7687"),
+				User: db.MemgraphSetting(memgraphUser, "MEMGRAPH_USER", ""),
+				TLS:  memgraphTLS,
+			}
+		}
+
+		if err := cfg.Save(configPath); err != nil {
+			log.Fatalf("Failed to write config: %v", err)
+		}
+
+		fmt.Printf("✅ Created %s\n", configPath)
+		if len(languages) == 0 {
+			fmt.Println("⚠️  No supported source files found")
+		} else {
+			fmt.Println("\n🗂️  Detected languages:")
+			for _, lang := range languages {
+				fmt.Printf("  - %s (%d files)\n", lang.name, lang.files)
+			}
+		}
+
+		fmt.Println("\n🚀 Next steps:")
+		fmt.Println("  1. Review the files that will be analyzed:")
+		fmt.Printf("     codegraphgen codebase %s --dry-run\n", dirPath)
+		fmt.Println("  2. Build the knowledge graph:")
+		if useMemgraph {
+			fmt.Printf("     codegraphgen codebase %s --memgraph\n", dirPath)
+		} else {
+			fmt.Printf("     codegraphgen codebase %s\n", dirPath)
+		}
+		fmt.Println("  3. Explore it through the REST API:")
+		fmt.Println("     codegraphgen server")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().BoolVar(&forceInit, "force", false, "Overwrite an existing config file")
+}
+
+// detectedLanguage summarizes the files of one language found in a project
+type detectedLanguage struct {
+	name       string
+	extensions []string
+	files      int
+}
+
+// detectLanguages groups scanned files by language, most common language first
+func detectLanguages(files []graph.CodeFile) []detectedLanguage {
+	byName := make(map[string]*detectedLanguage)
+	seenExt := make(map[string]bool)
+	for _, file := range files {
+		if file.Language == "unknown" {
+			continue
+		}
+		lang, ok := byName[file.Language]
+		if !ok {
+			lang = &detectedLanguage{name: file.Language}
+			byName[file.Language] = lang
+		}
+		lang.files++
+		if !seenExt[file.Extension] {
+			seenExt[file.Extension] = true
+			lang.extensions = append(lang.extensions, file.Extension)
+		}
+	}
+
+	languages := make([]detectedLanguage, 0, len(byName))
+	for _, lang := range byName {
+		sort.Strings(lang.extensions)
+		languages = append(languages, *lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].files != languages[j].files {
+			return languages[i].files > languages[j].files
+		}
+		return languages[i].name < languages[j].name
+	})
+	return languages
+}
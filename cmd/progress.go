@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"codegraphgen/internal/core/graph"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+const (
+	// progressBarWidth is the number of cells of the progress bar
+	progressBarWidth = 30
+
+	// progressFileWidth is the number of characters of the current file shown
+	// next to the progress bar; longer paths keep their end
+	progressFileWidth = 40
+
+	// progressLogInterval is the number of files between two progress lines
+	// when the output is not a terminal
+	progressLogInterval = 10
+
+	// progressRedrawInterval is the minimum time between two redraws of the bar
+	progressRedrawInterval = 65 * time.Millisecond
+)
+
+// analysisProgress reports the progress of a codebase analysis, as a progress
+// bar on a terminal and as a log line every progressLogInterval files
+// otherwise. Its Start and Update methods match core.StartFunc and
+// core.ProgressFunc.
+type analysisProgress struct {
+	out      io.Writer
+	terminal bool
+	bar      *progressbar.ProgressBar
+	total    int
+	files    int
+	entities int
+	start    time.Time
+}
+
+// newAnalysisProgress creates a progress reporter drawing a bar on out when
+// terminal is set
+func newAnalysisProgress(out io.Writer, terminal bool) *analysisProgress {
+	return &analysisProgress{out: out, terminal: terminal}
+}
+
+// Start resets the progress for the analysis of total files
+func (p *analysisProgress) Start(total int) {
+	p.Finish()
+	p.total = total
+	p.files = 0
+	p.entities = 0
+	p.start = time.Now()
+
+	if p.terminal {
+		out := p.out
+		p.bar = progressbar.NewOptions(total,
+			progressbar.OptionSetWriter(out),
+			progressbar.OptionSetWidth(progressBarWidth),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetElapsedTime(true),
+			progressbar.OptionSetPredictTime(true),
+			progressbar.OptionShowDescriptionAtLineEnd(),
+			progressbar.OptionThrottle(progressRedrawInterval),
+			progressbar.OptionOnCompletion(func() { fmt.Fprintln(out) }),
+		)
+	}
+}
+
+// Update records that a file has been analyzed
+func (p *analysisProgress) Update(file graph.CodeFile, entities []graph.Entity, relationships []graph.Relationship) {
+	p.files++
+	p.entities += len(entities)
+
+	if p.bar != nil {
+		p.bar.Describe(fmt.Sprintf("%d entities | %s", p.entities, truncatePath(file.Path, progressFileWidth)))
+		p.bar.Add(1)
+		return
+	}
+
+	if p.files%progressLogInterval == 0 || p.files == p.total {
+		elapsed := time.Since(p.start)
+		log.Printf("📄 Analyzed %d/%d files, %d entities (%s elapsed, ETA %s)",
+			p.files, p.total, p.entities, elapsed.Round(time.Second), p.eta(elapsed))
+	}
+}
+
+// Finish ends the progress bar, if one is drawn
+func (p *analysisProgress) Finish() {
+	if p.bar != nil && p.files > 0 && !p.bar.IsFinished() {
+		p.bar.Exit()
+	}
+	p.bar = nil
+}
+
+// eta estimates the time left from the average time per file so far
+func (p *analysisProgress) eta(elapsed time.Duration) time.Duration {
+	if p.files == 0 || p.files >= p.total {
+		return 0
+	}
+	perFile := elapsed / time.Duration(p.files)
+	return (perFile * time.Duration(p.total-p.files)).Round(time.Second)
+}
+
+// truncatePath shortens a path to width characters by replacing its start
+// with an ellipsis
+func truncatePath(path string, width int) string {
+	runes := []rune(path)
+	if len(runes) <= width {
+		return path
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
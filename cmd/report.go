@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/spf13/cobra"
+)
+
+var reportFormat string
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [directory]",
+	Short: "Report the languages of a codebase",
+	Long: `Analyze a codebase directory and print per language the number of files,
+their total lines, the number of entities found in them and their most common
+entity types. No database is needed.
+
+Examples:
+  codegraphgen report .
+  codegraphgen report ./my-project --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dirPath := args[0]
+
+		switch reportFormat {
+		case "table", "json":
+		default:
+			log.Fatalf("Unsupported format %q: expected table or json", reportFormat)
+		}
+
+		// Only the report goes to stdout
+		kg, err := analyzeCodebase(newProjectCodeProcessor(dirPath), dirPath, os.Stderr)
+		if err != nil {
+			log.Fatalf("Failed to analyze codebase: %v", err)
+		}
+
+		result := analysis.AnalyzeLanguages(kg.Entities)
+		if reportFormat == "json" {
+			printJSON(result)
+			return
+		}
+
+		fmt.Printf("📊 %d files, %d lines\n\n", result.TotalFiles, result.TotalLines)
+		printLanguageReports(result.LanguageReports)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportFormat, "format", "table", "Output format (table, json)")
+}
+
+// printLanguageReports prints the statistics of each language as a table
+func printLanguageReports(reports []graph.LanguageReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tFILES\tLINES\tENTITIES\tTOP ENTITY TYPES")
+	for _, report := range reports {
+		topTypes := make([]string, 0, len(report.TopEntityTypes))
+		for _, entityType := range report.TopEntityTypes {
+			topTypes = append(topTypes, fmt.Sprintf("%s (%d)", entityType.Type, entityType.Count))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n",
+			report.Language, report.Files, report.Lines, report.Entities, strings.Join(topTypes, ", "))
+	}
+	w.Flush()
+}
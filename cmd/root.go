@@ -2,14 +2,33 @@ package cmd
 
 import (
 	"os"
+	"time"
+
+	"codegraphgen/db"
+	"codegraphgen/internal/core"
 
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Global flags
-	useMemgraph bool
-	verbose     bool
+	useMemgraph         bool
+	verbose             bool
+	confidenceThreshold float64
+	postgresDSN         string
+	batchSize           int
+	queryTimeout        time.Duration
+	mergeStrategy       string
+
+	// Memgraph connection flags
+	memgraphURI           string
+	memgraphUser          string
+	memgraphPassword      string
+	memgraphTLS           bool
+	memgraphTLSCA         string
+	memgraphTLSCert       string
+	memgraphTLSKey        string
+	memgraphTLSSkipVerify bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -23,9 +42,13 @@ Examples:
   codegraphgen server
   codegraphgen codebase ./my-project
   codegraphgen codebase . --memgraph
+  codegraphgen codebase . --postgres postgres://localhost:5432/codegraph
   codegraphgen text "your text here"
   codegraphgen file ./document.txt
-  codegraphgen stats`,
+  codegraphgen stats
+  codegraphgen get <entity-id> --memgraph
+  codegraphgen compare ./v1 ./v2
+  codegraphgen export . --format jsonld`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -43,5 +66,20 @@ func Execute() {
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVar(&useMemgraph, "memgraph", false, "Use Memgraph database instead of in-memory")
+	rootCmd.PersistentFlags().StringVar(&postgresDSN, "postgres", "", "Use the PostgreSQL database at this connection string instead of in-memory")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().IntVar(&batchSize, "batch-size", core.DefaultBatchSize, "Number of entities or relationships written to Memgraph per batch")
+	rootCmd.PersistentFlags().DurationVar(&queryTimeout, "query-timeout", db.DefaultQueryTimeout, "Maximum duration of a Memgraph or PostgreSQL query or write batch (0 disables)")
+	rootCmd.PersistentFlags().StringVar(&mergeStrategy, "merge-strategy", string(db.MergeProperties), "How entities found again are merged with the stored entity (merge-properties, keep-higher-confidence, keep-newer, replace-all)")
+	rootCmd.PersistentFlags().Float64Var(&confidenceThreshold, "confidence-threshold", 0, "Drop entities and relationships with a confidence below this value (0-1)")
+
+	// Memgraph connection flags (fall back to MEMGRAPH_URI, MEMGRAPH_USER and MEMGRAPH_PASSWORD)
+	rootCmd.PersistentFlags().StringVar(&memgraphURI, "memgraph-uri", "", "Memgraph Bolt URI (default bolt://localhost:7687, env MEMGRAPH_URI)")
+	rootCmd.PersistentFlags().StringVar(&memgraphUser, "memgraph-user", "", "Memgraph username (env MEMGRAPH_USER)")
+	rootCmd.PersistentFlags().StringVar(&memgraphPassword, "memgraph-password", "", "Memgraph password (env MEMGRAPH_PASSWORD)")
+	rootCmd.PersistentFlags().BoolVar(&memgraphTLS, "memgraph-tls", false, "Use TLS for the Memgraph connection")
+	rootCmd.PersistentFlags().StringVar(&memgraphTLSCA, "memgraph-tls-ca", "", "CA certificate file used to verify the Memgraph server")
+	rootCmd.PersistentFlags().StringVar(&memgraphTLSCert, "memgraph-tls-cert", "", "Client certificate file for Memgraph TLS")
+	rootCmd.PersistentFlags().StringVar(&memgraphTLSKey, "memgraph-tls-key", "", "Client key file for Memgraph TLS")
+	rootCmd.PersistentFlags().BoolVar(&memgraphTLSSkipVerify, "memgraph-tls-skip-verify", false, "Accept any Memgraph server certificate, e.g. a self-signed one (insecure)")
 }
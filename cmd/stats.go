@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"codegraphgen/db"
 	"codegraphgen/internal/core"
 
 	"github.com/spf13/cobra"
 )
 
+var statsFormat string
+
 // statsCmd represents the stats command
 var statsCmd = &cobra.Command{
 	Use:   "stats",
@@ -19,8 +21,16 @@ This shows information about entities, relationships, and their types.
 
 Examples:
   codegraphgen stats
-  codegraphgen stats --memgraph`,
+  codegraphgen stats --memgraph
+  codegraphgen stats --memgraph --format json
+  codegraphgen stats --memgraph --format csv > stats.csv`,
 	Run: func(cmd *cobra.Command, args []string) {
+		switch statsFormat {
+		case "table", "json", "csv":
+		default:
+			log.Fatalf("Unsupported format %q: expected table, json or csv", statsFormat)
+		}
+
 		if verbose {
 			fmt.Println("📊 Getting knowledge graph statistics")
 		}
@@ -28,32 +38,32 @@ Examples:
 		// Initialize components
 		textProcessor := core.NewTextProcessor()
 
-		var database db.DatabaseConnection
-		if useMemgraph {
-			memgraphDB := db.NewMemgraphDatabase("bolt://localhost:7687", "", "")
-			if err := memgraphDB.Connect(); err != nil {
-				log.Fatalf("Failed to connect to Memgraph: %v", err)
-			}
-			database = memgraphDB
-			defer memgraphDB.Disconnect()
-		} else {
-			database = db.NewInMemoryDatabase()
-			if err := database.Connect(); err != nil {
-				log.Fatalf("Failed to connect to in-memory database: %v", err)
-			}
-		}
+		database := openDatabase()
+		defer database.Disconnect()
 
 		generator := core.NewKnowledgeGraphGenerator(textProcessor, database)
 
-		stats, err := generator.GetGraphStatistics()
+		ctx := context.Background()
+
+		stats, err := generator.GetGraphStatistics(ctx)
 		if err != nil {
 			log.Fatalf("Failed to get statistics: %v", err)
 		}
 
-		printStats(stats)
+		switch statsFormat {
+		case "json":
+			printJSON(stats)
+		case "csv":
+			if err := printStatsCSV(stats); err != nil {
+				log.Fatalf("Failed to print statistics: %v", err)
+			}
+		default:
+			printStats(stats)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().StringVar(&statsFormat, "format", "table", "Output format (table, json, csv)")
 }
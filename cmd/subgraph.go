@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"codegraphgen/internal/core"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	subgraphDepth  int
+	subgraphFormat string
+)
+
+// subgraphCmd represents the subgraph command
+var subgraphCmd = &cobra.Command{
+	Use:   "subgraph [entity-id]",
+	Short: "Show the neighborhood of an entity",
+	Long: `Show the ego network of an entity: every entity reachable within --depth hops
+and the relationships between them.
+
+The in-memory database does not persist between runs, so this command is
+most useful against a graph stored in Memgraph.
+
+Examples:
+  codegraphgen subgraph <entity-id> --memgraph
+  codegraphgen subgraph <entity-id> --depth 3 --memgraph --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		entityID := args[0]
+
+		if verbose {
+			fmt.Printf("🕸️ Extracting subgraph around %s (depth %d)\n", entityID, subgraphDepth)
+		}
+
+		database := openDatabase()
+		defer database.Disconnect()
+
+		generator := core.NewKnowledgeGraphGenerator(core.NewTextProcessor(), database)
+
+		ctx := context.Background()
+
+		kg, err := generator.GetSubgraph(ctx, entityID, subgraphDepth)
+		if err != nil {
+			log.Fatalf("Failed to get subgraph: %v", err)
+		}
+
+		if len(kg.Entities) == 0 {
+			log.Fatalf("Entity %s not found", entityID)
+		}
+
+		switch subgraphFormat {
+		case "json":
+			printJSON(kg)
+		case "table":
+			printKnowledgeGraph(kg)
+		default:
+			log.Fatalf("Unsupported format %q: expected table or json", subgraphFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(subgraphCmd)
+	subgraphCmd.Flags().IntVar(&subgraphDepth, "depth", 2, "Number of hops to follow from the entity")
+	subgraphCmd.Flags().StringVar(&subgraphFormat, "format", "table", "Output format (table, json)")
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 
-	"codegraphgen/db"
 	"codegraphgen/internal/core"
 
 	"github.com/spf13/cobra"
@@ -31,20 +30,8 @@ Examples:
 		// Initialize components
 		textProcessor := core.NewTextProcessor()
 
-		var database db.DatabaseConnection
-		if useMemgraph {
-			memgraphDB := db.NewMemgraphDatabase("bolt://localhost:7687", "", "")
-			if err := memgraphDB.Connect(); err != nil {
-				log.Fatalf("Failed to connect to Memgraph: %v", err)
-			}
-			database = memgraphDB
-			defer memgraphDB.Disconnect()
-		} else {
-			database = db.NewInMemoryDatabase()
-			if err := database.Connect(); err != nil {
-				log.Fatalf("Failed to connect to in-memory database: %v", err)
-			}
-		}
+		database := openDatabase()
+		defer database.Disconnect()
 
 		generator := core.NewKnowledgeGraphGenerator(textProcessor, database)
 
@@ -52,6 +39,7 @@ Examples:
 		if err != nil {
 			log.Fatalf("Failed to generate knowledge graph: %v", err)
 		}
+		kg = kg.FilterByConfidence(confidenceThreshold)
 
 		printKnowledgeGraph(kg)
 	},
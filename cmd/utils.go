@@ -1,24 +1,127 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
 
+	"codegraphgen/db"
 	"codegraphgen/internal/core"
 	"codegraphgen/internal/core/graph"
 )
 
+// newMemgraphDatabase creates a Memgraph database configured by the global connection flags
+func newMemgraphDatabase() (*db.MemgraphDatabase, error) {
+	memgraphDB := db.NewMemgraphDatabase(
+		db.MemgraphSetting(memgraphURI, "MEMGRAPH_URI", "bolt://localhost:7687"),
+		db.MemgraphSetting(memgraphUser, "MEMGRAPH_USER", ""),
+		db.MemgraphSetting(memgraphPassword, "MEMGRAPH_PASSWORD", ""),
+	)
+
+	tlsConfig, err := memgraphTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	memgraphDB.Encrypted = memgraphTLS
+	memgraphDB.SkipVerify = memgraphTLSSkipVerify
+	memgraphDB.TLSConfig = tlsConfig
+
+	return memgraphDB, nil
+}
+
+// memgraphTLSConfig loads the TLS configuration requested by the --memgraph-tls* flags
+func memgraphTLSConfig() (*tls.Config, error) {
+	if !memgraphTLS {
+		if memgraphTLSSkipVerify {
+			return nil, fmt.Errorf("--memgraph-tls-skip-verify requires --memgraph-tls")
+		}
+		return nil, nil
+	}
+	tlsConfig, err := db.LoadTLSConfig(memgraphTLSCA, memgraphTLSCert, memgraphTLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure Memgraph TLS: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// openDatabase connects to the database selected by the global flags
+func openDatabase() db.DatabaseConnection {
+	checkDatabaseFlags()
+	strategy := databaseMergeStrategy()
+
+	var database db.DatabaseConnection
+	switch {
+	case postgresDSN != "":
+		postgresDB := db.NewPostgresDatabase(postgresDSN).WithMergeStrategy(strategy).WithQueryTimeout(queryTimeout)
+		if err := postgresDB.Connect(); err != nil {
+			log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+		}
+		database = postgresDB
+	case useMemgraph:
+		memgraphDB, err := newMemgraphDatabase()
+		if err != nil {
+			log.Fatalf("Failed to configure Memgraph: %v", err)
+		}
+		memgraphDB.WithMergeStrategy(strategy).WithQueryTimeout(queryTimeout)
+		if err := memgraphDB.Connect(); err != nil {
+			log.Fatalf("Failed to connect to Memgraph: %v", err)
+		}
+		database = memgraphDB
+	default:
+		database = db.NewInMemoryDatabase().WithMergeStrategy(strategy)
+		if err := database.Connect(); err != nil {
+			log.Fatalf("Failed to connect to in-memory database: %v", err)
+		}
+	}
+	return database
+}
+
+// checkDatabaseFlags rejects selecting more than one database backend
+func checkDatabaseFlags() {
+	if useMemgraph && postgresDSN != "" {
+		log.Fatalf("--memgraph and --postgres cannot be combined")
+	}
+}
+
+// databaseMergeStrategy returns the merge strategy selected by --merge-strategy
+func databaseMergeStrategy() db.MergeStrategy {
+	strategy, err := db.ParseMergeStrategy(mergeStrategy)
+	if err != nil {
+		log.Fatalf("Invalid --merge-strategy: %v", err)
+	}
+	return strategy
+}
+
+// databaseDescription describes the database backend selected by the global flags
+func databaseDescription() string {
+	switch {
+	case postgresDSN != "":
+		return "🐘 Using PostgreSQL database"
+	case useMemgraph:
+		return "🔗 Using Memgraph database"
+	default:
+		return "🧠 Using in-memory database"
+	}
+}
+
 // analyzeCodebase analyzes a codebase directory and returns a knowledge graph
-func analyzeCodebase(processor *core.CodeProcessor, dirPath string) (*graph.KnowledgeGraph, error) {
-	fmt.Printf("🔍 Analyzing codebase at: %s\n", dirPath)
+func analyzeCodebase(processor *core.CodeProcessor, dirPath string, out io.Writer) (*graph.KnowledgeGraph, error) {
+	processor.Output = out
+	fmt.Fprintf(out, "🔍 Analyzing codebase at: %s\n", dirPath)
 
 	entities, relationships, err := processor.AnalyzeCodebase(dirPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process directory: %w", err)
 	}
 
-	fmt.Printf("✅ Found %d entities and %d relationships\n", len(entities), len(relationships))
+	fmt.Fprintf(out, "✅ Found %d entities and %d relationships\n", len(entities), len(relationships))
 
 	return &graph.KnowledgeGraph{
 		Entities:      entities,
@@ -86,20 +189,82 @@ func printKnowledgeGraph(kg *graph.KnowledgeGraph) {
 }
 
 // printStats prints knowledge graph statistics
+// as aligned tables, with types and languages sorted alphabetically
 func printStats(stats *graph.GraphStatistics) {
 	fmt.Println("\n📊 Knowledge Graph Statistics:")
-	fmt.Printf("Total Entities: %d\n", stats.TotalEntities)
-	fmt.Printf("Total Relationships: %d\n", stats.TotalRelationships)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Total Entities:\t%d\n", stats.TotalEntities)
+	fmt.Fprintf(w, "Total Relationships:\t%d\n", stats.TotalRelationships)
+	fmt.Fprintf(w, "Average Degree:\t%.2f\n", stats.AverageDegree)
+	fmt.Fprintf(w, "Density:\t%.4f\n", stats.Density)
+	fmt.Fprintf(w, "Largest Connected Component:\t%d\n", stats.LargestConnectedComponentSize)
+	fmt.Fprintf(w, "Isolated Entities:\t%d\n", stats.IsolatedEntityCount)
+	fmt.Fprintf(w, "Max In-Degree:\t%d\n", stats.MaxInDegree)
+	fmt.Fprintf(w, "Max Out-Degree:\t%d\n", stats.MaxOutDegree)
+	w.Flush()
 
-	fmt.Println("\nEntities by Type:")
-	for entityType, count := range stats.EntitiesByType {
-		fmt.Printf("  %s: %d\n", entityType, count)
+	printCountTable("Entities by Type", stats.EntitiesByType)
+	printCountTable("Relationships by Type", stats.RelationshipsByType)
+	printCountTable("Entities by Language", stats.EntitiesByLanguage)
+
+	if len(stats.Languages) > 0 {
+		fmt.Println("\nLanguages:")
+		printLanguageReports(stats.Languages)
 	}
+}
 
-	fmt.Println("\nRelationships by Type:")
-	for relType, count := range stats.RelationshipsByType {
-		fmt.Printf("  %s: %d\n", relType, count)
+// printCountTable prints one section of statistics as an aligned table sorted by name
+func printCountTable(title string, counts map[string]int) {
+	fmt.Printf("\n%s:\n", title)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range sortedKeys(counts) {
+		fmt.Fprintf(w, "  %s\t%d\n", name, counts[name])
+	}
+	w.Flush()
+}
+
+// printStatsCSV prints knowledge graph statistics as CSV rows of category, name and count
+func printStatsCSV(stats *graph.GraphStatistics) error {
+	w := csv.NewWriter(os.Stdout)
+	rows := [][]string{
+		{"category", "name", "count"},
+		{"total", "entities", strconv.Itoa(stats.TotalEntities)},
+		{"total", "relationships", strconv.Itoa(stats.TotalRelationships)},
+		{"connectivity", "averageDegree", strconv.FormatFloat(stats.AverageDegree, 'f', -1, 64)},
+		{"connectivity", "density", strconv.FormatFloat(stats.Density, 'f', -1, 64)},
+		{"connectivity", "largestConnectedComponentSize", strconv.Itoa(stats.LargestConnectedComponentSize)},
+		{"connectivity", "isolatedEntityCount", strconv.Itoa(stats.IsolatedEntityCount)},
+		{"connectivity", "maxInDegree", strconv.Itoa(stats.MaxInDegree)},
+		{"connectivity", "maxOutDegree", strconv.Itoa(stats.MaxOutDegree)},
+	}
+	sections := []struct {
+		category string
+		counts   map[string]int
+	}{
+		{"entityType", stats.EntitiesByType},
+		{"relationshipType", stats.RelationshipsByType},
+		{"language", stats.EntitiesByLanguage},
+	}
+	for _, section := range sections {
+		for _, name := range sortedKeys(section.counts) {
+			rows = append(rows, []string{section.category, name, strconv.Itoa(section.counts[name])})
+		}
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a count map in alphabetical order
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
 }
 
 // Helper function to pretty print JSON
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/core"
+
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [directory]",
+	Short: "Check the consistency of the knowledge graph",
+	Long: `Check the knowledge graph stored in the database for consistency:
+relationships whose source or target entity does not exist, entities without a
+label, IDs shared by entities of different types and file entities whose path
+does not exist on disk. With a directory, the graph of that codebase is
+analyzed and checked instead, without storing it.
+
+Labels shared by entities of the same type with different IDs, e.g. functions
+of the same name in different files, are listed as informational findings.
+
+Exits with status 1 if any issue is found; informational findings are not
+issues.
+
+Examples:
+  codegraphgen validate --memgraph
+  codegraphgen validate ./my-project`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var report *analysis.ValidationReport
+		if len(args) == 1 {
+			// Only the report goes to stdout
+			kg, err := analyzeCodebase(newProjectCodeProcessor(args[0]), args[0], os.Stderr)
+			if err != nil {
+				log.Fatalf("Failed to analyze codebase: %v", err)
+			}
+			report = analysis.ValidateGraph(kg)
+		} else {
+			database := openDatabase()
+			defer database.Disconnect()
+
+			generator := core.NewKnowledgeGraphGenerator(core.NewTextProcessor(), database)
+
+			ctx := context.Background()
+
+			var err error
+			report, err = generator.ValidateGraph(ctx)
+			if err != nil {
+				log.Fatalf("Failed to validate graph: %v", err)
+			}
+		}
+
+		printValidationReport(report)
+
+		if issues := report.Issues(); issues > 0 {
+			fmt.Printf("\n❌ Found %d issues\n", issues)
+			os.Exit(1)
+		}
+		fmt.Println("\n✅ The knowledge graph is consistent")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+// printValidationReport prints the issue count and sample IDs of every check
+func printValidationReport(report *analysis.ValidationReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tISSUES\tSAMPLES")
+	for _, check := range report.Checks {
+		name := check.Name
+		if check.Informational {
+			name += " (info)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", name, check.Count, strings.Join(check.Samples, ", "))
+	}
+	w.Flush()
+}
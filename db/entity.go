@@ -0,0 +1,85 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// entityFieldKeys are the keys of the Entity fields in the map form of an entity
+var entityFieldKeys = map[string]bool{
+	"id":         true,
+	"label":      true,
+	"type":       true,
+	"confidence": true,
+}
+
+// collidingPropertyPrefix is prepended to properties whose key is taken by an
+// Entity field in the map form, e.g. the type property of a struct field
+const collidingPropertyPrefix = "prop_"
+
+// ToMap returns the entity as a flat map with the id, label, type and confidence
+// of the entity next to all of its properties. A property named like one of those
+// fields is kept under the key prefixed with prop_, e.g. prop_type.
+func (e Entity) ToMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(e.Properties)+len(entityFieldKeys))
+	for key, value := range e.Properties {
+		if entityFieldKeys[key] {
+			key = collidingPropertyPrefix + key
+		}
+		m[key] = value
+	}
+	m["id"] = e.ID
+	m["label"] = e.Label
+	m["type"] = string(e.Type)
+	m["confidence"] = e.Confidence
+	return m
+}
+
+// EntityFromMap converts a map created by ToMap back to an Entity. Every key other
+// than id, label, type and confidence becomes a property.
+func EntityFromMap(m map[string]interface{}) (Entity, error) {
+	id, ok := m["id"].(string)
+	if !ok || id == "" {
+		return Entity{}, fmt.Errorf("entity map has no string id")
+	}
+
+	entity := Entity{ID: id, Properties: make(Properties)}
+	if label, ok := m["label"]; ok {
+		if entity.Label, ok = label.(string); !ok {
+			return Entity{}, fmt.Errorf("entity %s: label must be a string, got %T", id, label)
+		}
+	}
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		entity.Type = EntityType(t)
+	case EntityType:
+		entity.Type = t
+	default:
+		return Entity{}, fmt.Errorf("entity %s: type must be a string, got %T", id, t)
+	}
+	switch c := m["confidence"].(type) {
+	case nil:
+	case float64:
+		entity.Confidence = c
+	case float32:
+		entity.Confidence = float64(c)
+	case int:
+		entity.Confidence = float64(c)
+	case int64:
+		entity.Confidence = float64(c)
+	default:
+		return Entity{}, fmt.Errorf("entity %s: confidence must be a number, got %T", id, c)
+	}
+
+	for key, value := range m {
+		if entityFieldKeys[key] {
+			continue
+		}
+		if field := strings.TrimPrefix(key, collidingPropertyPrefix); entityFieldKeys[field] {
+			key = field
+		}
+		entity.Properties[key] = value
+	}
+	return entity, nil
+}
@@ -1,8 +1,11 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -10,6 +13,7 @@ import (
 type InMemoryDatabase struct {
 	entities      map[string]Entity
 	relationships map[string]Relationship
+	mergeStrategy MergeStrategy
 	mutex         sync.RWMutex
 }
 
@@ -18,9 +22,17 @@ func NewInMemoryDatabase() *InMemoryDatabase {
 	return &InMemoryDatabase{
 		entities:      make(map[string]Entity),
 		relationships: make(map[string]Relationship),
+		mergeStrategy: MergeProperties,
 	}
 }
 
+// WithMergeStrategy sets how an entity created again under an existing ID is
+// merged with the stored entity
+func (db *InMemoryDatabase) WithMergeStrategy(strategy MergeStrategy) *InMemoryDatabase {
+	db.mergeStrategy = strategy
+	return db
+}
+
 // Connect establishes a connection (no-op for in-memory)
 func (db *InMemoryDatabase) Connect() error {
 	log.Println("🔗 Connected to in-memory database")
@@ -34,7 +46,11 @@ func (db *InMemoryDatabase) Disconnect() error {
 }
 
 // Query executes a query against the in-memory database
-func (db *InMemoryDatabase) Query(cypher string, parameters Properties) ([]QueryResult, error) {
+func (db *InMemoryDatabase) Query(ctx context.Context, cypher string, parameters Properties) ([]QueryResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	db.mutex.RLock()
 	defer db.mutex.RUnlock()
 
@@ -44,12 +60,12 @@ func (db *InMemoryDatabase) Query(cypher string, parameters Properties) ([]Query
 	if cypher == "MATCH (n) RETURN n" {
 		results := make([]QueryResult, 0, len(db.entities))
 		for _, entity := range db.entities {
-			results = append(results, QueryResult{"n": entity})
+			results = append(results, QueryResult{"n": entity.ToMap()})
 		}
 		return results, nil
 	}
 
-	if cypher == "MATCH (a)-[r]->(b) RETURN a, r, b" {
+	if cypher == "MATCH (a)-[r]->(b) RETURN a, r, b" || cypher == "MATCH (a)-[r]->(b) RETURN r" {
 		results := make([]QueryResult, 0, len(db.relationships))
 		for _, rel := range db.relationships {
 			sourceEntity, sourceExists := db.entities[rel.Source]
@@ -57,9 +73,9 @@ func (db *InMemoryDatabase) Query(cypher string, parameters Properties) ([]Query
 
 			if sourceExists && targetExists {
 				result := QueryResult{
-					"a": sourceEntity,
+					"a": sourceEntity.ToMap(),
 					"r": rel,
-					"b": targetEntity,
+					"b": targetEntity.ToMap(),
 				}
 				results = append(results, result)
 			}
@@ -67,6 +83,49 @@ func (db *InMemoryDatabase) Query(cypher string, parameters Properties) ([]Query
 		return results, nil
 	}
 
+	if cypher == "MATCH (n) WHERE n.label CONTAINS $q RETURN n" {
+		q, _ := parameters["q"].(string)
+		results := make([]QueryResult, 0)
+		for _, entity := range db.entities {
+			if strings.Contains(entity.Label, q) {
+				results = append(results, QueryResult{"n": entity.ToMap()})
+			}
+		}
+		return results, nil
+	}
+
+	if cypher == "MATCH (n {label: $label}) RETURN n" {
+		label, _ := parameters["label"].(string)
+		results := make([]QueryResult, 0)
+		for _, entity := range db.entities {
+			if entity.Label == label {
+				results = append(results, QueryResult{"n": entity.ToMap()})
+			}
+		}
+		return results, nil
+	}
+
+	// Neighbors of an entity in either direction
+	if cypher == "MATCH (n {id: $id})-[r]-(m) RETURN r, m" {
+		id, _ := parameters["id"].(string)
+		results := make([]QueryResult, 0)
+		for _, rel := range db.relationships {
+			var otherID string
+			switch id {
+			case rel.Source:
+				otherID = rel.Target
+			case rel.Target:
+				otherID = rel.Source
+			default:
+				continue
+			}
+			if other, exists := db.entities[otherID]; exists {
+				results = append(results, QueryResult{"r": rel, "m": other.ToMap()})
+			}
+		}
+		return results, nil
+	}
+
 	// Handle basic entity type queries
 	if len(cypher) > 12 && cypher[:12] == "MATCH (n:" {
 		// Extract entity type from query like "MATCH (n:CLASS) RETURN n"
@@ -84,7 +143,7 @@ func (db *InMemoryDatabase) Query(cypher string, parameters Properties) ([]Query
 
 			for _, entity := range db.entities {
 				if string(entity.Type) == entityType {
-					results = append(results, QueryResult{"n": entity})
+					results = append(results, QueryResult{"n": entity.ToMap()})
 				}
 			}
 			return results, nil
@@ -130,32 +189,58 @@ func (db *InMemoryDatabase) Query(cypher string, parameters Properties) ([]Query
 		return results, nil
 	}
 
+	if cypher == `
+		MATCH (n)
+		RETURN n.language as lang, count(*) as count
+	` {
+		languageCounts := make(map[string]int)
+		for _, entity := range db.entities {
+			if lang, ok := entity.Properties["language"].(string); ok && lang != "" {
+				languageCounts[lang]++
+			}
+		}
+
+		results := make([]QueryResult, 0, len(languageCounts))
+		for lang, count := range languageCounts {
+			results = append(results, QueryResult{
+				"lang":  lang,
+				"count": count,
+			})
+		}
+		return results, nil
+	}
+
 	log.Printf("⚠️ Unsupported query: %s", cypher)
 	return []QueryResult{}, nil
 }
 
-// CreateEntity creates a new entity in the database
+// ReadQuery executes a query like Query. Queries with clauses that modify the
+// graph fail with ErrWriteQuery.
+func (db *InMemoryDatabase) ReadQuery(ctx context.Context, cypher string, parameters Properties) ([]QueryResult, error) {
+	if err := checkReadQuery(cypher); err != nil {
+		return nil, err
+	}
+	return db.Query(ctx, cypher, parameters)
+}
+
 // CreateEntity creates a new entity or updates an existing one in the database
-func (db *InMemoryDatabase) CreateEntity(entity Entity) error {
+func (db *InMemoryDatabase) CreateEntity(ctx context.Context, entity Entity) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
-	if existingEntity, exists := db.entities[entity.ID]; exists {
-		// Update existing entity - keep higher confidence and merge properties
-		updatedEntity := existingEntity
-		updatedEntity.Label = entity.Label
-		if entity.Confidence > existingEntity.Confidence {
-			updatedEntity.Confidence = entity.Confidence
-		}
-
-		// Merge properties
-		if updatedEntity.Properties == nil {
-			updatedEntity.Properties = make(Properties)
-		}
-		for k, v := range entity.Properties {
-			updatedEntity.Properties[k] = v
-		}
+	db.storeEntity(entity)
+	return nil
+}
 
+// storeEntity creates or updates an entity. The caller holds the write lock.
+func (db *InMemoryDatabase) storeEntity(entity Entity) {
+	if existingEntity, exists := db.entities[entity.ID]; exists {
+		// Update existing entity according to the merge strategy
+		updatedEntity := mergeEntity(existingEntity, entity, db.mergeStrategy)
 		db.entities[entity.ID] = updatedEntity
 		log.Printf("🔄 Updated entity: %s (%s)", updatedEntity.Label, updatedEntity.Type)
 	} else {
@@ -163,14 +248,23 @@ func (db *InMemoryDatabase) CreateEntity(entity Entity) error {
 		db.entities[entity.ID] = entity
 		log.Printf("✅ Created entity: %s (%s)", entity.Label, entity.Type)
 	}
-	return nil
 }
 
 // CreateRelationship creates a new relationship or updates an existing one in the database
-func (db *InMemoryDatabase) CreateRelationship(relationship Relationship) error {
+func (db *InMemoryDatabase) CreateRelationship(ctx context.Context, relationship Relationship) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
+	return db.storeRelationship(relationship)
+}
+
+// storeRelationship creates or updates a relationship between stored entities.
+// The caller holds the write lock.
+func (db *InMemoryDatabase) storeRelationship(relationship Relationship) error {
 	// Check if source and target entities exist
 	if _, sourceExists := db.entities[relationship.Source]; !sourceExists {
 		return fmt.Errorf("source entity %s not found", relationship.Source)
@@ -223,9 +317,9 @@ func (db *InMemoryDatabase) CreateRelationship(relationship Relationship) error
 }
 
 // CreateEntities creates multiple entities in batch
-func (db *InMemoryDatabase) CreateEntities(entities []Entity) error {
+func (db *InMemoryDatabase) CreateEntities(ctx context.Context, entities []Entity) error {
 	for _, entity := range entities {
-		if err := db.CreateEntity(entity); err != nil {
+		if err := db.CreateEntity(ctx, entity); err != nil {
 			return err
 		}
 	}
@@ -233,9 +327,9 @@ func (db *InMemoryDatabase) CreateEntities(entities []Entity) error {
 }
 
 // CreateRelationships creates multiple relationships in batch
-func (db *InMemoryDatabase) CreateRelationships(relationships []Relationship) error {
+func (db *InMemoryDatabase) CreateRelationships(ctx context.Context, relationships []Relationship) error {
 	for _, relationship := range relationships {
-		if err := db.CreateRelationship(relationship); err != nil {
+		if err := db.CreateRelationship(ctx, relationship); err != nil {
 			return err
 		}
 	}
@@ -243,14 +337,317 @@ func (db *InMemoryDatabase) CreateRelationships(relationships []Relationship) er
 }
 
 // GetEntityByID returns an entity by its ID
-func (db *InMemoryDatabase) GetEntityByID(id string) (*Entity, error) {
+func (db *InMemoryDatabase) GetEntityByID(ctx context.Context, id string) (*Entity, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	db.mutex.RLock()
 	defer db.mutex.RUnlock()
 
 	if entity, exists := db.entities[id]; exists {
 		return &entity, nil
 	}
-	return nil, fmt.Errorf("entity not found")
+	return nil, fmt.Errorf("entity %s: %w", id, ErrNotFound)
+}
+
+// GetEntityByLabel returns the entities with exactly the given label.
+// An empty entityType matches entities of any type.
+func (db *InMemoryDatabase) GetEntityByLabel(ctx context.Context, label string, entityType EntityType) ([]Entity, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	entities := make([]Entity, 0)
+	for _, entity := range db.entities {
+		if entity.Label == label && (entityType == "" || entity.Type == entityType) {
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}
+
+// GetRelationshipsByEntityID returns the relationships of an entity in the given direction
+// ("in", "out" or "both")
+func (db *InMemoryDatabase) GetRelationshipsByEntityID(ctx context.Context, id string, direction string) ([]Relationship, error) {
+	if direction != DirectionIn && direction != DirectionOut && direction != DirectionBoth {
+		return nil, fmt.Errorf("invalid direction %q: expected in, out or both", direction)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	relationships := make([]Relationship, 0)
+	for _, rel := range db.relationships {
+		outgoing := rel.Source == id && direction != DirectionIn
+		incoming := rel.Target == id && direction != DirectionOut
+		if outgoing || incoming {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships, nil
+}
+
+// GetEntitiesByType returns the entities of the given type
+func (db *InMemoryDatabase) GetEntitiesByType(ctx context.Context, entityType EntityType) ([]Entity, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	entities := make([]Entity, 0)
+	for _, entity := range db.entities {
+		if entity.Type == entityType {
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}
+
+// GetRelationshipsByType returns the relationships of the given type
+func (db *InMemoryDatabase) GetRelationshipsByType(ctx context.Context, relType RelationshipType) ([]Relationship, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	relationships := make([]Relationship, 0)
+	for _, rel := range db.relationships {
+		if rel.Type == relType {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships, nil
+}
+
+// GetRelationships returns the stored relationships with a confidence of at
+// least minConfidence
+func (db *InMemoryDatabase) GetRelationships(ctx context.Context, minConfidence float64) ([]Relationship, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	relationships := make([]Relationship, 0, len(db.relationships))
+	for _, rel := range db.relationships {
+		if rel.Confidence >= minConfidence {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships, nil
+}
+
+// GetDegreeStatistics counts the relationships of each entity. Relationships
+// whose endpoints aren't stored are ignored.
+func (db *InMemoryDatabase) GetDegreeStatistics(ctx context.Context) (DegreeStatistics, error) {
+	if err := ctx.Err(); err != nil {
+		return DegreeStatistics{}, err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	inDegrees := make(map[string]int)
+	outDegrees := make(map[string]int)
+	for _, rel := range db.relationships {
+		_, sourceExists := db.entities[rel.Source]
+		_, targetExists := db.entities[rel.Target]
+		if sourceExists && targetExists {
+			outDegrees[rel.Source]++
+			inDegrees[rel.Target]++
+		}
+	}
+
+	var stats DegreeStatistics
+	for id := range db.entities {
+		if inDegrees[id]+outDegrees[id] == 0 {
+			stats.IsolatedEntities++
+		}
+		stats.MaxInDegree = max(stats.MaxInDegree, inDegrees[id])
+		stats.MaxOutDegree = max(stats.MaxOutDegree, outDegrees[id])
+	}
+	return stats, nil
+}
+
+// DeleteEntitiesBySourceFile removes the entities extracted from a file, the
+// FILE entity of the file and all of their relationships
+func (db *InMemoryDatabase) DeleteEntitiesBySourceFile(ctx context.Context, filePath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	deleted := db.deleteEntitiesBySourceFile(filePath)
+	log.Printf("🗑️ Deleted %d entities of %s", deleted, filePath)
+	return nil
+}
+
+// deleteEntitiesBySourceFile removes the entities of a file with their
+// relationships and returns the number of entities removed. The caller holds
+// the write lock.
+func (db *InMemoryDatabase) deleteEntitiesBySourceFile(filePath string) int {
+	deleted := make(map[string]bool)
+	for id, entity := range db.entities {
+		if isFromSourceFile(entity, filePath) {
+			deleted[id] = true
+			delete(db.entities, id)
+		}
+	}
+	for id, rel := range db.relationships {
+		if deleted[rel.Source] || deleted[rel.Target] {
+			delete(db.relationships, id)
+		}
+	}
+	return len(deleted)
+}
+
+// DeleteRelationshipsBySourceFile removes the relationships created for a file:
+// those with a sourceFile property of filePath and those starting at an entity
+// extracted from the file or at its FILE entity. The entities are kept.
+func (db *InMemoryDatabase) DeleteRelationshipsBySourceFile(ctx context.Context, filePath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	deleted := db.deleteRelationshipsBySourceFile(filePath)
+	log.Printf("🗑️ Deleted %d relationships of %s", deleted, filePath)
+	return nil
+}
+
+// deleteRelationshipsBySourceFile removes the relationships created for a file
+// and returns their number. The caller holds the write lock.
+func (db *InMemoryDatabase) deleteRelationshipsBySourceFile(filePath string) int {
+	deleted := 0
+	for id, rel := range db.relationships {
+		source, exists := db.entities[rel.Source]
+		if rel.Properties["sourceFile"] == filePath || (exists && isFromSourceFile(source, filePath)) {
+			delete(db.relationships, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// ReplaceSourceFiles removes the entities and relationships of the files and
+// stores entities and relationships in their place under a single lock, so
+// readers never see the files half replaced. Relationships of other files
+// ending at entities of the files are kept and moved to the new entities.
+// Relationships whose endpoints aren't stored are skipped.
+func (db *InMemoryDatabase) ReplaceSourceFiles(ctx context.Context, filePaths []string, entities []Entity, relationships []Relationship) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	incoming, targets := db.incomingRelationships(filePaths)
+	relationships = slices.Concat(relationships, retargetRelationships(incoming, targets, entities))
+
+	for _, filePath := range filePaths {
+		db.deleteRelationshipsBySourceFile(filePath)
+		db.deleteEntitiesBySourceFile(filePath)
+	}
+	for _, entity := range entities {
+		db.storeEntity(entity)
+	}
+	skipped := 0
+	for _, relationship := range relationships {
+		if err := db.storeRelationship(relationship); err != nil {
+			skipped++
+		}
+	}
+
+	log.Printf("🔁 Replaced the graph of %d files (%d relationships skipped)", len(filePaths), skipped)
+	return nil
+}
+
+// incomingRelationships returns the relationships that other files have to
+// entities of the files, and those entities keyed by ID. The caller holds the
+// lock.
+func (db *InMemoryDatabase) incomingRelationships(filePaths []string) ([]Relationship, map[string]Entity) {
+	replaced := make(map[string]Entity)
+	for id, entity := range db.entities {
+		for _, filePath := range filePaths {
+			if isFromSourceFile(entity, filePath) {
+				replaced[id] = entity
+				break
+			}
+		}
+	}
+
+	var incoming []Relationship
+	for _, rel := range db.relationships {
+		_, targetReplaced := replaced[rel.Target]
+		_, sourceReplaced := replaced[rel.Source]
+		sourceFile, _ := rel.Properties["sourceFile"].(string)
+		if targetReplaced && !sourceReplaced && !slices.Contains(filePaths, sourceFile) {
+			incoming = append(incoming, rel)
+		}
+	}
+	return incoming, replaced
+}
+
+// DeleteEntityByID removes an entity and all of its relationships
+func (db *InMemoryDatabase) DeleteEntityByID(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.entities[id]; !exists {
+		return fmt.Errorf("entity %s: %w", id, ErrNotFound)
+	}
+	delete(db.entities, id)
+	for relID, rel := range db.relationships {
+		if rel.Source == id || rel.Target == id {
+			delete(db.relationships, relID)
+		}
+	}
+	return nil
+}
+
+// DeleteRelationshipByID removes a relationship
+func (db *InMemoryDatabase) DeleteRelationshipByID(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.relationships[id]; !exists {
+		return fmt.Errorf("relationship %s: %w", id, ErrNotFound)
+	}
+	delete(db.relationships, id)
+	return nil
+}
+
+// isFromSourceFile reports whether an entity was extracted from filePath or is its FILE entity
+func isFromSourceFile(entity Entity, filePath string) bool {
+	if entity.Type == "FILE" {
+		return entity.Properties["path"] == filePath
+	}
+	return entity.Properties["sourceFile"] == filePath
 }
 
 // GetAllEntities returns all entities
@@ -2,10 +2,19 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
@@ -16,6 +25,35 @@ type MemgraphDatabase struct {
 	uri      string
 	username string
 	password string
+
+	// Encrypted enables TLS for the Bolt connection
+	Encrypted bool
+	// SkipVerify accepts any server certificate, e.g. a self-signed one, on
+	// encrypted connections
+	SkipVerify bool
+	// TLSConfig is an optional TLS configuration (custom CA, client certificates)
+	TLSConfig *tls.Config
+	// BatchThreshold is the minimum number of relationships of one type needed
+	// before CreateRelationships switches from serial creation to an UNWIND batch
+	BatchThreshold int
+
+	mergeStrategy MergeStrategy
+	queryTimeout  time.Duration
+}
+
+// defaultBatchThreshold is the default value of MemgraphDatabase.BatchThreshold
+const defaultBatchThreshold = 20
+
+// MemgraphSetting resolves a Memgraph connection setting: value > environment
+// variable envKey > defaultValue
+func MemgraphSetting(value, envKey, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	if envValue := os.Getenv(envKey); envValue != "" {
+		return envValue
+	}
+	return defaultValue
 }
 
 // NewMemgraphDatabase creates a new Memgraph database connection
@@ -30,12 +68,28 @@ func NewMemgraphDatabase(uri, username, password string) *MemgraphDatabase {
 	}
 
 	return &MemgraphDatabase{
-		uri:      uri,
-		username: username,
-		password: password,
+		uri:            uri,
+		username:       username,
+		password:       password,
+		BatchThreshold: defaultBatchThreshold,
+		mergeStrategy:  MergeProperties,
 	}
 }
 
+// WithMergeStrategy sets how an entity created again under an existing ID is
+// merged with the stored node
+func (db *MemgraphDatabase) WithMergeStrategy(strategy MergeStrategy) *MemgraphDatabase {
+	db.mergeStrategy = strategy
+	return db
+}
+
+// WithQueryTimeout bounds the duration of each query and of each write
+// transaction, such as one batch of CreateEntities. 0 disables the timeout.
+func (db *MemgraphDatabase) WithQueryTimeout(timeout time.Duration) *MemgraphDatabase {
+	db.queryTimeout = timeout
+	return db
+}
+
 // Connect establishes a connection to Memgraph
 func (db *MemgraphDatabase) Connect() error {
 	ctx := context.Background()
@@ -49,13 +103,16 @@ func (db *MemgraphDatabase) Connect() error {
 	}
 
 	// Create driver with Memgraph-optimized configuration
-	driver, err := neo4j.NewDriverWithContext(db.uri, auth, func(c *neo4j.Config) {
+	driver, err := neo4j.NewDriverWithContext(db.connectionURI(), auth, func(c *neo4j.Config) {
 		c.MaxConnectionLifetime = 30 * time.Minute
 		c.MaxConnectionPoolSize = 50
 		c.ConnectionAcquisitionTimeout = 2 * time.Minute
 		c.SocketConnectTimeout = 15 * time.Second
 		c.SocketKeepalive = true
-		// Note: Encryption settings may vary by Neo4j driver version
+		// The TLS configuration is only used by the driver for encrypted URI schemes
+		if db.Encrypted && db.TLSConfig != nil {
+			c.TlsConfig = db.TLSConfig
+		}
 	})
 
 	if err != nil {
@@ -80,6 +137,62 @@ func (db *MemgraphDatabase) Connect() error {
 	return nil
 }
 
+// connectionURI returns the Bolt URI, switching to the encrypted variant of its
+// scheme when TLS is enabled: bolt+s:// or neo4j+s://, which keeps routing, or
+// bolt+ssc:// and neo4j+ssc:// (self-signed certificates) with SkipVerify.
+// The driver verifies certificates by the scheme, not by the TLS configuration.
+func (db *MemgraphDatabase) connectionURI() string {
+	if !db.Encrypted {
+		return db.uri
+	}
+
+	suffix := "+s://"
+	if db.SkipVerify {
+		suffix = "+ssc://"
+	}
+
+	for _, scheme := range []string{"bolt", "neo4j"} {
+		if rest, ok := strings.CutPrefix(db.uri, scheme+"://"); ok {
+			return scheme + suffix + rest
+		}
+	}
+
+	// Already an encrypted scheme (bolt+s://, neo4j+ssc://, ...)
+	return db.uri
+}
+
+// LoadTLSConfig builds a TLS configuration for Memgraph from PEM files.
+// caFile adds a trusted certificate authority; certFile and keyFile configure
+// a client certificate. All arguments are optional.
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("both a client certificate and key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 // Disconnect closes the connection to Memgraph
 func (db *MemgraphDatabase) Disconnect() error {
 	if db.driver != nil {
@@ -95,12 +208,12 @@ func (db *MemgraphDatabase) Disconnect() error {
 }
 
 // Query executes a Cypher query against Memgraph
-func (db *MemgraphDatabase) Query(cypher string, parameters Properties) ([]QueryResult, error) {
+func (db *MemgraphDatabase) Query(ctx context.Context, cypher string, parameters Properties) ([]QueryResult, error) {
 	if db.driver == nil {
 		return nil, fmt.Errorf("database not connected. Call Connect() first")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := WithQueryTimeout(ctx, db.queryTimeout)
 	defer cancel()
 
 	// Convert Properties to map[string]any for Neo4j driver
@@ -124,7 +237,62 @@ func (db *MemgraphDatabase) Query(cypher string, parameters Properties) ([]Query
 		return nil, fmt.Errorf("query execution failed: %w", err)
 	}
 
-	// Process results
+	return db.collectResults(ctx, result)
+}
+
+// ReadQuery executes a Cypher query in a read transaction. Queries with
+// clauses that modify the graph fail with ErrWriteQuery before they are sent,
+// as do queries Memgraph rejects in the read transaction, such as calls of
+// procedures that write.
+func (db *MemgraphDatabase) ReadQuery(ctx context.Context, cypher string, parameters Properties) ([]QueryResult, error) {
+	if db.driver == nil {
+		return nil, fmt.Errorf("database not connected. Call Connect() first")
+	}
+	if err := checkReadQuery(cypher); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := WithQueryTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	session := db.driver.NewSession(ctx, neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: "memgraph",
+	})
+	defer session.Close(ctx)
+
+	results, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, cypher, map[string]any(parameters))
+		if err != nil {
+			return nil, err
+		}
+		return db.collectResults(ctx, result)
+	})
+	if isAccessModeError(err) {
+		return nil, fmt.Errorf("%w: %v", ErrWriteQuery, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read query execution failed: %w", err)
+	}
+	return results.([]QueryResult), nil
+}
+
+// isAccessModeError reports whether Memgraph rejected a query for modifying the
+// graph in a read transaction
+func isAccessModeError(err error) bool {
+	var neo4jErr *neo4j.Neo4jError
+	if !errors.As(err, &neo4jErr) {
+		return false
+	}
+	if neo4jErr.Code == "Neo.ClientError.Statement.AccessMode" {
+		return true
+	}
+	message := strings.ToLower(neo4jErr.Msg)
+	return strings.Contains(message, "write") && (strings.Contains(message, "read") || strings.Contains(message, "forbidden"))
+}
+
+// collectResults converts the records of a query result
+func (db *MemgraphDatabase) collectResults(ctx context.Context, result neo4j.ResultWithContext) ([]QueryResult, error) {
 	var results []QueryResult
 	for result.Next(ctx) {
 		record := result.Record()
@@ -140,7 +308,7 @@ func (db *MemgraphDatabase) Query(cypher string, parameters Properties) ([]Query
 	}
 
 	// Check for any errors during result processing
-	if err = result.Err(); err != nil {
+	if err := result.Err(); err != nil {
 		return nil, fmt.Errorf("error processing query results: %w", err)
 	}
 
@@ -148,48 +316,106 @@ func (db *MemgraphDatabase) Query(cypher string, parameters Properties) ([]Query
 }
 
 // CreateEntity creates a new entity or updates an existing one in Memgraph
-func (db *MemgraphDatabase) CreateEntity(entity Entity) error {
+func (db *MemgraphDatabase) CreateEntity(ctx context.Context, entity Entity) error {
+	cypher, params := db.entityQuery(entity)
+	if _, err := db.Query(ctx, cypher, params); err != nil {
+		return fmt.Errorf("failed to create entity %s: %w", entity.ID, err)
+	}
+	return nil
+}
+
+// entityQuery returns the query creating or updating a single entity
+func (db *MemgraphDatabase) entityQuery(entity Entity) (string, Properties) {
 	// Escape the entity type to handle reserved keywords
 	escapedType := db.escapeLabel(string(entity.Type))
 	// Escape the entity label as well for use as a node label
 	escapedLabel := db.escapeLabel(entity.Label)
 
 	// Enhanced Cypher query for entity creation/update
-	// Use both the entity label and type as node labels for better organization
+	// Use both the entity label and type as node labels for better organization.
+	// Nodes are merged on the type alone so that nodes created by CreateEntities,
+	// which only carry the type label, are matched as well.
 	cypher := fmt.Sprintf(`
-		MERGE (n:%s:%s {id: $id})
-		ON CREATE SET n.label = $label,
-			n.confidence = $confidence,
-			n.created_at = timestamp(),
-			n.updated_at = timestamp()
-		ON MATCH SET n.label = $label,
-			n.confidence = CASE
-				WHEN $confidence > n.confidence THEN $confidence
-				ELSE n.confidence
-			END,
-			n.updated_at = timestamp()
-		SET n += $properties
+		WITH $entity AS entity
+		MERGE (n:%s {id: entity.id})
+		%s
+		SET n:%s
 		RETURN n
-	`, escapedType, escapedLabel)
+	`, escapedType, db.entityMergeClauses(), escapedLabel)
 
-	// Prepare properties
-	params := Properties{
+	return cypher, Properties{"entity": entityParameter(entity)}
+}
+
+// entityParameter returns the query parameter holding an entity
+func entityParameter(entity Entity) map[string]interface{} {
+	return map[string]interface{}{
 		"id":         entity.ID,
 		"label":      entity.Label,
 		"confidence": entity.Confidence,
-		"properties": db.flattenProperties(entity.Properties),
+		"properties": SanitizeProperties(entity.Properties),
 	}
+}
 
-	_, err := db.Query(cypher, params)
-	if err != nil {
-		return fmt.Errorf("failed to create entity %s: %w", entity.ID, err)
+// entityMergeClauses returns the clauses following the MERGE of an entity
+// query, which set the node n from the parameter entity according to the merge
+// strategy
+func (db *MemgraphDatabase) entityMergeClauses() string {
+	switch db.mergeStrategy {
+	case KeepNewer:
+		return `ON CREATE SET n.created_at = timestamp()
+		SET n.label = entity.label,
+			n.confidence = entity.confidence,
+			n.updated_at = timestamp()
+		SET n += entity.properties`
+	case ReplaceAll:
+		return `ON CREATE SET n.created_at = timestamp()
+		WITH n, entity, n.created_at AS createdAt
+		SET n = entity.properties
+		SET n.id = entity.id,
+			n.label = entity.label,
+			n.confidence = entity.confidence,
+			n.created_at = createdAt,
+			n.updated_at = timestamp()`
+	case KeepHigherConfidence:
+		// The stored node is only replaced when the entity's confidence is at
+		// least as high; FOREACH over an empty list skips the update otherwise
+		return `ON CREATE SET n.created_at = timestamp(),
+			n.confidence = entity.confidence
+		WITH n, entity, n.created_at AS createdAt
+		FOREACH (_ IN CASE WHEN entity.confidence >= n.confidence THEN [1] ELSE [] END |
+			SET n = entity.properties
+			SET n.id = entity.id,
+				n.label = entity.label,
+				n.confidence = entity.confidence,
+				n.created_at = createdAt,
+				n.updated_at = timestamp()
+		)`
+	default:
+		return `ON CREATE SET n.label = entity.label,
+			n.confidence = entity.confidence,
+			n.created_at = timestamp(),
+			n.updated_at = timestamp()
+		ON MATCH SET n.label = entity.label,
+			n.confidence = CASE
+				WHEN entity.confidence > n.confidence THEN entity.confidence
+				ELSE n.confidence
+			END,
+			n.updated_at = timestamp()
+		SET n += entity.properties`
 	}
+}
 
+// CreateRelationship creates a new relationship or updates an existing one in Memgraph
+func (db *MemgraphDatabase) CreateRelationship(ctx context.Context, relationship Relationship) error {
+	cypher, params := db.relationshipQuery(relationship)
+	if _, err := db.Query(ctx, cypher, params); err != nil {
+		return fmt.Errorf("failed to create relationship %s: %w", relationship.ID, err)
+	}
 	return nil
 }
 
-// CreateRelationship creates a new relationship or updates an existing one in Memgraph
-func (db *MemgraphDatabase) CreateRelationship(relationship Relationship) error {
+// relationshipQuery returns the query creating or updating a single relationship
+func (db *MemgraphDatabase) relationshipQuery(relationship Relationship) (string, Properties) {
 	// Escape the relationship type to handle reserved keywords
 	escapedType := db.escapeLabel(string(relationship.Type))
 
@@ -219,28 +445,84 @@ func (db *MemgraphDatabase) CreateRelationship(relationship Relationship) error
 		"targetId":   relationship.Target,
 		"id":         relationship.ID,
 		"confidence": relationship.Confidence,
-		"properties": db.flattenProperties(relationship.Properties),
+		"properties": SanitizeProperties(relationship.Properties),
+	}
+
+	return cypher, params
+}
+
+// WithTransaction runs fn in a single explicit write transaction, which is
+// committed when fn succeeds and rolled back when it fails. Transactions that
+// fail with a transient error are retried by the driver.
+func (db *MemgraphDatabase) WithTransaction(ctx context.Context, fn func(tx neo4j.ManagedTransaction) error) error {
+	if db.driver == nil {
+		return fmt.Errorf("database not connected. Call Connect() first")
 	}
 
-	_, err := db.Query(cypher, params)
+	session := db.driver.NewSession(ctx, neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeWrite,
+		DatabaseName: "memgraph",
+	})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return nil, fn(tx)
+	})
+	return err
+}
+
+// runInTransaction runs a write query in a transaction and discards its results
+func runInTransaction(ctx context.Context, tx neo4j.ManagedTransaction, cypher string, parameters Properties) error {
+	result, err := tx.Run(ctx, cypher, map[string]any(parameters))
 	if err != nil {
-		return fmt.Errorf("failed to create relationship %s: %w", relationship.ID, err)
+		return err
 	}
+	_, err = result.Consume(ctx)
+	return err
+}
 
-	return nil
+// queryInTransaction runs a query in a transaction and returns its results
+func (db *MemgraphDatabase) queryInTransaction(ctx context.Context, tx neo4j.ManagedTransaction, cypher string, parameters Properties) ([]QueryResult, error) {
+	result, err := tx.Run(ctx, cypher, map[string]any(parameters))
+	if err != nil {
+		return nil, err
+	}
+	return db.collectResults(ctx, result)
 }
 
-// CreateEntities creates multiple entities in a batch for better performance
-func (db *MemgraphDatabase) CreateEntities(entities []Entity) error {
+// CreateEntities creates or updates multiple entities in one transaction, with
+// one UNWIND query per entity type. Node labels can't be parameterized in
+// Cypher, so unlike CreateEntity the entity label is only stored as the label
+// property. When the transaction fails, the entities are created one by one to
+// find the entity that can't be stored.
+func (db *MemgraphDatabase) CreateEntities(ctx context.Context, entities []Entity) error {
 	if len(entities) == 0 {
 		return nil
 	}
 
-	// For now, use individual creation as it's more reliable
-	// In production, you might want to implement proper batch processing
-	for _, entity := range entities {
-		if err := db.CreateEntity(entity); err != nil {
-			return fmt.Errorf("failed to create entity %s: %w", entity.ID, err)
+	types, byType := groupEntitiesByType(entities)
+
+	ctx, cancel := WithQueryTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	err := db.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
+		for _, entityType := range types {
+			cypher, params := db.entityBatchQuery(entityType, byType[entityType])
+			if err := runInTransaction(ctx, tx, cypher, params); err != nil {
+				return fmt.Errorf("failed to batch create %d %s entities: %w", len(byType[entityType]), entityType, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		log.Printf("⚠️ Batch of %d entities failed, retrying one by one: %v", len(entities), err)
+		for _, entity := range entities {
+			if err := db.CreateEntity(ctx, entity); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -248,22 +530,139 @@ func (db *MemgraphDatabase) CreateEntities(entities []Entity) error {
 	return nil
 }
 
-// CreateRelationships creates multiple relationships in a batch
-func (db *MemgraphDatabase) CreateRelationships(relationships []Relationship) error {
+// groupEntitiesByType groups entities by type, returning the types in the
+// order they first occur
+func groupEntitiesByType(entities []Entity) ([]EntityType, map[EntityType][]Entity) {
+	byType := make(map[EntityType][]Entity)
+	var types []EntityType
+	for _, entity := range entities {
+		if _, exists := byType[entity.Type]; !exists {
+			types = append(types, entity.Type)
+		}
+		byType[entity.Type] = append(byType[entity.Type], entity)
+	}
+	return types, byType
+}
+
+// groupRelationshipsByType groups relationships by type, returning the types
+// in the order they first occur
+func groupRelationshipsByType(relationships []Relationship) ([]RelationshipType, map[RelationshipType][]Relationship) {
+	byType := make(map[RelationshipType][]Relationship)
+	var types []RelationshipType
+	for _, rel := range relationships {
+		if _, exists := byType[rel.Type]; !exists {
+			types = append(types, rel.Type)
+		}
+		byType[rel.Type] = append(byType[rel.Type], rel)
+	}
+	return types, byType
+}
+
+// entityBatchQuery returns the UNWIND query creating or updating entities of a
+// single type
+func (db *MemgraphDatabase) entityBatchQuery(entityType EntityType, entities []Entity) (string, Properties) {
+	cypher := fmt.Sprintf(`
+		UNWIND $entities AS entity
+		MERGE (n:%s {id: entity.id})
+		%s
+	`, db.escapeLabel(string(entityType)), db.entityMergeClauses())
+
+	batch := make([]interface{}, 0, len(entities))
+	for _, entity := range entities {
+		batch = append(batch, entityParameter(entity))
+	}
+
+	return cypher, Properties{"entities": batch}
+}
+
+// CreateRelationships creates multiple relationships in one transaction. Types
+// with at least BatchThreshold relationships are created with an UNWIND query,
+// the others one by one. When the transaction fails, the relationships are
+// created one by one to find the relationship that can't be stored.
+func (db *MemgraphDatabase) CreateRelationships(ctx context.Context, relationships []Relationship) error {
 	if len(relationships) == 0 {
 		return nil
 	}
 
-	// Use individual creation for relationships as UNWIND can be complex with dynamic relationship types
-	for _, rel := range relationships {
-		if err := db.CreateRelationship(rel); err != nil {
-			return fmt.Errorf("failed to create relationship %s: %w", rel.ID, err)
+	// Relationship types can't be parameterized in Cypher, so batch per type
+	types, byType := groupRelationshipsByType(relationships)
+
+	ctx, cancel := WithQueryTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	err := db.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
+		for _, relType := range types {
+			rels := byType[relType]
+
+			if len(rels) >= db.BatchThreshold {
+				cypher, params := db.relationshipBatchQuery(relType, rels)
+				if err := runInTransaction(ctx, tx, cypher, params); err != nil {
+					return fmt.Errorf("failed to batch create %d %s relationships: %w", len(rels), relType, err)
+				}
+				continue
+			}
+
+			// Small groups aren't worth a batch query
+			for _, rel := range rels {
+				cypher, params := db.relationshipQuery(rel)
+				if err := runInTransaction(ctx, tx, cypher, params); err != nil {
+					return fmt.Errorf("failed to create relationship %s: %w", rel.ID, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		log.Printf("⚠️ Batch of %d relationships failed, retrying one by one: %v", len(relationships), err)
+		for _, rel := range relationships {
+			if err := db.CreateRelationship(ctx, rel); err != nil {
+				return err
+			}
 		}
 	}
 
+	log.Printf("✅ Created %d relationships in Memgraph", len(relationships))
 	return nil
 }
 
+// relationshipBatchQuery returns the UNWIND query creating or updating
+// relationships of a single type
+func (db *MemgraphDatabase) relationshipBatchQuery(relType RelationshipType, rels []Relationship) (string, Properties) {
+	cypher := fmt.Sprintf(`
+		UNWIND $rels AS rel
+		MATCH (source {id: rel.source})
+		MATCH (target {id: rel.target})
+		MERGE (source)-[r:%s]->(target)
+		ON CREATE SET r.id = rel.id,
+			r.confidence = rel.confidence,
+			r.created_at = timestamp(),
+			r.updated_at = timestamp()
+		ON MATCH SET r.id = rel.id,
+			r.confidence = CASE
+				WHEN rel.confidence > r.confidence THEN rel.confidence
+				ELSE r.confidence
+			END,
+			r.updated_at = timestamp()
+		SET r += rel.properties
+	`, db.escapeLabel(string(relType)))
+
+	batch := make([]interface{}, 0, len(rels))
+	for _, rel := range rels {
+		batch = append(batch, map[string]interface{}{
+			"source":     rel.Source,
+			"target":     rel.Target,
+			"id":         rel.ID,
+			"confidence": rel.Confidence,
+			"properties": SanitizeProperties(rel.Properties),
+		})
+	}
+
+	return cypher, Properties{"rels": batch}
+}
+
 // checkMemgraphCapabilities checks available Memgraph procedures and capabilities
 func (db *MemgraphDatabase) checkMemgraphCapabilities(ctx context.Context) error {
 	session := db.driver.NewSession(ctx, neo4j.SessionConfig{
@@ -310,92 +709,412 @@ func (db *MemgraphDatabase) convertMemgraphValue(value interface{}) interface{}
 			"nodes":         v.Nodes,
 			"relationships": v.Relationships,
 		}
+	case []interface{}:
+		// Lists such as nodes(path) may contain nodes and relationships
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = db.convertMemgraphValue(item)
+		}
+		return converted
 	default:
 		return value
 	}
 }
 
-// flattenProperties flattens nested properties for Memgraph storage
-func (db *MemgraphDatabase) flattenProperties(props Properties) map[string]interface{} {
-	flattened := make(map[string]interface{})
-	for key, value := range props {
-		// Prefix properties to avoid conflicts with reserved names
-		flattened[fmt.Sprintf("prop_%s", key)] = value
-	}
-	return flattened
-}
-
 // GetEntityByID retrieves an entity by its ID
-func (db *MemgraphDatabase) GetEntityByID(id string) (*Entity, error) {
+func (db *MemgraphDatabase) GetEntityByID(ctx context.Context, id string) (*Entity, error) {
 	cypher := "MATCH (n {id: $id}) RETURN n"
 	params := Properties{"id": id}
 
-	results, err := db.Query(cypher, params)
+	results, err := db.Query(ctx, cypher, params)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("entity not found: %s", id)
+		return nil, fmt.Errorf("entity %s: %w", id, ErrNotFound)
+	}
+
+	if nodeData, ok := results[0]["n"].(map[string]interface{}); ok {
+		entity := entityFromNode(nodeData)
+		return &entity, nil
+	}
+
+	return nil, fmt.Errorf("invalid entity format")
+}
+
+// GetEntityByLabel retrieves the entities with exactly the given label.
+// An empty entityType matches entities of any type.
+func (db *MemgraphDatabase) GetEntityByLabel(ctx context.Context, label string, entityType EntityType) ([]Entity, error) {
+	cypher := "MATCH (n {label: $label}) WHERE $type = '' OR $type IN labels(n) RETURN n"
+	params := Properties{"label": label, "type": string(entityType)}
+
+	results, err := db.Query(ctx, cypher, params)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert result to Entity (simplified)
-	result := results[0]
-	if nodeData, ok := result["n"].(map[string]interface{}); ok {
-		entity := &Entity{
-			ID:         id,
-			Properties: make(Properties),
+	entities := make([]Entity, 0, len(results))
+	for _, result := range results {
+		if nodeData, ok := result["n"].(map[string]interface{}); ok {
+			entities = append(entities, entityFromNode(nodeData))
 		}
+	}
+	return entities, nil
+}
 
-		if label, ok := nodeData["label"].(string); ok {
-			entity.Label = label
+// GetRelationshipsByEntityID retrieves the relationships of an entity in the given direction
+// ("in", "out" or "both")
+func (db *MemgraphDatabase) GetRelationshipsByEntityID(ctx context.Context, id string, direction string) ([]Relationship, error) {
+	var cypher string
+	switch direction {
+	case DirectionOut:
+		cypher = "MATCH (a {id: $id})-[r]->(b) RETURN r, a.id AS sourceId, b.id AS targetId"
+	case DirectionIn:
+		cypher = "MATCH (a)-[r]->(b {id: $id}) RETURN r, a.id AS sourceId, b.id AS targetId"
+	case DirectionBoth:
+		cypher = "MATCH (a)-[r]->(b) WHERE a.id = $id OR b.id = $id RETURN r, a.id AS sourceId, b.id AS targetId"
+	default:
+		return nil, fmt.Errorf("invalid direction %q: expected in, out or both", direction)
+	}
+
+	results, err := db.Query(ctx, cypher, Properties{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	relationships := make([]Relationship, 0, len(results))
+	for _, result := range results {
+		if rel, ok := ToRelationship(result); ok {
+			relationships = append(relationships, rel)
 		}
-		if confidence, ok := nodeData["confidence"].(float64); ok {
-			entity.Confidence = confidence
+	}
+	return relationships, nil
+}
+
+// GetEntitiesByType retrieves the entities of the given type
+func (db *MemgraphDatabase) GetEntitiesByType(ctx context.Context, entityType EntityType) ([]Entity, error) {
+	cypher := "MATCH (n) WHERE $type IN labels(n) RETURN n"
+
+	results, err := db.Query(ctx, cypher, Properties{"type": string(entityType)})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]Entity, 0, len(results))
+	for _, result := range results {
+		if nodeData, ok := result["n"].(map[string]interface{}); ok {
+			entities = append(entities, entityFromNode(nodeData))
 		}
+	}
+	return entities, nil
+}
+
+// GetRelationshipsByType retrieves the relationships of the given type
+func (db *MemgraphDatabase) GetRelationshipsByType(ctx context.Context, relType RelationshipType) ([]Relationship, error) {
+	cypher := "MATCH (a)-[r]->(b) WHERE type(r) = $type RETURN r, a.id AS sourceId, b.id AS targetId"
 
-		return entity, nil
+	results, err := db.Query(ctx, cypher, Properties{"type": string(relType)})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("invalid entity format")
+	relationships := make([]Relationship, 0, len(results))
+	for _, result := range results {
+		if rel, ok := ToRelationship(result); ok {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships, nil
 }
 
-// GetAllEntities retrieves all entities from the database
-func (db *MemgraphDatabase) GetAllEntities() ([]Entity, error) {
-	cypher := "MATCH (n) RETURN n LIMIT 1000" // Limit for safety
-	results, err := db.Query(cypher, nil)
+// GetRelationships retrieves the relationships with a confidence of at least
+// minConfidence
+func (db *MemgraphDatabase) GetRelationships(ctx context.Context, minConfidence float64) ([]Relationship, error) {
+	cypher := "MATCH (a)-[r]->(b) WHERE r.confidence >= $minConfidence RETURN r, a.id AS sourceId, b.id AS targetId"
+
+	results, err := db.Query(ctx, cypher, Properties{"minConfidence": minConfidence})
 	if err != nil {
 		return nil, err
 	}
 
-	var entities []Entity
+	relationships := make([]Relationship, 0, len(results))
 	for _, result := range results {
-		if nodeData, ok := result["n"].(map[string]interface{}); ok {
-			entity := Entity{
-				Properties: make(Properties),
+		if rel, ok := ToRelationship(result); ok {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships, nil
+}
+
+// GetDegreeStatistics counts the relationships of each node in Memgraph
+func (db *MemgraphDatabase) GetDegreeStatistics(ctx context.Context) (DegreeStatistics, error) {
+	results, err := db.Query(ctx, `
+		MATCH (n)
+		OPTIONAL MATCH (n)-[outgoing]->()
+		WITH n, count(outgoing) AS outDegree
+		OPTIONAL MATCH (n)<-[incoming]-()
+		WITH n, outDegree, count(incoming) AS inDegree
+		RETURN count(CASE WHEN inDegree + outDegree = 0 THEN 1 END) AS isolated,
+			max(inDegree) AS maxInDegree,
+			max(outDegree) AS maxOutDegree
+	`, nil)
+	if err != nil {
+		return DegreeStatistics{}, fmt.Errorf("failed to get degree statistics: %w", err)
+	}
+
+	var stats DegreeStatistics
+	if len(results) > 0 {
+		// Counts are int64, and the maxima null in an empty graph
+		isolated, _ := results[0]["isolated"].(int64)
+		maxIn, _ := results[0]["maxInDegree"].(int64)
+		maxOut, _ := results[0]["maxOutDegree"].(int64)
+		stats = DegreeStatistics{
+			IsolatedEntities: int(isolated),
+			MaxInDegree:      int(maxIn),
+			MaxOutDegree:     int(maxOut),
+		}
+	}
+	return stats, nil
+}
+
+// DeleteEntitiesBySourceFile removes the nodes extracted from a file, the FILE
+// node of the file and all of their relationships
+func (db *MemgraphDatabase) DeleteEntitiesBySourceFile(ctx context.Context, filePath string) error {
+	if _, err := db.Query(ctx, db.deleteEntitiesBySourceFileQuery(), Properties{"path": filePath}); err != nil {
+		return fmt.Errorf("failed to delete entities of %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// DeleteRelationshipsBySourceFile removes the relationships created for a file:
+// those with a sourceFile property of filePath and those starting at a node
+// extracted from the file or at its FILE node. The nodes are kept.
+func (db *MemgraphDatabase) DeleteRelationshipsBySourceFile(ctx context.Context, filePath string) error {
+	if _, err := db.Query(ctx, db.deleteRelationshipsBySourceFileQuery(), Properties{"path": filePath}); err != nil {
+		return fmt.Errorf("failed to delete relationships of %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// deleteEntitiesBySourceFileQuery returns the query deleting the nodes of the file $path
+func (db *MemgraphDatabase) deleteEntitiesBySourceFileQuery() string {
+	return fmt.Sprintf(`
+		MATCH (n)
+		WHERE n.sourceFile = $path OR (n:%s AND n.path = $path)
+		DETACH DELETE n
+	`, db.escapeLabel("FILE"))
+}
+
+// deleteRelationshipsBySourceFileQuery returns the query deleting the
+// relationships created for the file $path
+func (db *MemgraphDatabase) deleteRelationshipsBySourceFileQuery() string {
+	return fmt.Sprintf(`
+		MATCH (a)-[r]->()
+		WHERE r.sourceFile = $path OR a.sourceFile = $path OR (a:%s AND a.path = $path)
+		DELETE r
+	`, db.escapeLabel("FILE"))
+}
+
+// incomingRelationshipsQuery returns the query selecting the relationships
+// that other files have to the nodes of the files $paths, except those created
+// for the files
+func (db *MemgraphDatabase) incomingRelationshipsQuery() string {
+	file := db.escapeLabel("FILE")
+	return fmt.Sprintf(`
+		MATCH (a)-[r]->(b)
+		WHERE (b.sourceFile IN $paths OR (b:%s AND b.path IN $paths))
+			AND NOT (a.sourceFile IN $paths OR (a:%s AND a.path IN $paths))
+			AND NOT coalesce(r.sourceFile IN $paths, false)
+		RETURN r, a.id AS sourceId, b.id AS targetId, b
+	`, file, file)
+}
+
+// ReplaceSourceFiles removes the nodes and relationships of the files and
+// creates entities and relationships in their place in one transaction, so
+// readers see either the old or the new graph of the files. Relationships of
+// other files ending at nodes of the files are kept and moved to the new
+// nodes. Like CreateEntities, node labels are only stored as the label
+// property.
+func (db *MemgraphDatabase) ReplaceSourceFiles(ctx context.Context, filePaths []string, entities []Entity, relationships []Relationship) error {
+	entityTypes, entitiesByType := groupEntitiesByType(entities)
+
+	ctx, cancel := WithQueryTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	err := db.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
+		results, err := db.queryInTransaction(ctx, tx, db.incomingRelationshipsQuery(), Properties{"paths": filePaths})
+		if err != nil {
+			return fmt.Errorf("failed to get relationships to the files: %w", err)
+		}
+		var incoming []Relationship
+		targets := make(map[string]Entity)
+		for _, result := range results {
+			rel, ok := ToRelationship(result)
+			if !ok {
+				continue
+			}
+			if target, ok := ToEntity(result["b"]); ok {
+				targets[target.ID] = target
 			}
+			incoming = append(incoming, rel)
+		}
+		relTypes, relsByType := groupRelationshipsByType(slices.Concat(relationships, retargetRelationships(incoming, targets, entities)))
 
-			if id, ok := nodeData["id"].(string); ok {
-				entity.ID = id
+		for _, filePath := range filePaths {
+			params := Properties{"path": filePath}
+			if err := runInTransaction(ctx, tx, db.deleteRelationshipsBySourceFileQuery(), params); err != nil {
+				return fmt.Errorf("failed to delete relationships of %s: %w", filePath, err)
 			}
-			if label, ok := nodeData["label"].(string); ok {
-				entity.Label = label
+			if err := runInTransaction(ctx, tx, db.deleteEntitiesBySourceFileQuery(), params); err != nil {
+				return fmt.Errorf("failed to delete entities of %s: %w", filePath, err)
 			}
-			if confidence, ok := nodeData["confidence"].(float64); ok {
-				entity.Confidence = confidence
+		}
+		for _, entityType := range entityTypes {
+			cypher, params := db.entityBatchQuery(entityType, entitiesByType[entityType])
+			if err := runInTransaction(ctx, tx, cypher, params); err != nil {
+				return fmt.Errorf("failed to create %s entities: %w", entityType, err)
 			}
+		}
+		for _, relType := range relTypes {
+			cypher, params := db.relationshipBatchQuery(relType, relsByType[relType])
+			if err := runInTransaction(ctx, tx, cypher, params); err != nil {
+				return fmt.Errorf("failed to create %s relationships: %w", relType, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace the graph of %d files: %w", len(filePaths), err)
+	}
+	return nil
+}
+
+// DeleteEntityByID removes a node and all of its relationships
+func (db *MemgraphDatabase) DeleteEntityByID(ctx context.Context, id string) error {
+	results, err := db.Query(ctx, `
+		MATCH (n {id: $id})
+		DETACH DELETE n
+		RETURN count(*) AS deleted
+	`, Properties{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete entity %s: %w", id, err)
+	}
+	if !deletedAny(results) {
+		return fmt.Errorf("entity %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteRelationshipByID removes a relationship
+func (db *MemgraphDatabase) DeleteRelationshipByID(ctx context.Context, id string) error {
+	results, err := db.Query(ctx, `
+		MATCH ()-[r {id: $id}]->()
+		DELETE r
+		RETURN count(*) AS deleted
+	`, Properties{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete relationship %s: %w", id, err)
+	}
+	if !deletedAny(results) {
+		return fmt.Errorf("relationship %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// deletedAny reports whether the deleted count returned by a delete query is positive
+func deletedAny(results []QueryResult) bool {
+	if len(results) == 0 {
+		return false
+	}
+	deleted, _ := results[0]["deleted"].(int64)
+	return deleted > 0
+}
+
+// relationshipFromMap converts a relationship returned by convertMemgraphValue to a Relationship.
+// Memgraph only knows internal element IDs for the endpoints, so the caller supplies entity IDs.
+func relationshipFromMap(relData map[string]interface{}, sourceID, targetID string) Relationship {
+	rel := Relationship{
+		Source:     sourceID,
+		Target:     targetID,
+		Properties: make(Properties),
+	}
+	if relType, ok := relData["type"].(string); ok {
+		rel.Type = RelationshipType(relType)
+	}
 
-			entities = append(entities, entity)
+	if props, ok := relData["properties"].(map[string]interface{}); ok {
+		if id, ok := props["id"].(string); ok {
+			rel.ID = id
+		}
+		if confidence, ok := props["confidence"].(float64); ok {
+			rel.Confidence = confidence
+		}
+		copyStoredProperties(rel.Properties, props)
+	}
+
+	return rel
+}
+
+// GetAllEntities retrieves all entities from the database
+func (db *MemgraphDatabase) GetAllEntities(ctx context.Context) ([]Entity, error) {
+	cypher := "MATCH (n) RETURN n LIMIT 1000" // Limit for safety
+	results, err := db.Query(ctx, cypher, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []Entity
+	for _, result := range results {
+		if nodeData, ok := result["n"].(map[string]interface{}); ok {
+			entities = append(entities, entityFromNode(nodeData))
 		}
 	}
 
 	return entities, nil
 }
 
+// entityFromNode converts a node returned by convertMemgraphValue to an Entity.
+// The first node label is the entity type.
+func entityFromNode(nodeData map[string]interface{}) Entity {
+	entity := Entity{Properties: make(Properties)}
+
+	if labels, ok := nodeData["labels"].([]string); ok && len(labels) > 0 {
+		entity.Type = EntityType(labels[0])
+	}
+
+	if props, ok := nodeData["properties"].(map[string]interface{}); ok {
+		if id, ok := props["id"].(string); ok {
+			entity.ID = id
+		}
+		if label, ok := props["label"].(string); ok {
+			entity.Label = label
+		}
+		if confidence, ok := props["confidence"].(float64); ok {
+			entity.Confidence = confidence
+		}
+		copyStoredProperties(entity.Properties, props)
+	}
+
+	return entity
+}
+
+// copyStoredProperties copies the properties of a node or relationship except the
+// ones maintained by the database. Properties written with the prop_ prefix used by
+// earlier versions are unwrapped.
+func copyStoredProperties(dst Properties, stored map[string]interface{}) {
+	for key, value := range stored {
+		if reservedPropertyKeys[key] {
+			continue
+		}
+		dst[strings.TrimPrefix(key, "prop_")] = value
+	}
+}
+
 // ClearDatabase removes all nodes and relationships (useful for testing)
-func (db *MemgraphDatabase) ClearDatabase() error {
+func (db *MemgraphDatabase) ClearDatabase(ctx context.Context) error {
 	cypher := "MATCH (n) DETACH DELETE n"
-	_, err := db.Query(cypher, nil)
+	_, err := db.Query(ctx, cypher, nil)
 	if err != nil {
 		return fmt.Errorf("failed to clear database: %w", err)
 	}
@@ -403,62 +1122,79 @@ func (db *MemgraphDatabase) ClearDatabase() error {
 	return nil
 }
 
-// escapeLabel escapes labels for Cypher queries to handle reserved keywords
+// maxCypherLabelLength is the maximum length in bytes of a node label or
+// relationship type written to Memgraph. Longer labels are truncated and get a
+// hash suffix, so distinct labels stay distinct.
+const maxCypherLabelLength = 128
+
+// cypherIdentifierRegex matches labels that can be used in Cypher without quoting
+var cypherIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// cypherReservedLabels are Memgraph/Cypher keywords that need escaping even
+// though they are plain identifiers
+var cypherReservedLabels = map[string]bool{
+	"DIRECTORY": true,
+	"FILE":      true,
+	"DATA":      true,
+	"TYPE":      true,
+	"INDEX":     true,
+	"KEY":       true,
+	"NODE":      true,
+	"EDGE":      true,
+	"GRAPH":     true,
+	"DATABASE":  true,
+	"USER":      true,
+	"ROLE":      true,
+	"CONFIG":    true,
+	"SETTING":   true,
+	"STATUS":    true,
+	"VERSION":   true,
+	"SESSION":   true,
+	"QUERY":     true,
+	"INFO":      true,
+	"STATS":     true,
+	"MODE":      true,
+	"TIMEOUT":   true,
+	"STREAM":    true,
+	"TRIGGER":   true,
+	"FUNCTION":  true,
+	"MODULE":    true,
+	"CLASS":     true,
+	"METHOD":    true,
+	"VARIABLE":  true,
+	"CONSTANT":  true,
+	"PROPERTY":  true,
+	"PARAMETER": true,
+	"IMPORT":    true,
+	"EXPORT":    true,
+	"PACKAGE":   true,
+	"NAMESPACE": true,
+	"INTERFACE": true,
+	"ENUM":      true,
+	"COMMENT":   true,
+	"TEST":      true,
+}
+
+// escapeLabel makes a label safe to use as a node label or relationship type in
+// Cypher queries: labels that aren't plain identifiers, or that are reserved
+// keywords, are quoted with backticks, and labels longer than
+// maxCypherLabelLength are truncated with a hash suffix
 func (db *MemgraphDatabase) escapeLabel(label string) string {
-	// List of Memgraph/Cypher reserved keywords that need escaping
-	reservedKeywords := map[string]bool{
-		"DIRECTORY": true,
-		"FILE":      true,
-		"DATA":      true,
-		"TYPE":      true,
-		"INDEX":     true,
-		"KEY":       true,
-		"NODE":      true,
-		"EDGE":      true,
-		"GRAPH":     true,
-		"DATABASE":  true,
-		"USER":      true,
-		"ROLE":      true,
-		"CONFIG":    true,
-		"SETTING":   true,
-		"STATUS":    true,
-		"VERSION":   true,
-		"SESSION":   true,
-		"QUERY":     true,
-		"INFO":      true,
-		"STATS":     true,
-		"MODE":      true,
-		"TIMEOUT":   true,
-		"STREAM":    true,
-		"TRIGGER":   true,
-		"FUNCTION":  true,
-		"MODULE":    true,
-		"CLASS":     true,
-		"METHOD":    true,
-		"VARIABLE":  true,
-		"CONSTANT":  true,
-		"PROPERTY":  true,
-		"PARAMETER": true,
-		"IMPORT":    true,
-		"EXPORT":    true,
-		"PACKAGE":   true,
-		"NAMESPACE": true,
-		"INTERFACE": true,
-		"ENUM":      true,
-		"COMMENT":   true,
-		"TEST":      true,
-	}
-
-	// Check if the label is a reserved keyword
-	if reservedKeywords[strings.ToUpper(label)] {
-		// Escape with backticks
-		return "`" + label + "`"
-	}
-
-	// Also escape if it contains spaces or special characters
-	if strings.ContainsAny(label, " -+*/()[]{}.,;:!?@#$%^&=<>|\\\"'") {
-		return "`" + label + "`"
-	}
-
-	return label
+	if len(label) > maxCypherLabelLength {
+		sum := sha256.Sum256([]byte(label))
+		suffix := "_" + hex.EncodeToString(sum[:])[:16]
+		// Don't cut a multi-byte character in half
+		cut := maxCypherLabelLength - len(suffix)
+		for cut > 0 && !utf8.RuneStart(label[cut]) {
+			cut--
+		}
+		label = label[:cut] + suffix
+	}
+
+	if cypherIdentifierRegex.MatchString(label) && !cypherReservedLabels[strings.ToUpper(label)] {
+		return label
+	}
+
+	// Backticks inside a quoted name are escaped by doubling them
+	return "`" + strings.ReplaceAll(label, "`", "``") + "`"
 }
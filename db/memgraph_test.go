@@ -0,0 +1,108 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeLabel(t *testing.T) {
+	db := NewMemgraphDatabase("", "", "")
+
+	tests := []struct {
+		name  string
+		label string
+		want  string
+	}{
+		{name: "plain identifier", label: "handleRequest", want: "handleRequest"},
+		{name: "underscore", label: "_private_name", want: "_private_name"},
+		{name: "reserved keyword", label: "FUNCTION", want: "`FUNCTION`"},
+		{name: "reserved keyword in lower case", label: "file", want: "`file`"},
+		{name: "generic type", label: "List<String>", want: "`List<String>`"},
+		{name: "go generic", label: "Map[K, V]", want: "`Map[K, V]`"},
+		{name: "slash", label: "src/utils/index.ts", want: "`src/utils/index.ts`"},
+		{name: "hyphen", label: "my-package", want: "`my-package`"},
+		{name: "leading digit", label: "3rdParty", want: "`3rdParty`"},
+		{name: "unicode", label: "größe", want: "`größe`"},
+		{name: "backtick", label: "a`b", want: "`a``b`"},
+		{name: "empty", label: "", want: "``"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := db.escapeLabel(tt.label); got != tt.want {
+				t.Errorf("escapeLabel(%q) = %q, want %q", tt.label, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLabelTruncatesLongLabels(t *testing.T) {
+	db := NewMemgraphDatabase("", "", "")
+
+	long := strings.Repeat("a", maxCypherLabelLength+10)
+	got := db.escapeLabel(long)
+	if len(got) != maxCypherLabelLength {
+		t.Errorf("len(escapeLabel(long)) = %d, want %d", len(got), maxCypherLabelLength)
+	}
+	if !strings.HasPrefix(got, strings.Repeat("a", 64)) {
+		t.Errorf("escapeLabel(long) = %q, want the label as prefix", got)
+	}
+
+	// Labels sharing the truncated prefix get different hash suffixes
+	other := db.escapeLabel(long[:len(long)-1] + "b")
+	if other == got {
+		t.Errorf("distinct long labels escaped to the same label %q", got)
+	}
+
+	// The same label always truncates to the same label
+	if again := db.escapeLabel(long); again != got {
+		t.Errorf("escapeLabel is not deterministic: %q != %q", again, got)
+	}
+
+	// Labels at the limit are kept as they are
+	exact := strings.Repeat("b", maxCypherLabelLength)
+	if got := db.escapeLabel(exact); got != exact {
+		t.Errorf("escapeLabel(%d bytes) = %q, want it unchanged", maxCypherLabelLength, got)
+	}
+}
+
+func TestEscapeLabelTruncatesLongUnicodeLabels(t *testing.T) {
+	db := NewMemgraphDatabase("", "", "")
+
+	// Multi-byte characters must not be cut in half by the truncation
+	long := strings.Repeat("ü", maxCypherLabelLength)
+	got := db.escapeLabel(long)
+	if !utf8.ValidString(got) {
+		t.Fatalf("escapeLabel(long) = %q is not valid UTF-8", got)
+	}
+	if !strings.HasPrefix(got, "`") || !strings.HasSuffix(got, "`") {
+		t.Errorf("escapeLabel(long) = %q, want it quoted", got)
+	}
+	if unquoted := strings.Trim(got, "`"); len(unquoted) > maxCypherLabelLength {
+		t.Errorf("truncated label is %d bytes, want at most %d", len(unquoted), maxCypherLabelLength)
+	}
+}
+
+func TestConnectionURI(t *testing.T) {
+	tests := []struct {
+		uri        string
+		encrypted  bool
+		skipVerify bool
+		want       string
+	}{
+		{uri: "bolt://localhost:7687", want: "bolt://localhost:7687"},
+		{uri: "bolt://localhost:7687", encrypted: true, want: "bolt+s://localhost:7687"},
+		{uri: "neo4j://cluster:7687", encrypted: true, want: "neo4j+s://cluster:7687"},
+		{uri: "bolt://localhost:7687", encrypted: true, skipVerify: true, want: "bolt+ssc://localhost:7687"},
+		{uri: "neo4j://cluster:7687", encrypted: true, skipVerify: true, want: "neo4j+ssc://cluster:7687"},
+		{uri: "neo4j+s://cluster:7687", encrypted: true, skipVerify: true, want: "neo4j+s://cluster:7687"},
+	}
+	for _, tt := range tests {
+		db := NewMemgraphDatabase(tt.uri, "", "")
+		db.Encrypted = tt.encrypted
+		db.SkipVerify = tt.skipVerify
+		if got := db.connectionURI(); got != tt.want {
+			t.Errorf("connectionURI(%q, encrypted=%v, skipVerify=%v) = %q, want %q", tt.uri, tt.encrypted, tt.skipVerify, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package db
+
+import "fmt"
+
+// MergeStrategy selects how an entity stored again under an existing ID is
+// combined with the stored entity. All backends apply the same strategy.
+type MergeStrategy string
+
+const (
+	// MergeProperties keeps the higher confidence, takes the new label and adds
+	// the new properties, overwriting stored properties of the same name. It is
+	// the default.
+	MergeProperties MergeStrategy = "merge-properties"
+	// KeepHigherConfidence keeps whichever entity has the higher confidence as a
+	// whole, with its label and properties only. On equal confidence the new
+	// entity is kept.
+	KeepHigherConfidence MergeStrategy = "keep-higher-confidence"
+	// KeepNewer takes the label and confidence of the new entity and adds its
+	// properties like MergeProperties, even when its confidence is lower
+	KeepNewer MergeStrategy = "keep-newer"
+	// ReplaceAll replaces the stored entity with the new one, dropping stored
+	// properties the new entity doesn't have
+	ReplaceAll MergeStrategy = "replace-all"
+)
+
+// MergeStrategies lists the available merge strategies
+var MergeStrategies = []MergeStrategy{MergeProperties, KeepHigherConfidence, KeepNewer, ReplaceAll}
+
+// ParseMergeStrategy returns the merge strategy with the given name. An empty
+// name selects MergeProperties.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	if name == "" {
+		return MergeProperties, nil
+	}
+	for _, strategy := range MergeStrategies {
+		if MergeStrategy(name) == strategy {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown merge strategy %q: expected one of %v", name, MergeStrategies)
+}
+
+// mergeEntity returns the entity stored when entity is stored again under the
+// ID of existing
+func mergeEntity(existing, entity Entity, strategy MergeStrategy) Entity {
+	switch strategy {
+	case ReplaceAll:
+		return entity
+	case KeepHigherConfidence:
+		if entity.Confidence >= existing.Confidence {
+			return entity
+		}
+		return existing
+	}
+
+	merged := existing
+	merged.Label = entity.Label
+	if strategy == KeepNewer || entity.Confidence > existing.Confidence {
+		merged.Confidence = entity.Confidence
+	}
+
+	// Copy the properties so the stored entity doesn't share its map with the caller
+	merged.Properties = make(Properties, len(existing.Properties)+len(entity.Properties))
+	for k, v := range existing.Properties {
+		merged.Properties[k] = v
+	}
+	for k, v := range entity.Properties {
+		merged.Properties[k] = v
+	}
+	return merged
+}
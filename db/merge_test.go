@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    MergeStrategy
+		wantErr bool
+	}{
+		{name: "", want: MergeProperties},
+		{name: "merge-properties", want: MergeProperties},
+		{name: "keep-higher-confidence", want: KeepHigherConfidence},
+		{name: "keep-newer", want: KeepNewer},
+		{name: "replace-all", want: ReplaceAll},
+		{name: "Replace-All", wantErr: true},
+		{name: "unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMergeStrategy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMergeStrategy(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMergeStrategy(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeEntity(t *testing.T) {
+	existing := Entity{
+		ID:         "e1",
+		Label:      "old",
+		Type:       "FUNCTION",
+		Confidence: 0.8,
+		Properties: Properties{"kept": "stored", "shared": "stored"},
+	}
+	lower := Entity{
+		ID:         "e1",
+		Label:      "new",
+		Type:       "FUNCTION",
+		Confidence: 0.5,
+		Properties: Properties{"shared": "new", "added": "new"},
+	}
+	higher := lower
+	higher.Confidence = 0.9
+
+	merged := func(label string, confidence float64) Entity {
+		return Entity{
+			ID:         "e1",
+			Label:      label,
+			Type:       "FUNCTION",
+			Confidence: confidence,
+			Properties: Properties{"kept": "stored", "shared": "new", "added": "new"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		entity   Entity
+		want     Entity
+	}{
+		{name: "merge-properties with lower confidence", strategy: MergeProperties, entity: lower, want: merged("new", 0.8)},
+		{name: "merge-properties with higher confidence", strategy: MergeProperties, entity: higher, want: merged("new", 0.9)},
+		{name: "keep-higher-confidence with lower confidence", strategy: KeepHigherConfidence, entity: lower, want: existing},
+		{name: "keep-higher-confidence with higher confidence", strategy: KeepHigherConfidence, entity: higher, want: higher},
+		{name: "keep-newer with lower confidence", strategy: KeepNewer, entity: lower, want: merged("new", 0.5)},
+		{name: "keep-newer with higher confidence", strategy: KeepNewer, entity: higher, want: merged("new", 0.9)},
+		{name: "replace-all with lower confidence", strategy: ReplaceAll, entity: lower, want: lower},
+		{name: "replace-all with higher confidence", strategy: ReplaceAll, entity: higher, want: higher},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeEntity(existing, tt.entity, tt.strategy)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeEntity() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeEntityKeepsEqualConfidence(t *testing.T) {
+	existing := Entity{ID: "e1", Label: "old", Confidence: 0.8}
+	entity := Entity{ID: "e1", Label: "new", Confidence: 0.8}
+
+	if got := mergeEntity(existing, entity, KeepHigherConfidence); got.Label != "new" {
+		t.Errorf("keep-higher-confidence on equal confidence kept %q, want the new entity", got.Label)
+	}
+}
+
+func TestMergeEntityDoesNotShareProperties(t *testing.T) {
+	existing := Entity{ID: "e1", Properties: Properties{"a": 1}}
+	entity := Entity{ID: "e1", Properties: Properties{"b": 2}}
+
+	got := mergeEntity(existing, entity, MergeProperties)
+	got.Properties["c"] = 3
+	if _, ok := existing.Properties["c"]; ok {
+		t.Error("merged entity shares its properties with the stored entity")
+	}
+	if _, ok := entity.Properties["c"]; ok {
+		t.Error("merged entity shares its properties with the new entity")
+	}
+}
+
+func TestInMemoryMergeStrategies(t *testing.T) {
+	ctx := context.Background()
+	for _, strategy := range MergeStrategies {
+		t.Run(string(strategy), func(t *testing.T) {
+			database := NewInMemoryDatabase().WithMergeStrategy(strategy)
+			existing := Entity{ID: "e1", Label: "old", Confidence: 0.8, Properties: Properties{"kept": "stored"}}
+			entity := Entity{ID: "e1", Label: "new", Confidence: 0.5, Properties: Properties{"added": "new"}}
+
+			if err := database.CreateEntity(ctx, existing); err != nil {
+				t.Fatal(err)
+			}
+			if err := database.CreateEntity(ctx, entity); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := database.GetEntityByID(ctx, "e1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := mergeEntity(existing, entity, strategy); !reflect.DeepEqual(*got, want) {
+				t.Errorf("stored entity = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+// The Memgraph and PostgreSQL backends merge in their query languages; each
+// strategy must produce its own clause
+func TestMergeClausesPerStrategy(t *testing.T) {
+	memgraphClauses := make(map[string]MergeStrategy)
+	postgresClauses := make(map[string]MergeStrategy)
+	for _, strategy := range MergeStrategies {
+		memgraph := NewMemgraphDatabase("", "", "").WithMergeStrategy(strategy)
+		postgres := NewPostgresDatabase("").WithMergeStrategy(strategy)
+
+		clause := memgraph.entityMergeClauses()
+		if other, exists := memgraphClauses[clause]; exists {
+			t.Errorf("Memgraph merge clauses of %s and %s are the same", strategy, other)
+		}
+		memgraphClauses[clause] = strategy
+
+		update := postgres.entityConflictUpdate()
+		if other, exists := postgresClauses[update]; exists {
+			t.Errorf("PostgreSQL conflict updates of %s and %s are the same", strategy, other)
+		}
+		postgresClauses[update] = strategy
+	}
+
+	keepHigher := NewPostgresDatabase("").WithMergeStrategy(KeepHigherConfidence).entityConflictUpdate()
+	if !strings.Contains(keepHigher, "WHERE EXCLUDED.confidence >= entities.confidence") {
+		t.Errorf("keep-higher-confidence update %q doesn't compare confidences", keepHigher)
+	}
+	replaceAll := NewPostgresDatabase("").WithMergeStrategy(ReplaceAll).entityConflictUpdate()
+	if strings.Contains(replaceAll, "entities.properties ||") {
+		t.Errorf("replace-all update %q keeps stored properties", replaceAll)
+	}
+}
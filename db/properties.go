@@ -0,0 +1,87 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reservedPropertyKeys are stored on nodes and relationships by the database
+// itself and must not be overwritten by entity properties
+var reservedPropertyKeys = map[string]bool{
+	"id":         true,
+	"label":      true,
+	"confidence": true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// cypherReservedWords are the openCypher keywords, which cannot be used as
+// property keys without backticks
+var cypherReservedWords = map[string]bool{
+	"ALL": true, "ASC": true, "ASCENDING": true, "BY": true, "CREATE": true,
+	"DELETE": true, "DESC": true, "DESCENDING": true, "DETACH": true, "EXISTS": true,
+	"LIMIT": true, "MATCH": true, "MERGE": true, "ON": true, "OPTIONAL": true,
+	"ORDER": true, "REMOVE": true, "RETURN": true, "SET": true, "SKIP": true,
+	"WHERE": true, "WITH": true, "UNION": true, "UNWIND": true, "AND": true,
+	"AS": true, "CONTAINS": true, "DISTINCT": true, "ENDS": true, "IN": true,
+	"IS": true, "NOT": true, "OR": true, "STARTS": true, "XOR": true,
+	"CASE": true, "ELSE": true, "END": true, "THEN": true, "WHEN": true,
+	"NULL": true, "TRUE": true, "FALSE": true, "CONSTRAINT": true, "DO": true,
+	"FOR": true, "REQUIRE": true, "UNIQUE": true, "MANDATORY": true, "SCALAR": true,
+	"OF": true, "ADD": true, "DROP": true, "CALL": true, "YIELD": true,
+}
+
+// Flatten returns the properties with every value the Bolt protocol cannot
+// store as a property (maps, structs and lists of those) encoded as a JSON
+// string. Scalars and lists of scalars are kept as they are.
+func (p Properties) Flatten() map[string]interface{} {
+	flattened := make(map[string]interface{}, len(p))
+	for key, value := range p {
+		if isPropertyValue(value) {
+			flattened[key] = value
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			flattened[key] = fmt.Sprintf("%v", value)
+			continue
+		}
+		flattened[key] = string(data)
+	}
+	return flattened
+}
+
+// SanitizeProperties prepares entity or relationship properties for storage in
+// Memgraph under their own names, so that they can be queried as n.language.
+// Values are flattened, and keys that are Cypher reserved words or that would
+// overwrite the id, label or confidence of the node are dropped.
+func SanitizeProperties(props Properties) map[string]interface{} {
+	sanitized := props.Flatten()
+	for key := range sanitized {
+		if reservedPropertyKeys[key] || cypherReservedWords[strings.ToUpper(key)] {
+			delete(sanitized, key)
+		}
+	}
+	return sanitized
+}
+
+// isPropertyValue reports whether a value can be stored as a property as is
+func isPropertyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64, float32, float64, time.Time,
+		[]bool, []string, []int, []int64, []float64:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			switch item.(type) {
+			case nil, bool, string, int, int8, int16, int32, int64, float32, float64:
+			default:
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
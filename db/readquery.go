@@ -0,0 +1,27 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// cypherLiteralRegex matches string literals, quoted identifiers and comments,
+// whose words aren't clauses
+var cypherLiteralRegex = regexp.MustCompile("'(?:[^'\\\\]|\\\\.)*'|\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`|//[^\\n]*|/\\*[\\s\\S]*?\\*/")
+
+// cypherWriteClauseRegex matches the Cypher clauses that modify the graph. A
+// word after a dot, colon or dollar sign is a property, label or parameter like
+// n.set, not a clause.
+var cypherWriteClauseRegex = regexp.MustCompile(`(?i)(?:^|[^.:$\w])(CREATE|MERGE|SET|DELETE|REMOVE|DROP)\b`)
+
+// checkReadQuery returns ErrWriteQuery, wrapped, when a Cypher query contains a
+// clause that modifies the graph. ReadQuery checks queries before running them,
+// so read-only callers don't depend on the database enforcing read access.
+func checkReadQuery(cypher string) error {
+	stripped := cypherLiteralRegex.ReplaceAllString(cypher, " ")
+	if match := cypherWriteClauseRegex.FindStringSubmatch(stripped); match != nil {
+		return fmt.Errorf("%w: %s clause in %s", ErrWriteQuery, strings.ToUpper(match[1]), cypher)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckReadQuery(t *testing.T) {
+	tests := []struct {
+		cypher string
+		write  bool
+	}{
+		{"MATCH (n) RETURN n", false},
+		{"MATCH (n) WHERE n.label = 'CREATE TABLE' RETURN n.offset, n.set", false},
+		{"MATCH (n:Merge) WHERE n.id = $delete RETURN n // DELETE n", false},
+		{"MATCH (n) DETACH DELETE n", true},
+		{"match (n) set n.label = 'x'", true},
+		{"CREATE (n:FUNCTION {id: 'x'})", true},
+		{"MATCH (a), (b) MERGE (a)-[:CALLS]->(b)", true},
+		{"MATCH (n) REMOVE n.label", true},
+		{"DROP INDEX ON :FUNCTION(id)", true},
+	}
+	for _, tt := range tests {
+		err := checkReadQuery(tt.cypher)
+		if write := errors.Is(err, ErrWriteQuery); write != tt.write {
+			t.Errorf("checkReadQuery(%q) = %v, want write %v", tt.cypher, err, tt.write)
+		}
+	}
+}
+
+func TestInMemoryReadQueryRejectsWrites(t *testing.T) {
+	database := NewInMemoryDatabase()
+	if _, err := database.ReadQuery(context.Background(), "MATCH (n) DETACH DELETE n", nil); !errors.Is(err, ErrWriteQuery) {
+		t.Errorf("ReadQuery(DETACH DELETE) = %v, want ErrWriteQuery", err)
+	}
+	if _, err := database.ReadQuery(context.Background(), "MATCH (n) RETURN n", nil); err != nil {
+		t.Errorf("ReadQuery(MATCH) = %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package db
+
+import "fmt"
+
+// RelationshipID returns the deterministic ID of the relationship of type
+// relType from sourceID to targetID
+func RelationshipID(sourceID, targetID string, relType RelationshipType) string {
+	key := fmt.Sprintf("%s|%s|%s", sourceID, string(relType), targetID)
+	return fmt.Sprintf("%x", key)
+}
+
+// entityFileKey identifies an entity within the files being replaced across
+// re-analyses, which change the IDs of entities that moved to another line
+func entityFileKey(entity Entity) string {
+	if entity.Type == "FILE" {
+		return fmt.Sprintf("%s|%v", entity.Type, entity.Properties["path"])
+	}
+	return fmt.Sprintf("%s|%s|%v", entity.Type, entity.Label, entity.Properties["sourceFile"])
+}
+
+// retargetRelationships points the relationships that unchanged files had to
+// the replaced entities of targets, keyed by ID, at the new entities of the
+// files. A relationship keeps its target when an entity with the same ID is
+// stored again, and otherwise moves to the one entity of the same type, label
+// and file. Relationships whose target is gone are dropped.
+func retargetRelationships(incoming []Relationship, targets map[string]Entity, entities []Entity) []Relationship {
+	stored := make(map[string]bool, len(entities))
+	byKey := make(map[string]string, len(entities))
+	for _, entity := range entities {
+		stored[entity.ID] = true
+		key := entityFileKey(entity)
+		if _, exists := byKey[key]; exists {
+			// Ambiguous, e.g. methods of the same name in different classes
+			byKey[key] = ""
+			continue
+		}
+		byKey[key] = entity.ID
+	}
+
+	retargeted := make([]Relationship, 0, len(incoming))
+	for _, rel := range incoming {
+		if !stored[rel.Target] {
+			target, known := targets[rel.Target]
+			if !known || byKey[entityFileKey(target)] == "" {
+				continue
+			}
+			rel.Target = byKey[entityFileKey(target)]
+			rel.ID = RelationshipID(rel.Source, rel.Target, rel.Type)
+		}
+		retargeted = append(retargeted, rel)
+	}
+	return retargeted
+}
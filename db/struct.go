@@ -1,5 +1,23 @@
 package db
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned, wrapped, when the entity to get or the entity or
+// relationship to delete does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrUnsupportedQuery is returned, wrapped, by databases that translate Cypher
+// when they don't understand a query
+var ErrUnsupportedQuery = errors.New("unsupported query")
+
+// ErrWriteQuery is returned, wrapped, when ReadQuery is given a query that
+// modifies the graph
+var ErrWriteQuery = errors.New("query modifies the graph")
+
 // Properties is a map of property key-value pairs
 type Properties map[string]interface{}
 
@@ -31,12 +49,97 @@ type Relationship struct {
 	Confidence float64          `json:"confidence,omitempty"`
 }
 
-// DatabaseConnection interface defines database operations
+// ToEntity converts an entity value returned by Query to an Entity.
+// The in-memory backend returns flat maps created by Entity.ToMap, Memgraph returns
+// node maps and PostgreSQL returns Entity values.
+func ToEntity(value interface{}) (Entity, bool) {
+	switch v := value.(type) {
+	case Entity:
+		return v, true
+	case map[string]interface{}:
+		if _, ok := v["properties"]; ok {
+			return entityFromNode(v), true
+		}
+		if entity, err := EntityFromMap(v); err == nil {
+			return entity, true
+		}
+	}
+	return Entity{}, false
+}
+
+// ToRelationship converts a query result containing a relationship column "r" to a Relationship.
+// The in-memory and PostgreSQL backends return Relationship values. For Memgraph the endpoint entity IDs are
+// read from "sourceId"/"targetId" columns or from the "a"/"b" nodes of (a)-[r]->(b) queries.
+func ToRelationship(result QueryResult) (Relationship, bool) {
+	switch r := result["r"].(type) {
+	case Relationship:
+		return r, true
+	case map[string]interface{}:
+		sourceID, _ := result["sourceId"].(string)
+		targetID, _ := result["targetId"].(string)
+		if source, ok := ToEntity(result["a"]); ok && sourceID == "" {
+			sourceID = source.ID
+		}
+		if target, ok := ToEntity(result["b"]); ok && targetID == "" {
+			targetID = target.ID
+		}
+		return relationshipFromMap(r, sourceID, targetID), true
+	}
+	return Relationship{}, false
+}
+
+// DegreeStatistics summarizes the number of relationships starting (out
+// degree) and ending (in degree) at each entity
+type DegreeStatistics struct {
+	// IsolatedEntities is the number of entities without relationships
+	IsolatedEntities int
+	// MaxInDegree is the largest number of relationships ending at one entity
+	MaxInDegree int
+	// MaxOutDegree is the largest number of relationships starting at one entity
+	MaxOutDegree int
+}
+
+// Relationship directions accepted by GetRelationshipsByEntityID
+const (
+	DirectionIn   = "in"
+	DirectionOut  = "out"
+	DirectionBoth = "both"
+)
+
+// DatabaseConnection interface defines database operations. The context of an
+// operation cancels it and bounds its duration. ReplaceSourceFiles deletes the
+// graph of files like DeleteRelationshipsBySourceFile and
+// DeleteEntitiesBySourceFile and stores the new graph in one transaction,
+// keeping the relationships other files have to the replaced entities.
 type DatabaseConnection interface {
 	Connect() error
 	Disconnect() error
-	Query(cypher string, parameters Properties) ([]QueryResult, error)
-	CreateEntity(entity Entity) error
-	CreateRelationship(relationship Relationship) error
+	Query(ctx context.Context, cypher string, parameters Properties) ([]QueryResult, error)
+	ReadQuery(ctx context.Context, cypher string, parameters Properties) ([]QueryResult, error)
+	CreateEntity(ctx context.Context, entity Entity) error
+	CreateRelationship(ctx context.Context, relationship Relationship) error
+	GetEntityByID(ctx context.Context, id string) (*Entity, error)
+	GetEntityByLabel(ctx context.Context, label string, entityType EntityType) ([]Entity, error)
+	GetRelationshipsByEntityID(ctx context.Context, id string, direction string) ([]Relationship, error)
+	GetEntitiesByType(ctx context.Context, entityType EntityType) ([]Entity, error)
+	GetRelationshipsByType(ctx context.Context, relType RelationshipType) ([]Relationship, error)
+	GetRelationships(ctx context.Context, minConfidence float64) ([]Relationship, error)
+	GetDegreeStatistics(ctx context.Context) (DegreeStatistics, error)
+	DeleteEntitiesBySourceFile(ctx context.Context, filePath string) error
+	DeleteRelationshipsBySourceFile(ctx context.Context, filePath string) error
+	ReplaceSourceFiles(ctx context.Context, filePaths []string, entities []Entity, relationships []Relationship) error
+	DeleteEntityByID(ctx context.Context, id string) error
+	DeleteRelationshipByID(ctx context.Context, id string) error
 }
 
+// DefaultQueryTimeout is the default duration of a database operation
+const DefaultQueryTimeout = 30 * time.Second
+
+// WithQueryTimeout returns a context for database operations that is cancelled
+// with parent or after timeout. A timeout of 0 or less only cancels with parent.
+func WithQueryTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
@@ -0,0 +1,242 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// Kinds of breaking changes reported by DetectBreakingChanges
+const (
+	BreakingRemovedExport      = "removed-export"
+	BreakingRemovedMethod      = "removed-method"
+	BreakingChangedSignature   = "changed-signature"
+	BreakingAddedRequiredParam = "added-required-param"
+)
+
+// BreakingChange describes a change to the public API between two graphs
+type BreakingChange struct {
+	Type        string `json:"type"`
+	EntityLabel string `json:"entityLabel"`
+	Details     string `json:"details,omitempty"`
+}
+
+// DetectBreakingChanges compares the public functions and methods of two graphs.
+// Exported functions and public methods that disappear are reported as removed;
+// for those present in both graphs, added parameters are reported as
+// added-required-param and other parameter or return type changes as changed-signature.
+// Parameters are compared by type, so renaming a parameter isn't a change.
+func DetectBreakingChanges(before, after *graph.KnowledgeGraph) []BreakingChange {
+	afterAPI := make(map[string]graph.Entity)
+	for _, entity := range after.Entities {
+		if key, ok := publicAPIKey(entity); ok {
+			if _, exists := afterAPI[key]; !exists {
+				afterAPI[key] = entity
+			}
+		}
+	}
+
+	var changes []BreakingChange
+	seen := make(map[string]bool)
+	for _, old := range before.Entities {
+		key, ok := publicAPIKey(old)
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		label := apiLabel(old)
+
+		current, exists := afterAPI[key]
+		if !exists {
+			changeType := BreakingRemovedExport
+			if old.Type == graph.EntityTypeMethod || receiverType(old) != "" {
+				changeType = BreakingRemovedMethod
+			}
+			changes = append(changes, BreakingChange{Type: changeType, EntityLabel: label})
+			continue
+		}
+
+		oldParams := stringSlice(old.Properties["parameters"])
+		newParams := stringSlice(current.Properties["parameters"])
+		switch {
+		case len(newParams) > len(oldParams):
+			changes = append(changes, BreakingChange{
+				Type:        BreakingAddedRequiredParam,
+				EntityLabel: label,
+				Details:     fmt.Sprintf("(%s) -> (%s)", strings.Join(oldParams, ", "), strings.Join(newParams, ", ")),
+			})
+		case !equalStrings(parameterTypes(old), parameterTypes(current)):
+			changes = append(changes, BreakingChange{
+				Type:        BreakingChangedSignature,
+				EntityLabel: label,
+				Details:     fmt.Sprintf("parameters (%s) -> (%s)", strings.Join(oldParams, ", "), strings.Join(newParams, ", ")),
+			})
+		}
+
+		oldReturns := returnTypes(old)
+		newReturns := returnTypes(current)
+		if !equalStrings(oldReturns, newReturns) {
+			changes = append(changes, BreakingChange{
+				Type:        BreakingChangedSignature,
+				EntityLabel: label,
+				Details:     fmt.Sprintf("returns (%s) -> (%s)", strings.Join(oldReturns, ", "), strings.Join(newReturns, ", ")),
+			})
+		}
+	}
+
+	return changes
+}
+
+// publicAPIKey returns the key matching a public function or method across graphs.
+// Go methods are qualified by their receiver type so that methods with the same
+// name on different types are told apart.
+func publicAPIKey(entity graph.Entity) (string, bool) {
+	switch entity.Type {
+	case graph.EntityTypeFunction:
+		if exported, _ := entity.Properties["isExported"].(bool); !exported {
+			return "", false
+		}
+	case graph.EntityTypeMethod:
+		if visibility, _ := entity.Properties["visibility"].(string); visibility == "private" || visibility == "protected" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	return fmt.Sprintf("%s:%s", entity.Type, apiLabel(entity)), true
+}
+
+// apiLabel returns the label of a function, prefixed by its receiver type for Go methods
+func apiLabel(entity graph.Entity) string {
+	if receiver := receiverType(entity); receiver != "" {
+		return receiver + "." + entity.Label
+	}
+	return entity.Label
+}
+
+// receiverType extracts the type name from a Go receiver such as "s *Server"
+func receiverType(entity graph.Entity) string {
+	receiver, _ := entity.Properties["receiver"].(string)
+	fields := strings.Fields(receiver)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimLeft(fields[len(fields)-1], "*")
+}
+
+// goTypeKeywords start unnamed Go parameter types that contain spaces, such as
+// "func(int) error" or "chan int"
+var goTypeKeywords = map[string]bool{"func": true, "chan": true, "map": true, "struct": true, "interface": true}
+
+// parameterTypes returns the types of the parameters of a function, as
+// recorded by the analyzers: "name type" (or only the type) for Go and
+// "name: type" for TypeScript
+func parameterTypes(entity graph.Entity) []string {
+	language, _ := entity.Properties["language"].(string)
+	parameters := stringSlice(entity.Properties["parameters"])
+	types := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		parameter = strings.TrimSpace(parameter)
+		if language == "go" {
+			types = append(types, goParameterType(parameter))
+		} else {
+			types = append(types, annotatedParameterType(parameter))
+		}
+	}
+	return types
+}
+
+// goParameterType returns the type of a Go parameter, which is unnamed when it
+// is a single word or starts with a type keyword
+func goParameterType(parameter string) string {
+	fields := strings.Fields(parameter)
+	if len(fields) < 2 || goTypeKeywords[fields[0]] || strings.ContainsAny(fields[0], ".*[]()<-") {
+		return parameter
+	}
+	return strings.TrimSpace(strings.TrimPrefix(parameter, fields[0]))
+}
+
+// annotatedParameterType returns the type of a parameter such as
+// "name?: type = default", marked optional with ? or variadic with ...
+// Parameters without a type annotation have the empty type.
+func annotatedParameterType(parameter string) string {
+	optional := false
+	if end := topLevelIndex(parameter, '='); end >= 0 {
+		optional = true
+		parameter = strings.TrimSpace(parameter[:end])
+	}
+
+	var name, typ string
+	if colon := topLevelIndex(parameter, ':'); colon >= 0 {
+		name, typ = strings.TrimSpace(parameter[:colon]), strings.TrimSpace(parameter[colon+1:])
+	} else {
+		name = parameter
+	}
+	if strings.HasSuffix(name, "?") || optional {
+		typ += "?"
+	}
+	if strings.Contains(name, "...") {
+		typ = "..." + typ
+	}
+	return typ
+}
+
+// topLevelIndex returns the index of the first c outside of brackets in s, not
+// counting the = of =>, or -1
+func topLevelIndex(s string, c byte) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.IndexByte("([{<", s[i]) != -1:
+			depth++
+		case s[i] == '>' && i > 0 && s[i-1] == '=':
+		case strings.IndexByte(")]}>", s[i]) != -1:
+			depth--
+		case depth == 0 && s[i] == c && (c != '=' || i+1 == len(s) || s[i+1] != '>'):
+			return i
+		}
+	}
+	return -1
+}
+
+// returnTypes returns the return types of a function: returnTypes for Go,
+// returnType for TypeScript and Java
+func returnTypes(entity graph.Entity) []string {
+	if types, ok := entity.Properties["returnTypes"]; ok {
+		return stringSlice(types)
+	}
+	if returnType, ok := entity.Properties["returnType"].(string); ok && returnType != "" {
+		return []string{returnType}
+	}
+	return nil
+}
+
+// stringSlice converts a list property to strings. Lists are []string when
+// produced by an analyzer and []interface{} when read back from a database.
+func stringSlice(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	}
+	return nil
+}
+
+// equalStrings reports whether two string slices have the same elements in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"sort"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// FunctionComplexity is a function with its cyclomatic complexity
+type FunctionComplexity struct {
+	ID                   string `json:"id"`
+	Label                string `json:"label"`
+	SourceFile           string `json:"sourceFile"`
+	LineNumber           int    `json:"lineNumber"`
+	CyclomaticComplexity int    `json:"cyclomaticComplexity"`
+}
+
+// RankByComplexity returns the functions whose cyclomaticComplexity property is at
+// least threshold, most complex first. Ties are ordered by file and line. A limit
+// of 0 or less returns all matching functions.
+func RankByComplexity(entities []graph.Entity, threshold, limit int) []FunctionComplexity {
+	ranked := []FunctionComplexity{}
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeFunction {
+			continue
+		}
+		complexity, ok := ToInt(entity.Properties["cyclomaticComplexity"])
+		if !ok || complexity < threshold {
+			continue
+		}
+
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		lineNumber, _ := ToInt(entity.Properties["lineNumber"])
+		ranked = append(ranked, FunctionComplexity{
+			ID:                   entity.ID,
+			Label:                entity.Label,
+			SourceFile:           sourceFile,
+			LineNumber:           lineNumber,
+			CyclomaticComplexity: complexity,
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.CyclomaticComplexity != b.CyclomaticComplexity {
+			return a.CyclomaticComplexity > b.CyclomaticComplexity
+		}
+		if a.SourceFile != b.SourceFile {
+			return a.SourceFile < b.SourceFile
+		}
+		return a.LineNumber < b.LineNumber
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// ToInt converts a numeric property or count to an int. Values read back from a
+// database may be int (in-memory), int64 (Memgraph) or float64 (JSON).
+func ToInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import "codegraphgen/internal/core/graph"
+
+// Connectivity describes how the entities of a graph are connected, ignoring
+// the direction of the relationships
+type Connectivity struct {
+	// LargestComponentSize is the number of entities of the largest weakly
+	// connected component
+	LargestComponentSize int
+	// IsolatedEntities is the number of entities without relationships
+	IsolatedEntities int
+}
+
+// ComputeConnectivity finds the weakly connected components of a graph with a
+// union-find over its relationships. Relationships to entities that are not in
+// entities are ignored.
+func ComputeConnectivity(entities []graph.Entity, relationships []graph.Relationship) Connectivity {
+	parent := make(map[string]string, len(entities))
+	size := make(map[string]int, len(entities))
+	for _, entity := range entities {
+		parent[entity.ID] = entity.ID
+		size[entity.ID] = 1
+	}
+
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	connected := make(map[string]bool)
+	for _, rel := range relationships {
+		if _, ok := parent[rel.Source]; !ok {
+			continue
+		}
+		if _, ok := parent[rel.Target]; !ok {
+			continue
+		}
+		connected[rel.Source] = true
+		connected[rel.Target] = true
+
+		a, b := find(rel.Source), find(rel.Target)
+		if a == b {
+			continue
+		}
+		// Attach the smaller component to the larger one
+		if size[a] < size[b] {
+			a, b = b, a
+		}
+		parent[b] = a
+		size[a] += size[b]
+	}
+
+	var result Connectivity
+	for id := range parent {
+		if !connected[id] {
+			result.IsolatedEntities++
+		}
+		if find(id) == id && size[id] > result.LargestComponentSize {
+			result.LargestComponentSize = size[id]
+		}
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+package analysis
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// coverBlockRegex matches a block of a Go cover profile:
+// file.go:startLine.startCol,endLine.endCol numStatements count
+var coverBlockRegex = regexp.MustCompile(`^(.+\.go):(\d+)\.(\d+),(\d+)\.(\d+) (\d+) (\d+)$`)
+
+// coverBlock is a block of statements of a Go cover profile
+type coverBlock struct {
+	startLine  int
+	statements int
+	covered    bool
+}
+
+// ParseGoCoverProfile reads a cover profile written by go test -coverprofile and
+// sets the testCoverage property (percent of statements executed) on the Go
+// functions whose line range contains its blocks. Profile paths are import paths,
+// so each is matched to the function source file sharing the longest path suffix
+// with it. Covered functions are linked from the test functions named after them
+// in the same directory with TESTS edges.
+func ParseGoCoverProfile(coverFile string, entities []graph.Entity) ([]graph.Relationship, error) {
+	blocksByPath, err := readGoCoverProfile(coverFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index Go functions by source file
+	functionsByFile := make(map[string][]graph.Entity)
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeFunction || entity.Properties["language"] != "go" || isGoTestFile(entity) {
+			continue
+		}
+		if sourceFile, ok := entity.Properties["sourceFile"].(string); ok {
+			functionsByFile[sourceFile] = append(functionsByFile[sourceFile], entity)
+		}
+	}
+	sourceFiles := make([]string, 0, len(functionsByFile))
+	for sourceFile := range functionsByFile {
+		sourceFiles = append(sourceFiles, sourceFile)
+	}
+
+	var covered []graph.Entity
+	for profilePath, blocks := range blocksByPath {
+		sourceFile, ok := matchCoverPath(profilePath, sourceFiles)
+		if !ok {
+			continue
+		}
+
+		for _, function := range functionsByFile[sourceFile] {
+			start, _ := ToInt(function.Properties["lineNumber"])
+			end, _ := ToInt(function.Properties["endLine"])
+
+			var total, executed int
+			for _, block := range blocks {
+				if block.startLine < start || block.startLine > end {
+					continue
+				}
+				total += block.statements
+				if block.covered {
+					executed += block.statements
+				}
+			}
+			if total == 0 {
+				continue
+			}
+
+			percent := math.Round(float64(executed)/float64(total)*1000) / 10
+			function.Properties["testCoverage"] = percent
+			if executed > 0 {
+				covered = append(covered, function)
+			}
+		}
+	}
+
+	return linkCoveredFunctions(covered, entities), nil
+}
+
+// readGoCoverProfile parses a cover profile into its blocks per file. Blocks
+// repeated by merged profiles count as covered if any run executed them.
+func readGoCoverProfile(coverFile string) (map[string][]coverBlock, error) {
+	file, err := os.Open(coverFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cover profile: %w", err)
+	}
+	defer file.Close()
+
+	type blockKey struct {
+		path string
+		pos  string
+	}
+	index := make(map[blockKey]int)
+	var keys []blockKey
+	var blocks []coverBlock
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		match := coverBlockRegex.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("invalid cover profile line %d: %q", lineNumber, line)
+		}
+		startLine, _ := strconv.Atoi(match[2])
+		statements, _ := strconv.Atoi(match[6])
+		count, _ := strconv.Atoi(match[7])
+
+		key := blockKey{match[1], line[len(match[1])+1 : strings.Index(line, " ")]}
+		if i, ok := index[key]; ok {
+			blocks[i].covered = blocks[i].covered || count > 0
+			continue
+		}
+		index[key] = len(blocks)
+		keys = append(keys, key)
+		blocks = append(blocks, coverBlock{
+			startLine:  startLine,
+			statements: statements,
+			covered:    count > 0,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cover profile: %w", err)
+	}
+
+	blocksByPath := make(map[string][]coverBlock)
+	for i, key := range keys {
+		blocksByPath[key.path] = append(blocksByPath[key.path], blocks[i])
+	}
+	return blocksByPath, nil
+}
+
+// matchCoverPath finds the source file sharing the most trailing path elements
+// with a cover profile path, e.g. "example.com/app/pkg/file.go" matches
+// "/src/app/pkg/file.go". Ties are ambiguous and match nothing.
+func matchCoverPath(profilePath string, sourceFiles []string) (string, bool) {
+	profileParts := strings.Split(profilePath, "/")
+
+	best, bestScore, tie := "", 0, false
+	for _, sourceFile := range sourceFiles {
+		sourceParts := strings.Split(filepath.ToSlash(filepath.Clean(sourceFile)), "/")
+		score := 0
+		for score < len(profileParts) && score < len(sourceParts) &&
+			profileParts[len(profileParts)-1-score] == sourceParts[len(sourceParts)-1-score] {
+			score++
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = sourceFile, score, false
+		case score == bestScore && score > 0:
+			tie = true
+		}
+	}
+	return best, bestScore > 0 && !tie
+}
+
+// linkCoveredFunctions creates TESTS edges to covered functions from the test
+// functions in the same directory whose name refers to them (TestXxx -> Xxx)
+func linkCoveredFunctions(covered, entities []graph.Entity) []graph.Relationship {
+	testsByTarget := make(map[string][]graph.Entity)
+	for _, entity := range entities {
+		if !isCallableEntity(entity) || !isGoTestFile(entity) {
+			continue
+		}
+		if target, property := splitTestName(entity.Label); property == "isTest" && target != "" {
+			key := sourceDir(entity) + "|" + target
+			testsByTarget[key] = append(testsByTarget[key], entity)
+		}
+	}
+
+	var relationships []graph.Relationship
+	for _, function := range covered {
+		dir := sourceDir(function)
+		tests := testsByTarget[dir+"|"+function.Label]
+		// TestXxx also tests unexported xxx
+		if upper := upperFirst(function.Label); upper != function.Label {
+			tests = append(tests, testsByTarget[dir+"|"+upper]...)
+		}
+		for _, test := range tests {
+			relationships = append(relationships, graph.CreateRelationship(
+				test.ID, function.ID, graph.RelationshipTypeTests, graph.Properties{
+					"testKind":     "Test",
+					"testCoverage": function.Properties["testCoverage"],
+				}))
+		}
+	}
+	return relationships
+}
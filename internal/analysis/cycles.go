@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// DetectCircularDependencies finds cycles formed by relationships of the given types.
+// Each cycle is returned once as the entity IDs along the cycle, starting from the
+// smallest ID; the closing edge back to the first entity is implied.
+// A depth-first search reports one cycle per back edge, so every strongly connected
+// component is covered without enumerating all of its elementary cycles.
+func DetectCircularDependencies(kg *graph.KnowledgeGraph, relTypes ...graph.RelationshipType) [][]string {
+	wanted := make(map[graph.RelationshipType]bool, len(relTypes))
+	for _, relType := range relTypes {
+		wanted[relType] = true
+	}
+
+	adjacency := make(map[string][]string)
+	for _, rel := range kg.Relationships {
+		if wanted[rel.Type] {
+			adjacency[rel.Source] = append(adjacency[rel.Source], rel.Target)
+		}
+	}
+
+	// Visit nodes in a stable order so results are deterministic
+	nodes := make([]string, 0, len(adjacency))
+	for node := range adjacency {
+		nodes = append(nodes, node)
+		sort.Strings(adjacency[node])
+	}
+	sort.Strings(nodes)
+
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+	seen := make(map[string]bool)
+
+	var visit func(node string)
+	visit = func(node string) {
+		state[node] = onStack
+		stack = append(stack, node)
+
+		for _, next := range adjacency[node] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case onStack:
+				// Back edge: the stack from next to node is a cycle
+				start := len(stack) - 1
+				for stack[start] != next {
+					start--
+				}
+				cycle := NormalizeCycle(stack[start:])
+				if key := strings.Join(cycle, "|"); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[node] = done
+	}
+
+	for _, node := range nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	return cycles
+}
+
+// NormalizeCycle rotates a cycle so that it starts at its smallest element,
+// making the same cycle found from different starting points compare equal
+func NormalizeCycle(cycle []string) []string {
+	if len(cycle) == 0 {
+		return cycle
+	}
+
+	minIndex := 0
+	for i, id := range cycle {
+		if id < cycle[minIndex] {
+			minIndex = i
+		}
+	}
+
+	normalized := make([]string, 0, len(cycle))
+	normalized = append(normalized, cycle[minIndex:]...)
+	normalized = append(normalized, cycle[:minIndex]...)
+	return normalized
+}
@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// SharedEntityTypes lists the entity types that describe something outside the
+// file they were found in. The same import or dependency seen in many files is
+// one node in the graph, so these are the only types merged by label and type.
+var SharedEntityTypes = map[graph.EntityType]bool{
+	graph.EntityTypeImport:     true,
+	graph.EntityTypeDependency: true,
+}
+
+// DeduplicateByLabelAndType merges entities of a shared type that have the same
+// label, type and module source. Imports of the same name from different modules,
+// e.g. Router from express and from react-router, stay separate entities, and
+// relative sources are resolved against the importing file's directory so that
+// ./utils in two directories isn't taken for one module. The first entity seen survives and keeps the highest confidence
+// of its duplicates. The returned map records oldID -> survivingID for every
+// entity that was merged away, so relationships can be remapped afterwards.
+func DeduplicateByLabelAndType(entities []graph.Entity) ([]graph.Entity, map[string]string) {
+	idMap := make(map[string]string)
+	survivors := make(map[string]int)
+	var unique []graph.Entity
+
+	for _, entity := range entities {
+		if !SharedEntityTypes[entity.Type] {
+			unique = append(unique, entity)
+			continue
+		}
+
+		key := fmt.Sprintf("%s-%s-%s", strings.ToLower(entity.Label), entity.Type, moduleSource(entity))
+		if idx, exists := survivors[key]; exists {
+			survivor := &unique[idx]
+			if entity.Confidence > survivor.Confidence {
+				survivor.Confidence = entity.Confidence
+			}
+			if entity.ID != survivor.ID {
+				idMap[entity.ID] = survivor.ID
+			}
+			continue
+		}
+
+		survivors[key] = len(unique)
+		unique = append(unique, entity)
+	}
+
+	return unique, idMap
+}
+
+// moduleSource returns the module a shared entity comes from, with relative
+// sources resolved against the directory of the importing file
+func moduleSource(entity graph.Entity) string {
+	source, _ := entity.Properties["source"].(string)
+	if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") {
+		return source
+	}
+	sourceFile, _ := entity.Properties["sourceFile"].(string)
+	return filepath.ToSlash(filepath.Join(filepath.Dir(sourceFile), filepath.FromSlash(source)))
+}
+
+// RemapRelationships rewrites relationship endpoints using an oldID -> newID map
+// and drops relationships that become duplicates once their endpoints are merged.
+func RemapRelationships(relationships []graph.Relationship, idMap map[string]string) []graph.Relationship {
+	if len(idMap) == 0 {
+		return relationships
+	}
+
+	seen := make(map[string]bool)
+	remapped := make([]graph.Relationship, 0, len(relationships))
+
+	for _, rel := range relationships {
+		source, sourceMapped := idMap[rel.Source]
+		target, targetMapped := idMap[rel.Target]
+		if !sourceMapped {
+			source = rel.Source
+		}
+		if !targetMapped {
+			target = rel.Target
+		}
+
+		if sourceMapped || targetMapped {
+			updated := graph.CreateRelationship(source, target, rel.Type, rel.Properties)
+			updated.Confidence = rel.Confidence
+			rel = updated
+		}
+
+		if seen[rel.ID] {
+			continue
+		}
+		seen[rel.ID] = true
+		remapped = append(remapped, rel)
+	}
+
+	return remapped
+}
@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// GraphDiff describes the differences between two knowledge graphs.
+// Entities are matched by label and type, since IDs include file paths that
+// differ between the two graphs. Relationships are matched by the label and
+// type of both endpoints plus the relationship type.
+type GraphDiff struct {
+	EntitiesOnlyInA      []graph.Entity       `json:"entitiesOnlyInA"`
+	EntitiesOnlyInB      []graph.Entity       `json:"entitiesOnlyInB"`
+	CommonEntities       []graph.Entity       `json:"commonEntities"`
+	RelationshipsOnlyInA []graph.Relationship `json:"relationshipsOnlyInA"`
+	RelationshipsOnlyInB []graph.Relationship `json:"relationshipsOnlyInB"`
+}
+
+// CompareGraphs computes the differences between graph a and graph b.
+// Entities or relationships repeated under the same key are reported once.
+func CompareGraphs(a, b *graph.KnowledgeGraph) *GraphDiff {
+	diff := &GraphDiff{
+		EntitiesOnlyInA:      []graph.Entity{},
+		EntitiesOnlyInB:      []graph.Entity{},
+		CommonEntities:       []graph.Entity{},
+		RelationshipsOnlyInA: []graph.Relationship{},
+		RelationshipsOnlyInB: []graph.Relationship{},
+	}
+
+	entitiesA := indexEntities(a.Entities)
+	entitiesB := indexEntities(b.Entities)
+
+	for _, entity := range uniqueEntities(a.Entities) {
+		if _, ok := entitiesB[EntityKey(entity)]; ok {
+			diff.CommonEntities = append(diff.CommonEntities, entity)
+		} else {
+			diff.EntitiesOnlyInA = append(diff.EntitiesOnlyInA, entity)
+		}
+	}
+	for _, entity := range uniqueEntities(b.Entities) {
+		if _, ok := entitiesA[EntityKey(entity)]; !ok {
+			diff.EntitiesOnlyInB = append(diff.EntitiesOnlyInB, entity)
+		}
+	}
+
+	relationshipsA := indexRelationships(a.Relationships, a.Entities)
+	relationshipsB := indexRelationships(b.Relationships, b.Entities)
+
+	diff.RelationshipsOnlyInA = relationshipsMissingFrom(a.Relationships, a.Entities, relationshipsB)
+	diff.RelationshipsOnlyInB = relationshipsMissingFrom(b.Relationships, b.Entities, relationshipsA)
+
+	return diff
+}
+
+// EntityKey returns the key used to match an entity across graphs, e.g. "FUNCTION:NewServer"
+func EntityKey(entity graph.Entity) string {
+	return fmt.Sprintf("%s:%s", entity.Type, entity.Label)
+}
+
+// RelationshipKey returns the key used to match a relationship across graphs.
+// It returns false if either endpoint is not among entities.
+func RelationshipKey(rel graph.Relationship, entitiesByID map[string]graph.Entity) (string, bool) {
+	source, ok := entitiesByID[rel.Source]
+	if !ok {
+		return "", false
+	}
+	target, ok := entitiesByID[rel.Target]
+	if !ok {
+		return "", false
+	}
+	return strings.Join([]string{EntityKey(source), string(rel.Type), EntityKey(target)}, "|"), true
+}
+
+// indexEntities indexes entities by their match key
+func indexEntities(entities []graph.Entity) map[string]graph.Entity {
+	index := make(map[string]graph.Entity, len(entities))
+	for _, entity := range entities {
+		if _, exists := index[EntityKey(entity)]; !exists {
+			index[EntityKey(entity)] = entity
+		}
+	}
+	return index
+}
+
+// uniqueEntities returns the first entity for each match key, preserving order
+func uniqueEntities(entities []graph.Entity) []graph.Entity {
+	seen := make(map[string]bool, len(entities))
+	var unique []graph.Entity
+	for _, entity := range entities {
+		key := EntityKey(entity)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, entity)
+	}
+	return unique
+}
+
+// entitiesByID indexes entities by ID
+func entitiesByID(entities []graph.Entity) map[string]graph.Entity {
+	index := make(map[string]graph.Entity, len(entities))
+	for _, entity := range entities {
+		index[entity.ID] = entity
+	}
+	return index
+}
+
+// indexRelationships returns the set of relationship match keys
+func indexRelationships(relationships []graph.Relationship, entities []graph.Entity) map[string]bool {
+	byID := entitiesByID(entities)
+	index := make(map[string]bool, len(relationships))
+	for _, rel := range relationships {
+		if key, ok := RelationshipKey(rel, byID); ok {
+			index[key] = true
+		}
+	}
+	return index
+}
+
+// relationshipsMissingFrom returns relationships whose match key is not in other
+func relationshipsMissingFrom(relationships []graph.Relationship, entities []graph.Entity, other map[string]bool) []graph.Relationship {
+	byID := entitiesByID(entities)
+	seen := make(map[string]bool)
+	missing := []graph.Relationship{}
+	for _, rel := range relationships {
+		key, ok := RelationshipKey(rel, byID)
+		if !ok || other[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		missing = append(missing, rel)
+	}
+	return missing
+}
@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// BuildDirectoryDependencyGraph maps the coupling between directories: for every
+// pair of directories A and B, the relationships from entities in A to entities
+// in B (calls, inheritance, references and so on) are counted into a single
+// DEPENDS_ON edge from A to B whose weight property is the count, with the count
+// per relationship type in relationshipTypes. Edges into a directory that lower
+// layers should not depend on point at layering violations.
+//
+// An entity lies in the directory of its sourceFile, or of its path for files.
+// Directory entities and the imports and dependencies shared across files are
+// not located in a directory; an import couples the files importing it to the
+// files it resolves to instead. Relationships within a directory are not counted.
+//
+// The nodes are the directory entities of the input, or new ones for
+// directories that have none.
+func BuildDirectoryDependencyGraph(entities []graph.Entity, relationships []graph.Relationship) *graph.KnowledgeGraph {
+	directories := make(map[string]graph.Entity)
+	locations := make(map[string]string)
+	for _, entity := range entities {
+		switch {
+		case entity.Type == graph.EntityTypeDirectory:
+			if path, ok := entity.Properties["path"].(string); ok {
+				directories[filepath.Clean(path)] = entity
+			}
+		case SharedEntityTypes[entity.Type]:
+		default:
+			if dir := entityDirectory(entity); dir != "" {
+				locations[entity.ID] = dir
+			}
+		}
+	}
+
+	type edge struct{ source, target string }
+	counts := make(map[edge]map[graph.RelationshipType]int)
+	count := func(sourceDir, targetDir string, relType graph.RelationshipType) {
+		if sourceDir == "" || targetDir == "" || sourceDir == targetDir {
+			return
+		}
+		key := edge{sourceDir, targetDir}
+		if counts[key] == nil {
+			counts[key] = make(map[graph.RelationshipType]int)
+		}
+		counts[key][relType]++
+	}
+
+	// Imports sit between the files importing them and the files they resolve to
+	importers := make(map[string][]string)
+	resolved := make(map[string][]string)
+	for _, rel := range relationships {
+		sourceDir, sourceLocated := locations[rel.Source]
+		targetDir, targetLocated := locations[rel.Target]
+		switch {
+		case sourceLocated && targetLocated:
+			count(sourceDir, targetDir, rel.Type)
+		case rel.Type == graph.RelationshipTypeImports && sourceLocated:
+			importers[rel.Target] = append(importers[rel.Target], sourceDir)
+		case rel.Type == graph.RelationshipTypeReferences && targetLocated:
+			resolved[rel.Source] = append(resolved[rel.Source], targetDir)
+		}
+	}
+	for importID, sourceDirs := range importers {
+		for _, sourceDir := range sourceDirs {
+			for _, targetDir := range resolved[importID] {
+				count(sourceDir, targetDir, graph.RelationshipTypeImports)
+			}
+		}
+	}
+
+	// Build the graph in a stable order so results are deterministic
+	edges := make([]edge, 0, len(counts))
+	for key := range counts {
+		edges = append(edges, key)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].source != edges[j].source {
+			return edges[i].source < edges[j].source
+		}
+		return edges[i].target < edges[j].target
+	})
+
+	result := &graph.KnowledgeGraph{}
+	nodeIDs := make(map[string]string)
+	node := func(dir string) string {
+		if id, ok := nodeIDs[dir]; ok {
+			return id
+		}
+		entity, ok := directories[dir]
+		if !ok {
+			entity = graph.CreateEntity(filepath.Base(dir), graph.EntityTypeDirectory, graph.Properties{
+				"path": dir,
+			})
+		}
+		result.Entities = append(result.Entities, entity)
+		nodeIDs[dir] = entity.ID
+		return entity.ID
+	}
+
+	for _, key := range edges {
+		weight := 0
+		relationshipTypes := make(map[string]interface{})
+		for relType, n := range counts[key] {
+			weight += n
+			relationshipTypes[string(relType)] = n
+		}
+		result.Relationships = append(result.Relationships, graph.CreateRelationship(
+			node(key.source), node(key.target), graph.RelationshipTypeDependsOn, graph.Properties{
+				"weight":            weight,
+				"relationshipTypes": relationshipTypes,
+			}))
+	}
+
+	return result
+}
+
+// entityDirectory returns the directory an entity lies in, or "" when unknown
+func entityDirectory(entity graph.Entity) string {
+	if entity.Type == graph.EntityTypeFile {
+		if path, ok := entity.Properties["path"].(string); ok {
+			return filepath.Dir(filepath.Clean(path))
+		}
+	}
+	if sourceFile, ok := entity.Properties["sourceFile"].(string); ok && sourceFile != "" {
+		return filepath.Dir(filepath.Clean(sourceFile))
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// LinkJavaMethodReferences creates CALLS edges from Java methods to the methods
+// named by their method references, recorded as methodReferences by the Java
+// analyzer. this::name and super::name refer to a method of the same file, and
+// Type::name to a method of the file declaring the class, enum or interface
+// Type. References to types outside the analyzed code, such as String::valueOf,
+// and to constructors (Type::new) are not linked. The edges record the line of
+// the reference as calledAt, like the calls found by the analyzers.
+func LinkJavaMethodReferences(entities []graph.Entity) []graph.Relationship {
+	typeFiles := make(map[string][]string)
+	methods := make(map[string]map[string]string)
+	for _, entity := range entities {
+		if lang, _ := entity.Properties["language"].(string); lang != "java" {
+			continue
+		}
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		switch entity.Type {
+		case graph.EntityTypeClass, graph.EntityTypeInterface, graph.EntityTypeEnum:
+			typeFiles[entity.Label] = append(typeFiles[entity.Label], sourceFile)
+		case graph.EntityTypeMethod:
+			if methods[sourceFile] == nil {
+				methods[sourceFile] = make(map[string]string)
+			}
+			if _, exists := methods[sourceFile][entity.Label]; !exists {
+				methods[sourceFile][entity.Label] = entity.ID
+			}
+		}
+	}
+
+	var relationships []graph.Relationship
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeMethod {
+			continue
+		}
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		lines := intSlice(entity.Properties["methodReferenceLines"])
+		for i, reference := range stringSlice(entity.Properties["methodReferences"]) {
+			receiver, name, ok := strings.Cut(reference, "::")
+			if !ok || name == "new" {
+				continue
+			}
+
+			files := typeFiles[receiver]
+			if receiver == "this" || receiver == "super" {
+				files = []string{sourceFile}
+			}
+			for _, file := range files {
+				if targetID, ok := methods[file][name]; ok && targetID != entity.ID {
+					properties := graph.Properties{"methodReference": reference}
+					if i < len(lines) {
+						properties["calledAt"] = lines[i]
+					}
+					relationships = append(relationships, graph.CreateRelationship(
+						entity.ID, targetID, graph.RelationshipTypeCalls, properties))
+					break
+				}
+			}
+		}
+	}
+
+	return relationships
+}
+
+// intSlice converts a list property to ints, or nil if an item isn't a number.
+// Lists read back from a database are []interface{}.
+func intSlice(value interface{}) []int {
+	switch v := value.(type) {
+	case []int:
+		return v
+	case []interface{}:
+		values := make([]int, 0, len(v))
+		for _, item := range v {
+			n, ok := ToInt(item)
+			if !ok {
+				return nil
+			}
+			values = append(values, n)
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// defaultKubernetesNamespace is the namespace of resources that don't set one
+const defaultKubernetesNamespace = "default"
+
+// clusterScopedKinds are the common Kubernetes kinds that belong to no namespace
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                      true,
+	"Node":                           true,
+	"PersistentVolume":               true,
+	"StorageClass":                   true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"IngressClass":                   true,
+	"PriorityClass":                  true,
+	"MutatingWebhookConfiguration":   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+// LinkKubernetesResources links the Kubernetes resources found by the YAML
+// analyzer across documents and files:
+//   - workloads DEPENDS_ON the Services named by the hosts in their
+//     serviceReferences (api, api.shop or api.shop.svc.cluster.local)
+//   - Ingresses DEPENDS_ON the Services in their backendServices
+//   - ConfigMaps CONFIGURES the workloads listing them in their configMaps
+//
+// Every namespace becomes a NAMESPACE entity that CONTAINS its resources.
+// Resources without a namespace are grouped into "default"; cluster-scoped
+// resources are not grouped.
+func LinkKubernetesResources(entities []graph.Entity) ([]graph.Entity, []graph.Relationship) {
+	var resources []graph.Entity
+	services := make(map[string]string)
+	configMaps := make(map[string]string)
+	for _, entity := range entities {
+		if _, ok := entity.Properties["apiVersion"]; !ok {
+			continue
+		}
+		kind, _ := entity.Properties["kind"].(string)
+		name, _ := entity.Properties["name"].(string)
+		if kind == "" {
+			continue
+		}
+		resources = append(resources, entity)
+
+		key := kubernetesNamespace(entity) + "/" + name
+		switch kind {
+		case "Service":
+			services[key] = entity.ID
+		case "ConfigMap":
+			configMaps[key] = entity.ID
+		}
+	}
+
+	var relationships []graph.Relationship
+	seen := make(map[string]bool)
+	link := func(source, target string, relType graph.RelationshipType) {
+		key := source + "|" + target + "|" + string(relType)
+		if source == target || seen[key] {
+			return
+		}
+		seen[key] = true
+		relationships = append(relationships, graph.CreateRelationship(source, target, relType, nil))
+	}
+
+	namespaceMembers := make(map[string][]string)
+	for _, resource := range resources {
+		namespace := kubernetesNamespace(resource)
+
+		for _, host := range stringSlice(resource.Properties["serviceReferences"]) {
+			name, hostNamespace := splitKubernetesHost(host, namespace)
+			if serviceID, ok := services[hostNamespace+"/"+name]; ok {
+				link(resource.ID, serviceID, graph.RelationshipTypeDependsOn)
+			}
+		}
+		for _, name := range stringSlice(resource.Properties["backendServices"]) {
+			if serviceID, ok := services[namespace+"/"+name]; ok {
+				link(resource.ID, serviceID, graph.RelationshipTypeDependsOn)
+			}
+		}
+		for _, name := range stringSlice(resource.Properties["configMaps"]) {
+			if configMapID, ok := configMaps[namespace+"/"+name]; ok {
+				link(configMapID, resource.ID, graph.RelationshipTypeConfigures)
+			}
+		}
+
+		kind, _ := resource.Properties["kind"].(string)
+		if !clusterScopedKinds[kind] {
+			namespaceMembers[namespace] = append(namespaceMembers[namespace], resource.ID)
+		}
+	}
+
+	// Create the namespaces in a stable order so results are deterministic
+	names := make([]string, 0, len(namespaceMembers))
+	for name := range namespaceMembers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var namespaces []graph.Entity
+	for _, name := range names {
+		namespace := graph.CreateEntity(name, graph.EntityTypeNamespace, graph.Properties{
+			"kind":     "kubernetesNamespace",
+			"language": "yaml",
+		})
+		namespaces = append(namespaces, namespace)
+		for _, member := range namespaceMembers[name] {
+			link(namespace.ID, member, graph.RelationshipTypeContains)
+		}
+	}
+
+	return namespaces, relationships
+}
+
+// kubernetesNamespace returns the namespace of a resource
+func kubernetesNamespace(resource graph.Entity) string {
+	if namespace, _ := resource.Properties["namespace"].(string); namespace != "" {
+		return namespace
+	}
+	return defaultKubernetesNamespace
+}
+
+// splitKubernetesHost splits a service DNS name into the service name and its
+// namespace, which defaults to the namespace of the referencing resource
+func splitKubernetesHost(host, namespace string) (string, string) {
+	parts := strings.Split(host, ".")
+	if len(parts) > 1 {
+		return parts[0], parts[1]
+	}
+	return parts[0], namespace
+}
@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// languageReportTopTypes is the number of most common entity types listed per
+// language
+const languageReportTopTypes = 3
+
+// unknownLanguage groups the files whose language isn't detected
+const unknownLanguage = "unknown"
+
+// AnalyzeLanguages summarizes the files of an analyzed codebase: the number of
+// files and lines, the files per language and extension, and a report for each
+// language. Files and lines are read from the FILE entities, so only files that
+// were analyzed are counted.
+func AnalyzeLanguages(entities []graph.Entity) *graph.CodebaseAnalysis {
+	result := &graph.CodebaseAnalysis{
+		Languages:       make(map[string]int),
+		FileTypes:       make(map[string]int),
+		LanguageReports: LanguageStatistics(entities),
+	}
+
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeFile {
+			continue
+		}
+		result.TotalFiles++
+		lines, _ := ToInt(entity.Properties["lineCount"])
+		result.TotalLines += lines
+
+		extension, _ := entity.Properties["extension"].(string)
+		if extension == "" {
+			// Files recognized by name, such as Makefile
+			extension = filepath.Base(entity.Label)
+		}
+		result.FileTypes[extension]++
+	}
+	for _, report := range result.LanguageReports {
+		if report.Files > 0 {
+			result.Languages[report.Language] = report.Files
+		}
+	}
+
+	return result
+}
+
+// LanguageStatistics reports per language the number of files, their total
+// lines, the number of entities declared in them and their most common entity
+// types, ordered by number of files and then by name. Entities without a
+// language, such as directories, are not counted.
+func LanguageStatistics(entities []graph.Entity) []graph.LanguageReport {
+	reports := make(map[string]*graph.LanguageReport)
+	typeCounts := make(map[string]map[string]int)
+	report := func(language string) *graph.LanguageReport {
+		if reports[language] == nil {
+			reports[language] = &graph.LanguageReport{Language: language}
+			typeCounts[language] = make(map[string]int)
+		}
+		return reports[language]
+	}
+
+	for _, entity := range entities {
+		language, _ := entity.Properties["language"].(string)
+		switch {
+		case entity.Type == graph.EntityTypeFile:
+			if language == "" {
+				language = unknownLanguage
+			}
+			lines, _ := ToInt(entity.Properties["lineCount"])
+			r := report(language)
+			r.Files++
+			r.Lines += lines
+		case language != "":
+			report(language).Entities++
+			typeCounts[language][string(entity.Type)]++
+		}
+	}
+
+	result := make([]graph.LanguageReport, 0, len(reports))
+	for language, r := range reports {
+		r.TopEntityTypes = topEntityTypes(typeCounts[language], languageReportTopTypes)
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Files != result[j].Files {
+			return result[i].Files > result[j].Files
+		}
+		return result[i].Language < result[j].Language
+	})
+	return result
+}
+
+// topEntityTypes returns the limit most common entity types, most common first
+// and ties ordered by name
+func topEntityTypes(counts map[string]int, limit int) []graph.EntityTypeCount {
+	types := make([]graph.EntityTypeCount, 0, len(counts))
+	for entityType, count := range counts {
+		types = append(types, graph.EntityTypeCount{Type: entityType, Count: count})
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Count != types[j].Count {
+			return types[i].Count > types[j].Count
+		}
+		return types[i].Type < types[j].Type
+	})
+	if len(types) > limit {
+		types = types[:limit]
+	}
+	return types
+}
@@ -0,0 +1,39 @@
+package analysis
+
+import (
+	"path/filepath"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// LinkNamespaceDirectories links NAMESPACE entities that are mapped to source
+// directories, such as the PSR-4 autoload namespaces of a composer.json, to the
+// DIRECTORY entities of those directories with CONTAINS edges. Directories
+// without an entity, e.g. because they hold no analyzed files, are skipped.
+func LinkNamespaceDirectories(entities []graph.Entity) []graph.Relationship {
+	directoryIDs := make(map[string]string)
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeDirectory {
+			continue
+		}
+		if path, ok := entity.Properties["path"].(string); ok {
+			directoryIDs[filepath.Clean(path)] = entity.ID
+		}
+	}
+
+	var relationships []graph.Relationship
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeNamespace {
+			continue
+		}
+		for _, dir := range stringSlice(entity.Properties["directories"]) {
+			if directoryID, ok := directoryIDs[filepath.Clean(dir)]; ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					entity.ID, directoryID, graph.RelationshipTypeContains, graph.Properties{
+						"autoload": entity.Properties["autoload"],
+					}))
+			}
+		}
+	}
+	return relationships
+}
@@ -0,0 +1,234 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// LinkPythonPackages creates a MODULE entity for every Python package, i.e. every
+// directory containing an __init__.py. The module CONTAINS the .py files of its
+// directory and EXPORTS the entities named in the reExports property that the
+// Python analyzer records on __init__.py: classes and functions defined in the
+// package, its submodules (name.py) and its subpackages. Names imported from a
+// module (reExportModules), such as X of "from .sub.module import X", are looked
+// up in that module first.
+func LinkPythonPackages(entities []graph.Entity) ([]graph.Entity, []graph.Relationship) {
+	// Index the Python files and definitions of each directory
+	filesByDir := make(map[string][]graph.Entity)
+	definitionsByDir := make(map[string]map[string]string)
+	definitionsByFile := make(map[string]map[string]string)
+	for _, entity := range entities {
+		switch entity.Type {
+		case graph.EntityTypeFile:
+			path, _ := entity.Properties["path"].(string)
+			if filepath.Ext(path) == ".py" {
+				dir := filepath.Dir(path)
+				filesByDir[dir] = append(filesByDir[dir], entity)
+			}
+		case graph.EntityTypeClass, graph.EntityTypeFunction:
+			if lang, _ := entity.Properties["language"].(string); lang != "python" {
+				continue
+			}
+			sourceFile, _ := entity.Properties["sourceFile"].(string)
+			dir := filepath.Dir(sourceFile)
+			if definitionsByDir[dir] == nil {
+				definitionsByDir[dir] = make(map[string]string)
+			}
+			if _, exists := definitionsByDir[dir][entity.Label]; !exists {
+				definitionsByDir[dir][entity.Label] = entity.ID
+			}
+			if definitionsByFile[sourceFile] == nil {
+				definitionsByFile[sourceFile] = make(map[string]string)
+			}
+			if _, exists := definitionsByFile[sourceFile][entity.Label]; !exists {
+				definitionsByFile[sourceFile][entity.Label] = entity.ID
+			}
+		}
+	}
+
+	var modules []graph.Entity
+	var relationships []graph.Relationship
+	moduleIDs := make(map[string]string)
+	initFiles := make(map[string]graph.Entity)
+
+	// Visit directories in a stable order so results are deterministic
+	dirs := make([]string, 0, len(filesByDir))
+	for dir := range filesByDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		files := filesByDir[dir]
+		for _, file := range files {
+			if file.Label != "__init__.py" {
+				continue
+			}
+			initPath, _ := file.Properties["path"].(string)
+			module := graph.CreateEntity(filepath.Base(dir), graph.EntityTypeModule, graph.Properties{
+				"path":       dir,
+				"sourceFile": initPath,
+				"isPackage":  true,
+				"language":   "python",
+			})
+			modules = append(modules, module)
+			moduleIDs[dir] = module.ID
+			initFiles[dir] = file
+
+			for _, member := range files {
+				relationships = append(relationships, graph.CreateRelationship(
+					module.ID, member.ID, graph.RelationshipTypeContains, nil))
+			}
+			break
+		}
+	}
+
+	for _, dir := range dirs {
+		initFile, ok := initFiles[dir]
+		if !ok {
+			continue
+		}
+		reExportModules := stringSlice(initFile.Properties["reExportModules"])
+		for i, name := range stringSlice(initFile.Properties["reExports"]) {
+			var targetID string
+			if i < len(reExportModules) && reExportModules[i] != "" {
+				modulePath := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(reExportModules[i], ".", "/")))
+				targetID = pythonModuleExport(modulePath, name, definitionsByFile, moduleIDs, filesByDir)
+			}
+			if targetID == "" {
+				targetID = definitionsByDir[dir][name]
+			}
+			if targetID == "" {
+				targetID = moduleIDs[filepath.Join(dir, name)]
+			}
+			if targetID == "" {
+				for _, file := range filesByDir[dir] {
+					if file.Label == name+".py" {
+						targetID = file.ID
+						break
+					}
+				}
+			}
+			if targetID != "" {
+				relationships = append(relationships, graph.CreateRelationship(
+					moduleIDs[dir], targetID, graph.RelationshipTypeExports, graph.Properties{
+						"exportedName": name,
+					}))
+			}
+		}
+	}
+
+	return modules, relationships
+}
+
+// pythonModuleExport returns the ID of the entity name imported from the module
+// at modulePath (without .py): a class or function of modulePath.py or of the
+// package's __init__.py, or a subpackage or submodule of the package. It
+// returns "" if the module has no such entity.
+func pythonModuleExport(modulePath, name string, definitionsByFile map[string]map[string]string,
+	moduleIDs map[string]string, filesByDir map[string][]graph.Entity) string {
+	if id := definitionsByFile[modulePath+".py"][name]; id != "" {
+		return id
+	}
+	if id := definitionsByFile[filepath.Join(modulePath, "__init__.py")][name]; id != "" {
+		return id
+	}
+	if id := moduleIDs[filepath.Join(modulePath, name)]; id != "" {
+		return id
+	}
+	for _, file := range filesByDir[modulePath] {
+		if file.Label == name+".py" {
+			return file.ID
+		}
+	}
+	return ""
+}
+
+// LinkPythonDependencies links Python packages to the dependencies declared by
+// requirements files, Pipfiles and pyproject.toml files: the __init__.py of the
+// nearest package DEPENDS_ON each dependency of the file. The nearest package is
+// in the file's directory or an enclosing one, or else the shallowest packages
+// below it, e.g. src/mypackage for a requirements.txt at the project root.
+// Editable installs of local directories (-e .) link the file to the nearest
+// packages of the installed directory with an editable DEPENDS_ON edge. Runs
+// after LinkPythonPackages, whose MODULE entities are the local packages.
+func LinkPythonDependencies(entities []graph.Entity) []graph.Relationship {
+	initFiles := make(map[string]string)
+	modules := make(map[string]string)
+	for _, entity := range entities {
+		path, _ := entity.Properties["path"].(string)
+		switch {
+		case entity.Type == graph.EntityTypeFile && entity.Label == "__init__.py":
+			initFiles[filepath.Dir(path)] = entity.ID
+		case entity.Type == graph.EntityTypeModule && entity.Properties["isPackage"] == true:
+			modules[path] = entity.ID
+		}
+	}
+	if len(initFiles) == 0 {
+		return nil
+	}
+
+	var relationships []graph.Relationship
+	for _, entity := range entities {
+		switch entity.Type {
+		case graph.EntityTypeDependency:
+			if language, _ := entity.Properties["language"].(string); language != "python" {
+				continue
+			}
+			sourceFile, _ := entity.Properties["sourceFile"].(string)
+			for _, dir := range nearestPythonPackages(filepath.Dir(sourceFile), initFiles) {
+				relationships = append(relationships, graph.CreateRelationship(
+					initFiles[dir], entity.ID, graph.RelationshipTypeDependsOn, nil))
+			}
+		case graph.EntityTypeFile:
+			for _, install := range stringSlice(entity.Properties["editableInstalls"]) {
+				for _, dir := range nearestPythonPackages(filepath.Clean(install), initFiles) {
+					if moduleID, ok := modules[dir]; ok {
+						relationships = append(relationships, graph.CreateRelationship(
+							entity.ID, moduleID, graph.RelationshipTypeDependsOn, graph.Properties{
+								"isEditable": true,
+							}))
+					}
+				}
+			}
+		}
+	}
+
+	return relationships
+}
+
+// nearestPythonPackages returns the package directories nearest to dir: dir or
+// its closest enclosing package, or else the shallowest packages below dir, in
+// alphabetical order
+func nearestPythonPackages(dir string, packageDirs map[string]string) []string {
+	for current := dir; ; current = filepath.Dir(current) {
+		if _, ok := packageDirs[current]; ok {
+			return []string{current}
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	var nearest []string
+	bestDepth := -1
+	for packageDir := range packageDirs {
+		rel, err := filepath.Rel(dir, packageDir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		depth := strings.Count(rel, string(filepath.Separator))
+		switch {
+		case bestDepth < 0 || depth < bestDepth:
+			nearest = []string{packageDir}
+			bestDepth = depth
+		case depth == bestDepth:
+			nearest = append(nearest, packageDir)
+		}
+	}
+	sort.Strings(nearest)
+	return nearest
+}
@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// typeScriptResolveSuffixes are tried in order when resolving a relative module specifier,
+// mirroring the lookup order of the TypeScript compiler
+var typeScriptResolveSuffixes = []string{
+	"",
+	".ts",
+	".tsx",
+	".js",
+	".jsx",
+	"/index.ts",
+	"/index.tsx",
+	"/index.js",
+	"/index.jsx",
+}
+
+// ResolveTypeScriptImports links relative TypeScript/JavaScript imports to the file they load.
+// For each import entity whose source starts with ./ or ../, the path is resolved against the
+// directory of the importing file and the TypeScript extensions are tried until a file entity
+// under rootDir matches. Each match yields a REFERENCES edge from the import to the file.
+// Import entities must carry their sourceFile, so this runs before imports are deduplicated.
+func ResolveTypeScriptImports(entities []graph.Entity, rootDir string) []graph.Relationship {
+	root := filepath.Clean(rootDir)
+	files := typeScriptFileIndex(entities)
+
+	var relationships []graph.Relationship
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeImport {
+			continue
+		}
+		source, _ := entity.Properties["source"].(string)
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		if path, fileID, ok := resolveTypeScriptModule(files, root, sourceFile, source); ok {
+			relationships = append(relationships, graph.CreateRelationship(
+				entity.ID, fileID, graph.RelationshipTypeReferences, graph.Properties{
+					"resolvedPath": path,
+				}))
+		}
+	}
+
+	return relationships
+}
+
+// typeScriptReExportTargets are the entity types a TypeScript module can export
+var typeScriptReExportTargets = map[graph.EntityType]bool{
+	graph.EntityTypeClass:     true,
+	graph.EntityTypeFunction:  true,
+	graph.EntityTypeInterface: true,
+	graph.EntityTypeType:      true,
+	graph.EntityTypeVariable:  true,
+	graph.EntityTypeConstant:  true,
+	graph.EntityTypeEnum:      true,
+	graph.EntityTypeNamespace: true,
+}
+
+// LinkTypeScriptReExports links the files re-exporting names from relative
+// modules to what they export. For export { Foo, Bar as Baz } from './module'
+// the file EXPORTS the Foo and Bar declared in the resolved module, with the
+// name they are exported under; names the module doesn't declare itself, e.g.
+// because it re-exports them in turn, are linked to the module file. For
+// export * from './module' the file EXPORTS the module file.
+func LinkTypeScriptReExports(entities []graph.Entity, rootDir string) []graph.Relationship {
+	root := filepath.Clean(rootDir)
+	files := typeScriptFileIndex(entities)
+
+	// Index the declarations of each file by name
+	declarations := make(map[string]map[string]string)
+	for _, entity := range entities {
+		if !typeScriptReExportTargets[entity.Type] {
+			continue
+		}
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		if sourceFile == "" {
+			continue
+		}
+		path := filepath.Clean(sourceFile)
+		if declarations[path] == nil {
+			declarations[path] = make(map[string]string)
+		}
+		if _, exists := declarations[path][entity.Label]; !exists {
+			declarations[path][entity.Label] = entity.ID
+		}
+	}
+
+	var relationships []graph.Relationship
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeExport {
+			continue
+		}
+		if isReExport, _ := entity.Properties["isReExport"].(bool); !isReExport {
+			continue
+		}
+		source, _ := entity.Properties["source"].(string)
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		path, moduleID, ok := resolveTypeScriptModule(files, root, sourceFile, source)
+		fileID, exporting := files[filepath.Clean(sourceFile)]
+		if !ok || !exporting {
+			continue
+		}
+
+		properties := graph.Properties{}
+		if exportedAs, _ := entity.Properties["exportedAs"].(string); exportedAs != "" {
+			properties["exportedName"] = exportedAs
+		}
+		targetID := moduleID
+		if isExportAll, _ := entity.Properties["isExportAll"].(bool); isExportAll {
+			properties["exportAll"] = true
+		} else if name, _ := entity.Properties["name"].(string); declarations[path][name] != "" {
+			targetID = declarations[path][name]
+		}
+		relationships = append(relationships, graph.CreateRelationship(
+			fileID, targetID, graph.RelationshipTypeExports, properties))
+	}
+
+	return relationships
+}
+
+// typeScriptFileIndex indexes the file entities by cleaned path
+func typeScriptFileIndex(entities []graph.Entity) map[string]string {
+	files := make(map[string]string)
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeFile {
+			continue
+		}
+		if path, ok := entity.Properties["path"].(string); ok {
+			files[filepath.Clean(path)] = entity.ID
+		}
+	}
+	return files
+}
+
+// resolveTypeScriptModule resolves a relative module specifier of sourceFile to
+// a file entity under root, returning its path and ID
+func resolveTypeScriptModule(files map[string]string, root, sourceFile, source string) (string, string, bool) {
+	if sourceFile == "" || !(strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")) {
+		return "", "", false
+	}
+
+	base := filepath.Join(filepath.Dir(sourceFile), filepath.FromSlash(source))
+	if !isWithinDir(base, root) {
+		return "", "", false
+	}
+
+	for _, suffix := range typeScriptResolveSuffixes {
+		candidate := filepath.Clean(base + filepath.FromSlash(suffix))
+		if fileID, ok := files[candidate]; ok {
+			return candidate, fileID, true
+		}
+	}
+	return "", "", false
+}
+
+// isWithinDir reports whether path lies inside dir
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
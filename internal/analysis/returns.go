@@ -0,0 +1,184 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// typeDefinitionTypes are the entity types a function can return
+var typeDefinitionTypes = map[graph.EntityType]bool{
+	graph.EntityTypeClass:     true,
+	graph.EntityTypeInterface: true,
+	graph.EntityTypeType:      true,
+	graph.EntityTypeEnum:      true,
+}
+
+// typeScriptWrapperTypes are generic types whose type argument is what a
+// TypeScript function effectively returns
+var typeScriptWrapperTypes = []string{"Promise<", "Array<", "ReadonlyArray<"}
+
+// LinkReturnTypes links functions and methods to the types they return.
+// Go functions are read from their returnTypes property, other languages from
+// returnType. Pointers, slices, maps, TypeScript arrays, unions and Promise<T>
+// are unwrapped to the named type, which is matched against classes,
+// interfaces, types and enums of the same language. Definitions in the
+// function's own directory win, and a package qualifier like graph.Entity
+// selects definitions in a directory of that name. Each match yields a RETURNS
+// edge recording the return position, so multi-return Go functions keep the
+// order of their results.
+func LinkReturnTypes(entities []graph.Entity) []graph.Relationship {
+	definitions := make(map[string][]graph.Entity)
+	for _, entity := range entities {
+		if typeDefinitionTypes[entity.Type] {
+			key := returnTypeKey(entity, entity.Label)
+			definitions[key] = append(definitions[key], entity)
+		}
+	}
+
+	var relationships []graph.Relationship
+	for _, entity := range entities {
+		if !isCallableEntity(entity) {
+			continue
+		}
+
+		for position, returnType := range declaredReturnTypes(entity) {
+			for _, name := range returnTypeNames(returnType) {
+				qualifier, typeName := splitQualifiedType(name)
+				for _, definition := range selectReturnDefinitions(entity, qualifier, definitions[returnTypeKey(entity, typeName)]) {
+					if definition.ID == entity.ID {
+						continue
+					}
+					relationships = append(relationships, graph.CreateRelationship(
+						entity.ID, definition.ID, graph.RelationshipTypeReturns, graph.Properties{
+							"position":   position,
+							"returnType": returnType,
+						}))
+				}
+			}
+		}
+	}
+
+	return relationships
+}
+
+// declaredReturnTypes returns the return types of a function entity in order
+func declaredReturnTypes(entity graph.Entity) []string {
+	if returnTypes := stringSlice(entity.Properties["returnTypes"]); len(returnTypes) > 0 {
+		return returnTypes
+	}
+	returnType, _ := entity.Properties["returnType"].(string)
+	switch strings.TrimSpace(returnType) {
+	case "", "unknown", "void", "any":
+		return nil
+	}
+	return []string{returnType}
+}
+
+// returnTypeNames extracts the named types referenced by a return type, e.g.
+// "*graph.KnowledgeGraph" -> ["graph.KnowledgeGraph"], "map[string]User" ->
+// ["User"] and "Promise<User | null>" -> ["User", "null"]
+func returnTypeNames(returnType string) []string {
+	returnType = strings.TrimSpace(returnType)
+
+	for _, wrapper := range typeScriptWrapperTypes {
+		if strings.HasPrefix(returnType, wrapper) && strings.HasSuffix(returnType, ">") {
+			return returnTypeNames(returnType[len(wrapper) : len(returnType)-1])
+		}
+	}
+
+	if strings.Contains(returnType, "|") {
+		var names []string
+		for _, member := range strings.Split(returnType, "|") {
+			names = append(names, returnTypeNames(member)...)
+		}
+		return names
+	}
+
+	for {
+		trimmed := strings.TrimSpace(returnType)
+		switch {
+		case strings.HasPrefix(trimmed, "*"):
+			trimmed = trimmed[1:]
+		case strings.HasPrefix(trimmed, "[]"):
+			trimmed = trimmed[2:]
+		case strings.HasPrefix(trimmed, "..."):
+			trimmed = trimmed[3:]
+		case strings.HasPrefix(trimmed, "<-chan "):
+			trimmed = trimmed[len("<-chan "):]
+		case strings.HasPrefix(trimmed, "chan<- "):
+			trimmed = trimmed[len("chan<- "):]
+		case strings.HasPrefix(trimmed, "chan "):
+			trimmed = trimmed[len("chan "):]
+		case strings.HasPrefix(trimmed, "map["):
+			// The value type of a map; keys are rarely what a function produces
+			if end := strings.Index(trimmed, "]"); end != -1 {
+				trimmed = trimmed[end+1:]
+			}
+		case strings.HasSuffix(trimmed, "[]"):
+			trimmed = strings.TrimSuffix(trimmed, "[]")
+		}
+		if trimmed == strings.TrimSpace(returnType) {
+			break
+		}
+		returnType = trimmed
+	}
+
+	// Drop type arguments: List<T>, Set[T]
+	if idx := strings.IndexAny(returnType, "<["); idx > 0 {
+		returnType = returnType[:idx]
+	}
+	returnType = strings.TrimSpace(returnType)
+	if returnType == "" {
+		return nil
+	}
+	return []string{returnType}
+}
+
+// splitQualifiedType splits a package qualifier from a type name,
+// e.g. "graph.Entity" -> ("graph", "Entity")
+func splitQualifiedType(name string) (string, string) {
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// selectReturnDefinitions narrows the definitions matching a return type name
+// to those in the function's own directory or, for qualified names, a
+// directory named like the qualifier. Ambiguous names keep all candidates.
+func selectReturnDefinitions(function graph.Entity, qualifier string, candidates []graph.Entity) []graph.Entity {
+	if len(candidates) <= 1 {
+		return candidates
+	}
+
+	functionDir := sourceDir(function)
+	var selected []graph.Entity
+	for _, candidate := range candidates {
+		dir := sourceDir(candidate)
+		if qualifier != "" && filepath.Base(dir) == qualifier || qualifier == "" && dir == functionDir {
+			selected = append(selected, candidate)
+		}
+	}
+	if len(selected) == 0 {
+		return candidates
+	}
+	return selected
+}
+
+// returnTypeKey indexes type definitions by language family and name, so that
+// TypeScript and JavaScript code can refer to each other's types
+func returnTypeKey(entity graph.Entity, name string) string {
+	language, _ := entity.Properties["language"].(string)
+	if language == "javascript" {
+		language = "typescript"
+	}
+	return language + "|" + name
+}
+
+// sourceDir returns the directory of the file an entity was declared in
+func sourceDir(entity graph.Entity) string {
+	sourceFile, _ := entity.Properties["sourceFile"].(string)
+	return filepath.Dir(sourceFile)
+}
@@ -0,0 +1,122 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// LinkRustImplementations links Rust types to the traits they implement and
+// the methods of their impl blocks. For every "Trait for Type" recorded in the
+// traitImpls of a file, the struct, enum or union Type gets an IMPLEMENTS edge
+// to Trait; traits not declared in the analyzed code, such as fmt::Display,
+// become INTERFACE entities flagged with isExternal, one per trait path. Every
+// method of an impl block is CONTAINS-ed by its implType. Types are matched by
+// name, preferring those of the same file and then of the same directory;
+// types declared outside the analyzed code are not linked.
+func LinkRustImplementations(entities []graph.Entity) ([]graph.Entity, []graph.Relationship) {
+	types := make(map[string][]graph.Entity)
+	traits := make(map[string][]graph.Entity)
+	for _, entity := range entities {
+		if lang, _ := entity.Properties["language"].(string); lang != "rust" {
+			continue
+		}
+		switch entity.Type {
+		case graph.EntityTypeClass, graph.EntityTypeEnum:
+			types[entity.Label] = append(types[entity.Label], entity)
+		case graph.EntityTypeInterface:
+			traits[entity.Label] = append(traits[entity.Label], entity)
+		}
+	}
+
+	var newEntities []graph.Entity
+	var relationships []graph.Relationship
+	externalTraits := make(map[string]string)
+
+	for _, entity := range entities {
+		switch {
+		case entity.Type == graph.EntityTypeFile:
+			path, _ := entity.Properties["path"].(string)
+			for _, traitImpl := range stringSlice(entity.Properties["traitImpls"]) {
+				traitPath, typeName, ok := strings.Cut(traitImpl, " for ")
+				if !ok {
+					continue
+				}
+				implType, ok := closestRustDefinition(types[typeName], path)
+				if !ok {
+					continue
+				}
+
+				traitName := traitPath[strings.LastIndex(traitPath, "::")+1:]
+				traitName = strings.TrimPrefix(traitName, ":")
+				traitID := ""
+				if trait, ok := closestRustDefinition(traits[traitName], path); ok {
+					traitID = trait.ID
+				} else if id, exists := externalTraits[traitPath]; exists {
+					traitID = id
+				} else {
+					trait := graph.CreateEntity(traitName, graph.EntityTypeInterface, graph.Properties{
+						"path":       traitPath,
+						"language":   "rust",
+						"isExternal": true,
+					})
+					newEntities = append(newEntities, trait)
+					externalTraits[traitPath] = trait.ID
+					traitID = trait.ID
+				}
+				relationships = append(relationships, graph.CreateRelationship(
+					implType.ID, traitID, graph.RelationshipTypeImplements, graph.Properties{
+						"sourceFile": path,
+					}))
+			}
+
+		case entity.Type == graph.EntityTypeMethod:
+			typeName, _ := entity.Properties["implType"].(string)
+			if typeName == "" {
+				continue
+			}
+			sourceFile, _ := entity.Properties["sourceFile"].(string)
+			if implType, ok := closestRustDefinition(types[typeName], sourceFile); ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					implType.ID, entity.ID, graph.RelationshipTypeContains, nil))
+			}
+		}
+	}
+
+	return newEntities, relationships
+}
+
+// closestRustDefinition picks the definition declared in file, or else in its
+// directory, or else the first by source file of the candidates
+func closestRustDefinition(candidates []graph.Entity, file string) (graph.Entity, bool) {
+	if len(candidates) == 0 {
+		return graph.Entity{}, false
+	}
+
+	sorted := make([]graph.Entity, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rustSourceFile(sorted[i]) < rustSourceFile(sorted[j])
+	})
+
+	dir := filepath.Dir(file)
+	for _, candidate := range sorted {
+		if rustSourceFile(candidate) == file {
+			return candidate, true
+		}
+	}
+	for _, candidate := range sorted {
+		if filepath.Dir(rustSourceFile(candidate)) == dir {
+			return candidate, true
+		}
+	}
+	return sorted[0], true
+}
+
+// rustSourceFile returns the source file of an entity
+func rustSourceFile(entity graph.Entity) string {
+	sourceFile, _ := entity.Properties["sourceFile"].(string)
+	return sourceFile
+}
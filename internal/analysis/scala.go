@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"codegraphgen/internal/core/graph"
+)
+
+// LinkScalaSupertypes links Scala classes, traits and objects to their parents:
+// an INHERITS_FROM edge to the type named in their extends clause and an
+// IMPLEMENTS edge to each trait mixed in with a with clause. Parents are
+// matched by name against the Scala classes and traits of the codebase,
+// preferring those in the same directory; parents declared outside of it, such
+// as library traits, are not linked.
+func LinkScalaSupertypes(entities []graph.Entity) []graph.Relationship {
+	definitions := make(map[string][]graph.Entity)
+	for _, entity := range entities {
+		if isScalaTypeEntity(entity) {
+			definitions[entity.Label] = append(definitions[entity.Label], entity)
+		}
+	}
+
+	var relationships []graph.Relationship
+	link := func(entity graph.Entity, parent string, relType graph.RelationshipType) {
+		qualifier, name := splitQualifiedType(parent)
+		for _, definition := range selectReturnDefinitions(entity, qualifier, definitions[name]) {
+			if definition.ID == entity.ID || definition.Properties["kind"] == "object" {
+				continue
+			}
+			relationships = append(relationships, graph.CreateRelationship(
+				entity.ID, definition.ID, relType, nil))
+		}
+	}
+
+	for _, entity := range entities {
+		if !isScalaTypeEntity(entity) {
+			continue
+		}
+		if parent, ok := entity.Properties["extends"].(string); ok && parent != "" {
+			link(entity, parent, graph.RelationshipTypeInheritsFrom)
+		}
+		for _, mixin := range stringSlice(entity.Properties["mixins"]) {
+			link(entity, mixin, graph.RelationshipTypeImplements)
+		}
+	}
+
+	return relationships
+}
+
+// isScalaTypeEntity reports whether an entity is a Scala class, trait or object
+func isScalaTypeEntity(entity graph.Entity) bool {
+	language, _ := entity.Properties["language"].(string)
+	return language == "scala" && (entity.Type == graph.EntityTypeClass || entity.Type == graph.EntityTypeInterface)
+}
@@ -0,0 +1,383 @@
+package analysis
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"codegraphgen/internal/core/analyzers"
+	"codegraphgen/internal/core/graph"
+)
+
+// goTestPrefixes maps Go test function prefixes to the property flagging them
+var goTestPrefixes = []struct {
+	prefix   string
+	property string
+}{
+	{"Benchmark", "isBenchmark"},
+	{"Example", "isExample"},
+	{"Test", "isTest"},
+}
+
+// LinkTestsToCode links Go test functions to the code they exercise.
+// A function named TestXxx, BenchmarkXxx or ExampleXxx declared in a _test.go file
+// is assumed to test the function or method named Xxx (or xxx for unexported code).
+// Test functions are flagged with isTest, isBenchmark or isExample properties.
+func LinkTestsToCode(entities []graph.Entity) []graph.Relationship {
+	var relationships []graph.Relationship
+
+	// Index production functions and methods by label
+	candidates := make(map[string][]graph.Entity)
+	for _, entity := range entities {
+		if !isCallableEntity(entity) || isGoTestFile(entity) {
+			continue
+		}
+		candidates[entity.Label] = append(candidates[entity.Label], entity)
+	}
+
+	for _, entity := range entities {
+		if !isCallableEntity(entity) || !isGoTestFile(entity) {
+			continue
+		}
+
+		target, property := splitTestName(entity.Label)
+		if property == "" {
+			continue
+		}
+
+		entity.Properties["isTest"] = property == "isTest"
+		entity.Properties["isBenchmark"] = property == "isBenchmark"
+		entity.Properties["isExample"] = property == "isExample"
+
+		if target == "" {
+			continue
+		}
+
+		matches := candidates[target]
+		if len(matches) == 0 {
+			matches = candidates[lowerFirst(target)]
+		}
+
+		for _, tested := range matches {
+			relationships = append(relationships, graph.CreateRelationship(
+				entity.ID, tested.ID, graph.RelationshipTypeTests, graph.Properties{
+					"testKind": strings.TrimPrefix(property, "is"),
+				}))
+		}
+	}
+
+	return append(relationships, linkTestFiles(entities)...)
+}
+
+// linkTestFiles links Go test files to the production file with the same base
+// name in the same directory, e.g. foo_test.go -> foo.go
+func linkTestFiles(entities []graph.Entity) []graph.Relationship {
+	var relationships []graph.Relationship
+
+	filesByPath := make(map[string]graph.Entity)
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeFile {
+			continue
+		}
+		if path, ok := entity.Properties["path"].(string); ok {
+			filesByPath[path] = entity
+		}
+	}
+
+	for _, testFile := range entities {
+		path, _ := testFile.Properties["path"].(string)
+		if testFile.Type != graph.EntityTypeFile || !strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		production, ok := filesByPath[strings.TrimSuffix(path, "_test.go")+".go"]
+		if !ok {
+			continue
+		}
+
+		relationships = append(relationships, graph.CreateRelationship(
+			testFile.ID, production.ID, graph.RelationshipTypeTests, graph.Properties{
+				"testKind": "File",
+			}))
+	}
+
+	return relationships
+}
+
+// goTestCase is a case of a table-driven test
+type goTestCase struct {
+	name string
+	line int
+}
+
+// ExtractGoTestCases extracts the cases of table-driven Go tests: every element
+// of a slice of structs, like tests := []struct{...}{...}, declared in a TestXxx
+// function the Go analyzer flagged with isTableDriven becomes a TEST entity
+// labeled with the first string of the element. The test function CONTAINS its
+// cases. Named element types must be structs declared in the same directory.
+func ExtractGoTestCases(files []graph.CodeFile, entities []graph.Entity) ([]graph.Entity, []graph.Relationship) {
+	var caseEntities []graph.Entity
+	var relationships []graph.Relationship
+
+	// Index test functions and struct types by file and directory
+	testFunctions := make(map[string][]graph.Entity)
+	structTypes := make(map[string]map[string]bool)
+	for _, entity := range entities {
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		if entity.Type == graph.EntityTypeClass && strings.HasSuffix(sourceFile, ".go") {
+			dir := sourceDir(entity)
+			if structTypes[dir] == nil {
+				structTypes[dir] = make(map[string]bool)
+			}
+			structTypes[dir][entity.Label] = true
+			continue
+		}
+		if !isCallableEntity(entity) || !isGoTestFile(entity) {
+			continue
+		}
+		if tableDriven, _ := entity.Properties["isTableDriven"].(bool); !tableDriven {
+			continue
+		}
+		if _, property := splitTestName(entity.Label); property == "isTest" {
+			testFunctions[sourceFile] = append(testFunctions[sourceFile], entity)
+		}
+	}
+
+	for _, file := range files {
+		tests := testFunctions[file.Path]
+		if len(tests) == 0 {
+			continue
+		}
+
+		lines := strings.Split(file.Content, "\n")
+		for _, test := range tests {
+			start, ok := ToInt(test.Properties["lineNumber"])
+			end, hasEnd := ToInt(test.Properties["endLine"])
+			if !ok || !hasEnd || start < 1 || end > len(lines) || start > end {
+				continue
+			}
+
+			body := strings.Join(lines[start-1:end], "\n")
+			for _, testCase := range extractGoTestCases(body, structTypes[sourceDir(test)]) {
+				caseEntity := graph.CreateEntity(testCase.name, graph.EntityTypeTest, graph.Properties{
+					"parentTest": test.Label,
+					"testCase":   testCase.name,
+					"sourceFile": file.Path,
+					"lineNumber": start + testCase.line,
+					"language":   "go",
+				})
+				caseEntities = append(caseEntities, caseEntity)
+				relationships = append(relationships, graph.CreateRelationship(
+					test.ID, caseEntity.ID, graph.RelationshipTypeContains, nil))
+			}
+		}
+	}
+
+	return caseEntities, relationships
+}
+
+// extractGoTestCases returns the named elements of the test case slice literals
+// in the body of a test function, with their line relative to the first line
+// of the body. Elements without a string are skipped.
+func extractGoTestCases(body string, structTypes map[string]bool) []goTestCase {
+	var cases []goTestCase
+
+	for offset := 0; offset < len(body); {
+		match := analyzers.GoTestTableRegex.FindStringSubmatchIndex(body[offset:])
+		if match == nil {
+			break
+		}
+		// The brace ending the match opens the struct type or the literal
+		base := offset
+		open := base + match[1] - 1
+		offset = base + match[1]
+
+		if match[2] >= 0 {
+			// Skip the anonymous struct type to the literal following it
+			typeEnd := goClosingBrace(body, open)
+			if typeEnd < 0 {
+				break
+			}
+			next := typeEnd + 1
+			for next < len(body) && (body[next] == ' ' || body[next] == '\t') {
+				next++
+			}
+			if next >= len(body) || body[next] != '{' {
+				offset = typeEnd + 1
+				continue
+			}
+			open = next
+		} else if !structTypes[body[base+match[4]:base+match[5]]] {
+			continue
+		}
+
+		literalEnd := goClosingBrace(body, open)
+		if literalEnd < 0 {
+			break
+		}
+		cases = append(cases, goTestCaseElements(body, open, literalEnd)...)
+		offset = literalEnd + 1
+	}
+
+	return cases
+}
+
+// goTestCaseElements returns the elements of the composite literal between the
+// braces at open and end that have a string, named by their first string
+func goTestCaseElements(body string, open, end int) []goTestCase {
+	var cases []goTestCase
+
+	depth := 0
+	elementStart := -1
+	named := false
+	for i := open; i <= end; i++ {
+		switch c := body[i]; c {
+		case '{':
+			depth++
+			if depth == 2 {
+				elementStart = i
+				named = false
+			}
+		case '}':
+			if depth == 2 {
+				elementStart = -1
+			}
+			depth--
+		case '"', '`':
+			literalEnd := goStringEnd(body, i)
+			if depth >= 2 && elementStart >= 0 && !named {
+				named = true
+				cases = append(cases, goTestCase{
+					name: goStringValue(body[i : literalEnd+1]),
+					line: strings.Count(body[:elementStart], "\n"),
+				})
+			}
+			i = literalEnd
+		case '\'':
+			i = goStringEnd(body, i)
+		case '/':
+			i = goCommentEnd(body, i)
+		}
+	}
+
+	return cases
+}
+
+// goClosingBrace returns the index of the brace closing the one at open, or -1
+func goClosingBrace(body string, open int) int {
+	depth := 0
+	for i := open; i < len(body); i++ {
+		switch body[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '`', '\'':
+			i = goStringEnd(body, i)
+		case '/':
+			i = goCommentEnd(body, i)
+		}
+	}
+	return -1
+}
+
+// goStringEnd returns the index of the quote closing the string or rune literal
+// starting at start, or the last index of body when it isn't closed
+func goStringEnd(body string, start int) int {
+	quote := body[start]
+	for i := start + 1; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && quote != '`':
+			i++
+		case body[i] == quote:
+			return i
+		}
+	}
+	return len(body) - 1
+}
+
+// goCommentEnd returns the last index of the comment starting at start, or start
+// when no comment starts there
+func goCommentEnd(body string, start int) int {
+	if start+1 >= len(body) {
+		return start
+	}
+	switch body[start+1] {
+	case '/':
+		if end := strings.IndexByte(body[start:], '\n'); end >= 0 {
+			return start + end - 1
+		}
+		return len(body) - 1
+	case '*':
+		if end := strings.Index(body[start+2:], "*/"); end >= 0 {
+			return start + 2 + end + 1
+		}
+		return len(body) - 1
+	}
+	return start
+}
+
+// goStringValue returns the value of a Go string literal, or its raw text when
+// it can't be unquoted
+func goStringValue(literal string) string {
+	if value, err := strconv.Unquote(literal); err == nil {
+		return value
+	}
+	return strings.Trim(literal, "\"`")
+}
+
+// splitTestName returns the suspected tested name and the flag property for a
+// Go test function name, e.g. "TestParse_Empty" -> ("Parse", "isTest")
+func splitTestName(name string) (string, string) {
+	for _, tp := range goTestPrefixes {
+		if !strings.HasPrefix(name, tp.prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(name, tp.prefix)
+		// go test only recognises TestXxx where Xxx does not start with a lowercase letter
+		if r, _ := utf8.DecodeRuneInString(rest); unicode.IsLower(r) {
+			return "", ""
+		}
+
+		rest = strings.TrimPrefix(rest, "_")
+		if idx := strings.Index(rest, "_"); idx != -1 {
+			rest = rest[:idx]
+		}
+		return rest, tp.property
+	}
+	return "", ""
+}
+
+// isCallableEntity reports whether an entity is a function or method
+func isCallableEntity(entity graph.Entity) bool {
+	return entity.Type == graph.EntityTypeFunction || entity.Type == graph.EntityTypeMethod
+}
+
+// isGoTestFile reports whether an entity was declared in a Go test file
+func isGoTestFile(entity graph.Entity) bool {
+	sourceFile, ok := entity.Properties["sourceFile"].(string)
+	return ok && strings.HasSuffix(sourceFile, "_test.go")
+}
+
+// lowerFirst lowercases the first letter of a name
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// upperFirst uppercases the first letter of a name
+func upperFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToUpper(r)) + name[size:]
+}
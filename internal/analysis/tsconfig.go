@@ -0,0 +1,199 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// TypeScriptPathAliases are the module aliases of the compilerOptions.paths of
+// a tsconfig.json, such as "@services/*": ["./src/services/*"]
+type TypeScriptPathAliases struct {
+	// BaseDir is the directory the targets are relative to: baseUrl, or the
+	// directory of the tsconfig.json when it has none
+	BaseDir string
+	// Paths maps each pattern to its targets, tried in order. Patterns and
+	// targets contain at most one *, standing for the same text in both.
+	Paths map[string][]string
+}
+
+// tsConfig holds the fields of a tsconfig.json used to resolve aliases
+type tsConfig struct {
+	CompilerOptions struct {
+		BaseURL string              `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// LoadTypeScriptPathAliases reads the path aliases of a tsconfig.json. Comments
+// and trailing commas, which the TypeScript compiler accepts, are allowed.
+// Aliases inherited through extends are not read.
+func LoadTypeScriptPathAliases(tsconfigPath string) (*TypeScriptPathAliases, error) {
+	data, err := os.ReadFile(tsconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", tsconfigPath, err)
+	}
+
+	var config tsConfig
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", tsconfigPath, err)
+	}
+
+	baseDir := filepath.Dir(tsconfigPath)
+	if config.CompilerOptions.BaseURL != "" {
+		baseDir = filepath.Join(baseDir, filepath.FromSlash(config.CompilerOptions.BaseURL))
+	}
+	return &TypeScriptPathAliases{
+		BaseDir: filepath.Clean(baseDir),
+		Paths:   config.CompilerOptions.Paths,
+	}, nil
+}
+
+// Targets returns the paths a module specifier may refer to, in the order the
+// TypeScript compiler tries them: those of an exact pattern, or else those of
+// the wildcard pattern with the longest prefix matching the specifier
+func (a *TypeScriptPathAliases) Targets(source string) []string {
+	if targets, ok := a.Paths[source]; ok && !strings.Contains(source, "*") {
+		return a.resolveTargets(targets, "")
+	}
+
+	// Sort the patterns so that ties between prefixes are broken stably
+	patterns := make([]string, 0, len(a.Paths))
+	for pattern := range a.Paths {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	best, bestPrefix, wildcard := "", -1, ""
+	for _, pattern := range patterns {
+		prefix, suffix, ok := strings.Cut(pattern, "*")
+		if !ok || len(prefix) <= bestPrefix || len(source) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(source, prefix) || !strings.HasSuffix(source, suffix) {
+			continue
+		}
+		best, bestPrefix = pattern, len(prefix)
+		wildcard = source[len(prefix) : len(source)-len(suffix)]
+	}
+	if bestPrefix < 0 {
+		return nil
+	}
+	return a.resolveTargets(a.Paths[best], wildcard)
+}
+
+// resolveTargets substitutes wildcard for the * of targets and makes them
+// absolute
+func (a *TypeScriptPathAliases) resolveTargets(targets []string, wildcard string) []string {
+	paths := make([]string, 0, len(targets))
+	for _, target := range targets {
+		target = strings.Replace(target, "*", wildcard, 1)
+		paths = append(paths, filepath.Join(a.BaseDir, filepath.FromSlash(target)))
+	}
+	return paths
+}
+
+// ApplyTypeScriptPathAliases rewrites the sources of the TypeScript/JavaScript
+// imports and re-exports that use a path alias to the relative specifier of the
+// file the alias resolves to, so that they are resolved like relative imports.
+// The first target of the alias matching a file entity under rootDir is used;
+// sources matching no file, such as packages caught by a "*" pattern, are kept.
+// The original source is recorded as aliasedSource.
+func ApplyTypeScriptPathAliases(entities []graph.Entity, aliases *TypeScriptPathAliases, rootDir string) {
+	root := filepath.Clean(rootDir)
+	files := typeScriptFileIndex(entities)
+
+	for _, entity := range entities {
+		if entity.Type != graph.EntityTypeImport && entity.Type != graph.EntityTypeExport {
+			continue
+		}
+		if lang, _ := entity.Properties["language"].(string); lang != "typescript" && lang != "javascript" {
+			continue
+		}
+		source, _ := entity.Properties["source"].(string)
+		sourceFile, _ := entity.Properties["sourceFile"].(string)
+		if source == "" || sourceFile == "" || strings.HasPrefix(source, ".") {
+			continue
+		}
+
+		for _, target := range aliases.Targets(source) {
+			if !isWithinDir(target, root) || !typeScriptModuleExists(files, target) {
+				continue
+			}
+			specifier, err := filepath.Rel(filepath.Dir(sourceFile), target)
+			if err != nil {
+				break
+			}
+			specifier = filepath.ToSlash(specifier)
+			if !strings.HasPrefix(specifier, "../") {
+				specifier = "./" + specifier
+			}
+			entity.Properties["aliasedSource"] = source
+			entity.Properties["source"] = specifier
+			break
+		}
+	}
+}
+
+// typeScriptModuleExists reports whether a module path resolves to a file
+// entity with the TypeScript extensions
+func typeScriptModuleExists(files map[string]string, path string) bool {
+	for _, suffix := range typeScriptResolveSuffixes {
+		if _, ok := files[filepath.Clean(path+filepath.FromSlash(suffix))]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stripJSONComments removes the // and /* */ comments and the trailing commas
+// of JSON with comments, leaving strings untouched
+func stripJSONComments(data []byte) []byte {
+	var out []byte
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			// Drop a comma before the closing bracket, ignoring whitespace
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// Names of the checks of a ValidationReport
+const (
+	CheckOrphanRelationships = "orphan-relationships"
+	CheckEmptyLabels         = "empty-labels"
+	CheckIDCollisions        = "id-collisions"
+	CheckMissingFiles        = "missing-files"
+	CheckLabelCollisions     = "label-collisions"
+)
+
+// maxValidationSamples is the number of offending IDs kept per check
+const maxValidationSamples = 5
+
+// ValidationCheck is the result of one consistency check of a graph
+type ValidationCheck struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Count       int      `json:"count"`
+	Samples     []string `json:"samples"`
+	// Informational checks report findings that are not inconsistencies and
+	// are not counted by Issues
+	Informational bool `json:"informational,omitempty"`
+}
+
+// ValidationReport holds the results of the consistency checks of a graph
+type ValidationReport struct {
+	Checks []*ValidationCheck `json:"checks"`
+}
+
+// NewValidationReport creates a report with all checks and no issues
+func NewValidationReport() *ValidationReport {
+	return &ValidationReport{Checks: []*ValidationCheck{
+		{Name: CheckOrphanRelationships, Description: "Relationships whose source or target entity does not exist", Samples: []string{}},
+		{Name: CheckEmptyLabels, Description: "Entities without a label", Samples: []string{}},
+		{Name: CheckIDCollisions, Description: "IDs shared by entities of different types", Samples: []string{}},
+		{Name: CheckMissingFiles, Description: "File entities whose path does not exist on disk", Samples: []string{}},
+		{Name: CheckLabelCollisions, Description: "Labels shared by entities of the same type with different IDs", Samples: []string{}, Informational: true},
+	}}
+}
+
+// AddIssue records an offending entity or relationship ID for the named check
+func (r *ValidationReport) AddIssue(check, id string) {
+	for _, c := range r.Checks {
+		if c.Name != check {
+			continue
+		}
+		c.Count++
+		if len(c.Samples) < maxValidationSamples {
+			c.Samples = append(c.Samples, id)
+		}
+		return
+	}
+}
+
+// Issues returns the total number of issues found by all checks that are not
+// informational
+func (r *ValidationReport) Issues() int {
+	total := 0
+	for _, c := range r.Checks {
+		if c.Informational {
+			continue
+		}
+		total += c.Count
+	}
+	return total
+}
+
+// CheckFilePath records a missing-files issue for a file entity whose path is
+// empty or does not exist on disk
+func (r *ValidationReport) CheckFilePath(id string, path interface{}) {
+	p, ok := path.(string)
+	if !ok || p == "" {
+		r.AddIssue(CheckMissingFiles, id)
+		return
+	}
+	if _, err := os.Stat(p); err != nil {
+		r.AddIssue(CheckMissingFiles, id)
+	}
+}
+
+// labelCollisionKey returns the normalized label and type of an entity, as
+// "TYPE:label" with the label in lower case
+func labelCollisionKey(entity graph.Entity) string {
+	return string(entity.Type) + ":" + strings.ToLower(strings.TrimSpace(entity.Label))
+}
+
+// LabelCollisionReport returns the entities of every normalized label and type
+// shared by entities with different IDs, e.g. functions of the same name in
+// different files, keyed by "TYPE:label". Each ID is listed once.
+func LabelCollisionReport(entities []graph.Entity) map[string][]graph.Entity {
+	byKey := make(map[string][]graph.Entity)
+	seen := make(map[string]bool)
+	for _, entity := range entities {
+		if entity.Label == "" || seen[entity.ID] {
+			continue
+		}
+		seen[entity.ID] = true
+		key := labelCollisionKey(entity)
+		byKey[key] = append(byKey[key], entity)
+	}
+
+	collisions := make(map[string][]graph.Entity)
+	for key, group := range byKey {
+		if len(group) > 1 {
+			collisions[key] = group
+		}
+	}
+	return collisions
+}
+
+// ValidateGraph checks the consistency of a knowledge graph: relationships must
+// connect existing entities, entities must have a label, an ID must not be used
+// by entities of different types and file entities must point to existing files.
+// Labels shared by entities of the same type are reported as informational.
+func ValidateGraph(kg *graph.KnowledgeGraph) *ValidationReport {
+	report := NewValidationReport()
+
+	types := make(map[string]graph.EntityType, len(kg.Entities))
+	collided := make(map[string]bool)
+	for _, entity := range kg.Entities {
+		if entity.Label == "" {
+			report.AddIssue(CheckEmptyLabels, entity.ID)
+		}
+
+		if entityType, seen := types[entity.ID]; seen && entityType != entity.Type && !collided[entity.ID] {
+			collided[entity.ID] = true
+			report.AddIssue(CheckIDCollisions, entity.ID)
+		}
+		types[entity.ID] = entity.Type
+
+		if entity.Type == graph.EntityTypeFile {
+			report.CheckFilePath(entity.ID, entity.Properties["path"])
+		}
+	}
+
+	for _, rel := range kg.Relationships {
+		_, sourceExists := types[rel.Source]
+		_, targetExists := types[rel.Target]
+		if !sourceExists || !targetExists {
+			report.AddIssue(CheckOrphanRelationships, rel.ID)
+		}
+	}
+
+	collisions := LabelCollisionReport(kg.Entities)
+	keys := make([]string, 0, len(collisions))
+	for key := range collisions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		report.AddIssue(CheckLabelCollisions, key)
+	}
+
+	return report
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project configuration file
+const FileName = ".codegraphgen.yaml"
+
+// Config represents the contents of a .codegraphgen.yaml project file
+type Config struct {
+	Include       []string `yaml:"include"`
+	Exclude       []string `yaml:"exclude"`
+	MaxFileSizeMB int      `yaml:"maxFileSizeMB"`
+	// SkipDirectories are directory names or glob patterns not scanned, in
+	// addition to the default build and dependency directories
+	SkipDirectories []string        `yaml:"skipDirectories,omitempty"`
+	Memgraph        *MemgraphConfig `yaml:"memgraph,omitempty"`
+}
+
+// MemgraphConfig holds the Memgraph connection settings of a project
+type MemgraphConfig struct {
+	URI  string `yaml:"uri"`
+	User string `yaml:"user,omitempty"`
+	TLS  bool   `yaml:"tls,omitempty"`
+}
+
+// DefaultExclude lists the build and dependency directories excluded by default
+var DefaultExclude = []string{
+	"node_modules/**",
+	"vendor/**",
+	"dist/**",
+	"build/**",
+	"out/**",
+	"target/**",
+	"bin/**",
+	"obj/**",
+	"__pycache__/**",
+	"coverage/**",
+}
+
+// Default returns a configuration with the default exclusions and file size limit
+func Default() *Config {
+	return &Config{
+		Exclude:       append([]string(nil), DefaultExclude...),
+		MaxFileSizeMB: 1,
+	}
+}
+
+// Load reads a configuration file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := Default()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration to path
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
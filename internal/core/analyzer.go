@@ -33,7 +33,13 @@ func NewAnalyzerRegistry() *AnalyzerRegistry {
 	registry.RegisterAnalyzer(&analyzers.TypeScriptAnalyzer{})
 	registry.RegisterAnalyzer(&analyzers.PythonAnalyzer{})
 	registry.RegisterAnalyzer(&analyzers.JavaAnalyzer{})
+	registry.RegisterAnalyzer(&analyzers.ScalaAnalyzer{})
+	registry.RegisterAnalyzer(&analyzers.RustAnalyzer{})
+	registry.RegisterAnalyzer(&analyzers.RubyAnalyzer{})
 	registry.RegisterAnalyzer(&analyzers.JSONAnalyzer{})
+	registry.RegisterAnalyzer(&analyzers.YAMLAnalyzer{})
+	registry.RegisterAnalyzer(&analyzers.SQLAnalyzer{})
+	registry.RegisterAnalyzer(&analyzers.MarkdownAnalyzer{})
 	registry.RegisterAnalyzer(&analyzers.GenericAnalyzer{})
 
 	return registry
@@ -46,6 +52,21 @@ func (ar *AnalyzerRegistry) RegisterAnalyzer(analyzer LanguageAnalyzer) {
 	}
 }
 
+// UnregisterAnalyzer removes the analyzer registered for a language, so that
+// files of that language fall back to the generic analyzer
+func (ar *AnalyzerRegistry) UnregisterAnalyzer(language string) {
+	delete(ar.analyzers, language)
+}
+
+// ReplaceAnalyzer registers an analyzer in place of the ones currently handling
+// its languages, e.g. to override a built-in analyzer or inject a mock in tests
+func (ar *AnalyzerRegistry) ReplaceAnalyzer(analyzer LanguageAnalyzer) {
+	for _, lang := range analyzer.SupportedLanguages() {
+		ar.UnregisterAnalyzer(lang)
+	}
+	ar.RegisterAnalyzer(analyzer)
+}
+
 // GetAnalyzer returns the analyzer for a specific language
 func (ar *AnalyzerRegistry) GetAnalyzer(language string) LanguageAnalyzer {
 	if analyzer, exists := ar.analyzers[language]; exists {
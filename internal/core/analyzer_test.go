@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// stubAnalyzer records the files it analyzes and returns a fixed entity
+type stubAnalyzer struct {
+	languages []string
+	analyzed  []string
+}
+
+func (sa *stubAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	sa.analyzed = append(sa.analyzed, file.Path)
+	entity := graph.CreateEntity("stub", graph.EntityTypeFunction, graph.Properties{"sourceFile": file.Path})
+	return []graph.Entity{entity}, nil, nil
+}
+
+func (sa *stubAnalyzer) SupportedLanguages() []string { return sa.languages }
+
+func (sa *stubAnalyzer) Name() string { return "Stub Analyzer" }
+
+func TestReplaceAnalyzerRoutesToStub(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	if name := registry.GetAnalyzer("go").Name(); name != "Go Analyzer" {
+		t.Fatalf("GetAnalyzer(go) = %s, want Go Analyzer", name)
+	}
+
+	stub := &stubAnalyzer{languages: []string{"go"}}
+	registry.ReplaceAnalyzer(stub)
+
+	analyzer := registry.GetAnalyzer("go")
+	if analyzer != LanguageAnalyzer(stub) {
+		t.Fatalf("GetAnalyzer(go) = %s, want the stub", analyzer.Name())
+	}
+
+	file := graph.CodeFile{Path: "main.go", Language: "go", Content: "package main"}
+	entities, _, err := analyzer.Analyze(file, graph.CreateEntity("main.go", graph.EntityTypeFile, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != 1 || entities[0].Label != "stub" {
+		t.Errorf("Analyze() = %v, want the stub entity", entities)
+	}
+	if len(stub.analyzed) != 1 || stub.analyzed[0] != "main.go" {
+		t.Errorf("stub analyzed %v, want [main.go]", stub.analyzed)
+	}
+
+	// Other languages keep their analyzers
+	if name := registry.GetAnalyzer("python").Name(); name == stub.Name() {
+		t.Errorf("GetAnalyzer(python) = %s, want the Python analyzer", name)
+	}
+}
+
+func TestUnregisterAnalyzerFallsBackToGeneric(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	registry.UnregisterAnalyzer("go")
+
+	if name := registry.GetAnalyzer("go").Name(); name != "Generic Analyzer" {
+		t.Errorf("GetAnalyzer(go) after UnregisterAnalyzer = %s, want Generic Analyzer", name)
+	}
+	if _, exists := registry.ListAnalyzers()["go"]; exists {
+		t.Error("ListAnalyzers() still lists go")
+	}
+}
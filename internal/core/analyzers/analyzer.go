@@ -20,7 +20,13 @@ func NewAnalyzerRegistry() *AnalyzerRegistry {
 	registry.RegisterAnalyzer(&TypeScriptAnalyzer{})
 	registry.RegisterAnalyzer(&PythonAnalyzer{})
 	registry.RegisterAnalyzer(&JavaAnalyzer{})
+	registry.RegisterAnalyzer(&ScalaAnalyzer{})
+	registry.RegisterAnalyzer(&RustAnalyzer{})
+	registry.RegisterAnalyzer(&RubyAnalyzer{})
 	registry.RegisterAnalyzer(&JSONAnalyzer{})
+	registry.RegisterAnalyzer(&YAMLAnalyzer{})
+	registry.RegisterAnalyzer(&SQLAnalyzer{})
+	registry.RegisterAnalyzer(&MarkdownAnalyzer{})
 	registry.RegisterAnalyzer(&GenericAnalyzer{})
 	return registry
 }
@@ -31,6 +37,17 @@ func (ar *AnalyzerRegistry) RegisterAnalyzer(analyzer LanguageAnalyzer) {
 	}
 }
 
+func (ar *AnalyzerRegistry) UnregisterAnalyzer(language string) {
+	delete(ar.analyzers, language)
+}
+
+func (ar *AnalyzerRegistry) ReplaceAnalyzer(analyzer LanguageAnalyzer) {
+	for _, lang := range analyzer.SupportedLanguages() {
+		ar.UnregisterAnalyzer(lang)
+	}
+	ar.RegisterAnalyzer(analyzer)
+}
+
 func (ar *AnalyzerRegistry) GetAnalyzer(language string) LanguageAnalyzer {
 	if analyzer, exists := ar.analyzers[language]; exists {
 		return analyzer
@@ -0,0 +1,315 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	cargoTableRegex      = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+	cargoKeyRegex        = regexp.MustCompile(`^("[^"]+"|'[^']+'|[A-Za-z0-9_-]+)((?:\.[A-Za-z0-9_-]+)?)\s*=\s*(.*)$`)
+	cargoDependencyTable = regexp.MustCompile(`^(?:target\.('[^']*'|"[^"]*"|[^.]+)\.)?(workspace\.)?(dependencies|dev-dependencies|dev_dependencies|build-dependencies|build_dependencies)(?:\.("[^"]+"|'[^']+'|[A-Za-z0-9_-]+))?$`)
+	cargoInlineKeyRegex  = regexp.MustCompile(`\b(version|path|git|branch|tag|rev|package|optional|workspace|features)\s*=\s*("[^"]*"|'[^']*'|\[[^\]]*\]|true|false)`)
+)
+
+// CargoManifest holds the crate, dependencies and workspace members of a Cargo.toml
+type CargoManifest struct {
+	Package      CargoPackage
+	Dependencies []CargoDependency
+	Members      []CargoMember
+}
+
+// CargoPackage is the [package] table of a Cargo.toml; its Name is empty for
+// a virtual workspace manifest
+type CargoPackage struct {
+	Name       string
+	Version    string
+	Edition    string
+	LineNumber int
+}
+
+// CargoDependency is a crate of a [dependencies], [dev-dependencies] or
+// [build-dependencies] table, possibly specific to a target, or of the
+// [workspace.dependencies] table shared by the members of a workspace
+type CargoDependency struct {
+	Name        string
+	Version     string
+	Type        string
+	Target      string
+	Features    []string
+	Path        string
+	Git         string
+	Package     string
+	IsOptional  bool
+	IsWorkspace bool
+	LineNumber  int
+}
+
+// CargoMember is a member of the [workspace] of a Cargo.toml: the path of a
+// crate relative to the manifest, or a glob pattern such as crates/*
+type CargoMember struct {
+	Path       string
+	LineNumber int
+}
+
+// IsCargoManifest reports whether a file name is a Cargo manifest
+func IsCargoManifest(name string) bool {
+	return name == "Cargo.toml"
+}
+
+// analyzeCargoManifest analyzes a Cargo.toml. The crate of its [package] table
+// is a MODULE entity that the file DEFINES and that DEPENDS_ON a DEPENDENCY
+// entity for each crate it depends on; the dependencies of a virtual workspace
+// manifest are the file's. The members of a [workspace] are MODULE entities
+// flagged with isWorkspaceMember that the crate, or the file, CONTAINS.
+func analyzeCargoManifest(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	manifest := parseCargoManifest(file.Content)
+	dir := filepath.Dir(file.Path)
+
+	ownerID := fileEntity.ID
+	if manifest.Package.Name != "" {
+		crateEntity := graph.CreateEntity(manifest.Package.Name, graph.EntityTypeModule, graph.Properties{
+			"name":       manifest.Package.Name,
+			"version":    manifest.Package.Version,
+			"edition":    manifest.Package.Edition,
+			"path":       dir,
+			"sourceFile": file.Path,
+			"lineNumber": manifest.Package.LineNumber,
+			"language":   "rust",
+			"isCrate":    true,
+		})
+		entities = append(entities, crateEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, crateEntity.ID, graph.RelationshipTypeDefines, nil))
+		ownerID = crateEntity.ID
+	}
+
+	seen := make(map[string]bool)
+	for _, dependency := range manifest.Dependencies {
+		if seen[dependency.Name] {
+			continue
+		}
+		seen[dependency.Name] = true
+
+		properties := graph.Properties{
+			"name":       dependency.Name,
+			"version":    dependency.Version,
+			"sourceFile": file.Path,
+			"lineNumber": dependency.LineNumber,
+			"type":       dependency.Type,
+			"language":   "rust",
+		}
+		if dependency.Target != "" {
+			properties["target"] = dependency.Target
+		}
+		if len(dependency.Features) > 0 {
+			properties["features"] = dependency.Features
+		}
+		if dependency.Path != "" {
+			properties["path"] = filepath.Join(dir, dependency.Path)
+		}
+		if dependency.Git != "" {
+			properties["git"] = dependency.Git
+		}
+		if dependency.Package != "" {
+			properties["package"] = dependency.Package
+		}
+		if dependency.IsOptional {
+			properties["isOptional"] = true
+		}
+		if dependency.IsWorkspace {
+			properties["isWorkspace"] = true
+		}
+
+		depEntity := graph.CreateEntity(dependency.Name, graph.EntityTypeDependency, properties)
+		entities = append(entities, depEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			ownerID, depEntity.ID, graph.RelationshipTypeDependsOn, nil))
+	}
+
+	for _, member := range manifest.Members {
+		memberEntity := graph.CreateEntity(member.Path, graph.EntityTypeModule, graph.Properties{
+			"path":              filepath.Join(dir, member.Path),
+			"sourceFile":        file.Path,
+			"lineNumber":        member.LineNumber,
+			"language":          "rust",
+			"isWorkspaceMember": true,
+			"isPattern":         strings.ContainsAny(member.Path, "*?["),
+		})
+		entities = append(entities, memberEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			ownerID, memberEntity.ID, graph.RelationshipTypeContains, nil))
+	}
+
+	return entities, relationships, nil
+}
+
+// parseCargoManifest reads the tables of a Cargo.toml used by the analyzer.
+// Dependencies are given either as name = "1.0", as an inline table such as
+// name = { version = "1.0", features = ["derive"] }, as dotted keys such as
+// name.workspace = true, or as a [dependencies.name] table of their own.
+func parseCargoManifest(content string) CargoManifest {
+	var manifest CargoManifest
+
+	table := ""
+	// current is the dependency of a [dependencies.name] table being read
+	var current *CargoDependency
+	// arrayKey and array accumulate a multi-line array until its closing bracket
+	arrayKey, array, arrayLine := "", "", 0
+
+	flush := func() {
+		if current != nil {
+			manifest.Dependencies = append(manifest.Dependencies, *current)
+			current = nil
+		}
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(stripTOMLComment(line))
+		if line == "" {
+			continue
+		}
+
+		if arrayKey != "" {
+			array += " " + line
+			if !closesPythonArray(line) {
+				continue
+			}
+			line, arrayKey = arrayKey+" = "+array, ""
+		}
+
+		if match := cargoTableRegex.FindStringSubmatch(line); match != nil {
+			flush()
+			table = strings.TrimSpace(match[1])
+			if match := cargoDependencyTable.FindStringSubmatch(table); match != nil && match[4] != "" {
+				current = &CargoDependency{Name: strings.Trim(match[4], `"'`), LineNumber: i + 1}
+				cargoDependencyKind(current, match)
+			}
+			continue
+		}
+
+		match := cargoKeyRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		key, subkey, value := strings.Trim(match[1], `"'`), strings.TrimPrefix(match[2], "."), strings.TrimSpace(match[3])
+
+		// Arrays may span several lines: read them whole before parsing the key
+		if strings.HasPrefix(value, "[") && !closesPythonArray(value[1:]) {
+			arrayKey, array, arrayLine = match[1]+match[2], value, i+1
+			continue
+		}
+		lineNumber := i + 1
+		if arrayLine != 0 {
+			lineNumber, arrayLine = arrayLine, 0
+		}
+
+		switch {
+		case table == "package" && subkey == "":
+			switch key {
+			case "name":
+				manifest.Package.Name = strings.Trim(value, `"'`)
+				manifest.Package.LineNumber = lineNumber
+			case "version":
+				manifest.Package.Version = strings.Trim(value, `"'`)
+			case "edition":
+				manifest.Package.Edition = strings.Trim(value, `"'`)
+			}
+		case table == "workspace" && key == "members" && subkey == "":
+			for _, member := range pipStringRegex.FindAllStringSubmatch(value, -1) {
+				manifest.Members = append(manifest.Members, CargoMember{Path: member[1] + member[2], LineNumber: lineNumber})
+			}
+		case current != nil && subkey == "":
+			setCargoDependencyOption(current, key, value)
+		default:
+			tableMatch := cargoDependencyTable.FindStringSubmatch(table)
+			if tableMatch == nil || tableMatch[4] != "" {
+				continue
+			}
+			dependency := CargoDependency{Name: key, LineNumber: lineNumber}
+			cargoDependencyKind(&dependency, tableMatch)
+			switch {
+			case subkey != "":
+				// name.workspace = true, name.version = "1.0"
+				setCargoDependencyOption(&dependency, subkey, value)
+			case strings.HasPrefix(value, "{"):
+				for _, option := range cargoInlineKeyRegex.FindAllStringSubmatch(value, -1) {
+					setCargoDependencyOption(&dependency, option[1], option[2])
+				}
+			default:
+				dependency.Version = strings.Trim(value, `"'`)
+			}
+			manifest.Dependencies = append(manifest.Dependencies, dependency)
+		}
+	}
+	flush()
+
+	return manifest
+}
+
+// cargoDependencyKind sets the type, target and workspace flag of a dependency
+// from the match of cargoDependencyTable on the name of its table
+func cargoDependencyKind(dependency *CargoDependency, table []string) {
+	dependency.Target = strings.Trim(table[1], `"'`)
+	dependency.IsWorkspace = table[2] != ""
+	switch strings.ReplaceAll(table[3], "_", "-") {
+	case "dev-dependencies":
+		dependency.Type = "devDependency"
+	case "build-dependencies":
+		dependency.Type = "buildDependency"
+	default:
+		dependency.Type = "dependency"
+	}
+}
+
+// setCargoDependencyOption sets an option of a dependency from its TOML value
+func setCargoDependencyOption(dependency *CargoDependency, key, value string) {
+	unquoted := strings.Trim(value, `"'`)
+	switch key {
+	case "version":
+		dependency.Version = unquoted
+	case "features":
+		dependency.Features = nil
+		for _, feature := range pipStringRegex.FindAllStringSubmatch(value, -1) {
+			dependency.Features = append(dependency.Features, feature[1]+feature[2])
+		}
+	case "path":
+		dependency.Path = unquoted
+	case "git":
+		dependency.Git = unquoted
+	case "package":
+		dependency.Package = unquoted
+	case "optional":
+		dependency.IsOptional = value == "true"
+	case "workspace":
+		// The version and features are inherited from [workspace.dependencies]
+		dependency.IsWorkspace = value == "true"
+	}
+}
+
+// stripTOMLComment removes a trailing # comment from a line of TOML, keeping
+// the # of strings
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
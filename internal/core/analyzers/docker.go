@@ -0,0 +1,214 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	dockerFromRegex     = regexp.MustCompile(`(?i)^FROM\s+((?:--\w+=\S+\s+)*)(\S+)(?:\s+AS\s+(\S+))?`)
+	dockerPlatformRegex = regexp.MustCompile(`--platform=(\S+)`)
+	dockerCopyFromRegex = regexp.MustCompile(`(?i)^(COPY|ADD)\s+(?:--\S+\s+)*?--from=(\S+)`)
+	dockerMountRegex    = regexp.MustCompile(`--mount=\S*\bfrom=([^,\s]+)`)
+)
+
+// DockerInstruction is an instruction of a Dockerfile, with its continuation
+// lines joined
+type DockerInstruction struct {
+	Text       string
+	LineNumber int
+}
+
+// DockerStage is a build stage of a Dockerfile, started by a FROM instruction
+type DockerStage struct {
+	Name       string
+	Index      int
+	BaseImage  string
+	Platform   string
+	LineNumber int
+	// Uses holds the stages or images the stage copies files from or mounts,
+	// in the order of the instructions
+	Uses []DockerStageUse
+}
+
+// DockerStageUse is a COPY --from, ADD --from or RUN --mount from of a stage
+type DockerStageUse struct {
+	From        string
+	Instruction string
+	LineNumber  int
+}
+
+// IsDockerfile reports whether a file name is a Dockerfile: Dockerfile,
+// Containerfile, or a variant such as Dockerfile.dev or api.Dockerfile
+func IsDockerfile(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "dockerfile" || lower == "containerfile" ||
+		strings.HasPrefix(lower, "dockerfile.") || strings.HasSuffix(lower, ".dockerfile")
+}
+
+// analyzeDockerfile analyzes a Dockerfile as the graph of its build stages.
+// Each stage is a MODULE entity with its stageName (the AS alias, if any),
+// stageIndex and baseImage that the file DEFINES. A stage DEPENDS_ON the earlier
+// stage it is built FROM and the stages it copies files from with
+// COPY --from=stage or mounts with RUN --mount=...,from=stage; the images it
+// is built from or copies files from are DEPENDENCY entities of type image.
+func analyzeDockerfile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	stages := extractDockerStages(file.Content)
+
+	// Stages are referred to by alias or by index
+	stageIDs := make(map[string]string)
+	images := make(map[string]string)
+	image := func(reference string, lineNumber int) string {
+		if id, ok := images[reference]; ok {
+			return id
+		}
+		name, version := splitImageReference(reference)
+		imageEntity := graph.CreateEntity(name, graph.EntityTypeDependency, graph.Properties{
+			"version":    version,
+			"sourceFile": file.Path,
+			"lineNumber": lineNumber,
+			"type":       "image",
+		})
+		entities = append(entities, imageEntity)
+		images[reference] = imageEntity.ID
+		return imageEntity.ID
+	}
+
+	for _, stage := range stages {
+		label := stage.Name
+		if label == "" {
+			label = "stage " + strconv.Itoa(stage.Index)
+		}
+		properties := graph.Properties{
+			"stageName":  stage.Name,
+			"stageIndex": stage.Index,
+			"baseImage":  stage.BaseImage,
+			"sourceFile": file.Path,
+			"lineNumber": stage.LineNumber,
+			"language":   "docker",
+			"isFinal":    stage.Index == len(stages)-1,
+		}
+		if stage.Platform != "" {
+			properties["platform"] = stage.Platform
+		}
+		stageEntity := graph.CreateEntity(label, graph.EntityTypeModule, properties)
+		entities = append(entities, stageEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, stageEntity.ID, graph.RelationshipTypeDefines, nil))
+
+		// The base image: an earlier stage, an image, or none for scratch
+		linked := make(map[string]bool)
+		if baseID, ok := stageIDs[strings.ToLower(stage.BaseImage)]; ok {
+			linked[baseID] = true
+			relationships = append(relationships, graph.CreateRelationship(
+				stageEntity.ID, baseID, graph.RelationshipTypeDependsOn, graph.Properties{
+					"instruction": "FROM",
+					"lineNumber":  stage.LineNumber,
+				}))
+		} else if !strings.EqualFold(stage.BaseImage, "scratch") {
+			relationships = append(relationships, graph.CreateRelationship(
+				stageEntity.ID, image(stage.BaseImage, stage.LineNumber), graph.RelationshipTypeDependsOn, graph.Properties{
+					"instruction": "FROM",
+				}))
+		}
+
+		for _, use := range stage.Uses {
+			targetID, ok := stageIDs[strings.ToLower(use.From)]
+			if !ok {
+				targetID = image(use.From, use.LineNumber)
+			}
+			// Copying several times from the same stage is one dependency
+			if linked[targetID] {
+				continue
+			}
+			linked[targetID] = true
+			relationships = append(relationships, graph.CreateRelationship(
+				stageEntity.ID, targetID, graph.RelationshipTypeDependsOn, graph.Properties{
+					"instruction": use.Instruction,
+					"lineNumber":  use.LineNumber,
+				}))
+		}
+
+		// Later stages can refer to this one, case-insensitively, once it is declared
+		stageIDs[strconv.Itoa(stage.Index)] = stageEntity.ID
+		if stage.Name != "" {
+			stageIDs[strings.ToLower(stage.Name)] = stageEntity.ID
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// extractDockerStages returns the build stages of a Dockerfile with the stages
+// and images their COPY, ADD and RUN instructions use
+func extractDockerStages(content string) []DockerStage {
+	var stages []DockerStage
+	for _, instruction := range extractDockerInstructions(content) {
+		if match := dockerFromRegex.FindStringSubmatch(instruction.Text); match != nil {
+			stage := DockerStage{
+				Name:       match[3],
+				Index:      len(stages),
+				BaseImage:  match[2],
+				LineNumber: instruction.LineNumber,
+			}
+			if platform := dockerPlatformRegex.FindStringSubmatch(match[1]); platform != nil {
+				stage.Platform = platform[1]
+			}
+			stages = append(stages, stage)
+			continue
+		}
+		if len(stages) == 0 {
+			continue
+		}
+
+		stage := &stages[len(stages)-1]
+		if match := dockerCopyFromRegex.FindStringSubmatch(instruction.Text); match != nil {
+			stage.Uses = append(stage.Uses, DockerStageUse{
+				From:        match[2],
+				Instruction: strings.ToUpper(match[1]),
+				LineNumber:  instruction.LineNumber,
+			})
+		}
+		if strings.HasPrefix(strings.ToUpper(instruction.Text), "RUN ") {
+			for _, mount := range dockerMountRegex.FindAllStringSubmatch(instruction.Text, -1) {
+				stage.Uses = append(stage.Uses, DockerStageUse{
+					From:        mount[1],
+					Instruction: "RUN",
+					LineNumber:  instruction.LineNumber,
+				})
+			}
+		}
+	}
+	return stages
+}
+
+// extractDockerInstructions splits a Dockerfile into its instructions, joining
+// the lines continued with a trailing backslash and skipping comments
+func extractDockerInstructions(content string) []DockerInstruction {
+	var instructions []DockerInstruction
+	var current *DockerInstruction
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		continued := strings.HasSuffix(trimmed, "\\")
+		trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, "\\"))
+		if current == nil {
+			instructions = append(instructions, DockerInstruction{Text: trimmed, LineNumber: i + 1})
+			current = &instructions[len(instructions)-1]
+		} else {
+			current.Text += " " + trimmed
+		}
+		if !continued {
+			current = nil
+		}
+	}
+	return instructions
+}
@@ -0,0 +1,256 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gemfileLockName is the name of the lockfile Bundler writes next to a Gemfile
+const gemfileLockName = "Gemfile.lock"
+
+var (
+	gemfileGemRegex            = regexp.MustCompile(`^gem\s+['"]([^'"]+)['"]\s*,?\s*(.*)$`)
+	gemfileGroupRegex          = regexp.MustCompile(`^group\s+(.+?)\s+do\b`)
+	gemfileBlockRegex          = regexp.MustCompile(`\bdo\s*(\|[^|]*\|)?$`)
+	gemfileGroupOptionRegex    = regexp.MustCompile(`(?:\bgroups?:|:groups?\s*=>)\s*(\[[^\]]*\]|:\w+)`)
+	gemfileSymbolRegex         = regexp.MustCompile(`:(\w+)`)
+	gemfileStringRegex         = regexp.MustCompile(`^['"]([^'"]*)['"]$`)
+	gemfileLockSpecRegex       = regexp.MustCompile(`^    (\S+) \(([^)]+)\)$`)
+	gemfileLockDependencyRegex = regexp.MustCompile(`^      (\S+)`)
+)
+
+// GemfileGem represents a gem declared in a Gemfile
+type GemfileGem struct {
+	Name         string
+	Requirements []string
+	Groups       []string
+	LineNumber   int
+}
+
+// GemfileLockSpec represents a gem resolved in a Gemfile.lock, with the names of
+// the gems it depends on
+type GemfileLockSpec struct {
+	Name         string
+	Version      string
+	Dependencies []string
+}
+
+// IsGemfile reports whether a file name is a Bundler Gemfile
+func IsGemfile(name string) bool {
+	return name == "Gemfile"
+}
+
+// analyzeGemfile creates a DEPENDENCY entity for every gem of a Gemfile. When a
+// Gemfile.lock is next to it, the gems get their lockedVersion and the gems
+// resolved only as dependencies of other gems are added with isTransitive.
+func analyzeGemfile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	var specs []GemfileLockSpec
+	lockPath := filepath.Join(filepath.Dir(file.Path), gemfileLockName)
+	if lockContent, err := os.ReadFile(lockPath); err == nil {
+		specs = parseGemfileLock(string(lockContent))
+	}
+	lockedVersions := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		lockedVersions[spec.Name] = spec.Version
+	}
+
+	gemIDs := make(map[string]string)
+	for _, gem := range parseGemfile(file.Content) {
+		if _, seen := gemIDs[gem.Name]; seen {
+			continue
+		}
+
+		depType := "dependency"
+		if isGemfileDevGroups(gem.Groups) {
+			depType = "devDependency"
+		}
+		properties := graph.Properties{
+			"name":       gem.Name,
+			"version":    strings.Join(gem.Requirements, ", "),
+			"sourceFile": file.Path,
+			"lineNumber": gem.LineNumber,
+			"type":       depType,
+			"language":   "ruby",
+		}
+		if len(gem.Groups) > 0 {
+			properties["groups"] = gem.Groups
+		}
+		if version, ok := lockedVersions[gem.Name]; ok {
+			properties["lockedVersion"] = version
+		}
+
+		depEntity := graph.CreateEntity(gem.Name, graph.EntityTypeDependency, properties)
+		entities = append(entities, depEntity)
+		gemIDs[gem.Name] = depEntity.ID
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, depEntity.ID, graph.RelationshipTypeDependsOn, nil))
+	}
+
+	for _, spec := range specs {
+		if _, direct := gemIDs[spec.Name]; direct {
+			continue
+		}
+		depEntity := graph.CreateEntity(spec.Name, graph.EntityTypeDependency, graph.Properties{
+			"name":          spec.Name,
+			"lockedVersion": spec.Version,
+			"sourceFile":    lockPath,
+			"type":          "dependency",
+			"isTransitive":  true,
+			"language":      "ruby",
+		})
+		entities = append(entities, depEntity)
+		gemIDs[spec.Name] = depEntity.ID
+	}
+
+	// Link each resolved gem to the gems it requires
+	for _, spec := range specs {
+		for _, dependency := range spec.Dependencies {
+			if targetID, ok := gemIDs[dependency]; ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					gemIDs[spec.Name], targetID, graph.RelationshipTypeDependsOn, nil))
+			}
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// parseGemfile returns the gems declared in a Gemfile with their version
+// requirements and the groups they belong to, from group blocks or group:
+// options
+func parseGemfile(content string) []GemfileGem {
+	var gems []GemfileGem
+
+	// The groups of each open do block; blocks other than group blocks add none
+	var blockGroups [][]string
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "end" {
+			if len(blockGroups) > 0 {
+				blockGroups = blockGroups[:len(blockGroups)-1]
+			}
+			continue
+		}
+
+		if match := gemfileGroupRegex.FindStringSubmatch(line); match != nil {
+			blockGroups = append(blockGroups, gemfileSymbols(match[1]))
+			continue
+		}
+		if gemfileBlockRegex.MatchString(line) {
+			blockGroups = append(blockGroups, nil)
+			continue
+		}
+
+		match := gemfileGemRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		gem := GemfileGem{Name: match[1], LineNumber: i + 1}
+		for _, groups := range blockGroups {
+			gem.Groups = append(gem.Groups, groups...)
+		}
+
+		// Version requirements are the strings before the first option
+		args := strings.Split(match[2], ",")
+		n := 0
+		for ; n < len(args); n++ {
+			str := gemfileStringRegex.FindStringSubmatch(strings.TrimSpace(args[n]))
+			if str == nil {
+				break
+			}
+			gem.Requirements = append(gem.Requirements, str[1])
+		}
+		options := strings.Join(args[n:], ",")
+		if group := gemfileGroupOptionRegex.FindStringSubmatch(options); group != nil {
+			gem.Groups = append(gem.Groups, gemfileSymbols(group[1])...)
+		}
+
+		gems = append(gems, gem)
+	}
+
+	return gems
+}
+
+// gemfileSymbols returns the names of the Ruby symbols in s, e.g. development and
+// test for ":development, :test"
+func gemfileSymbols(s string) []string {
+	var symbols []string
+	for _, match := range gemfileSymbolRegex.FindAllStringSubmatch(s, -1) {
+		symbols = append(symbols, match[1])
+	}
+	return symbols
+}
+
+// isGemfileDevGroups reports whether gems in these groups are only used during
+// development, i.e. all groups are development or test groups
+func isGemfileDevGroups(groups []string) bool {
+	if len(groups) == 0 {
+		return false
+	}
+	for _, group := range groups {
+		if group != "development" && group != "test" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseGemfileLock returns the gems resolved in a Gemfile.lock: the specs of its
+// GEM, GIT and PATH sections with their versions and dependencies. A gem listed
+// for several platforms is returned once.
+func parseGemfileLock(content string) []GemfileLockSpec {
+	var specs []GemfileLockSpec
+	seen := make(map[string]bool)
+
+	inSpecs := false
+	current := -1
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" {
+			continue
+		}
+
+		// Sections start in the first column
+		if !strings.HasPrefix(line, " ") {
+			inSpecs = false
+			current = -1
+			continue
+		}
+		if strings.TrimSpace(line) == "specs:" {
+			inSpecs = true
+			continue
+		}
+		if !inSpecs {
+			continue
+		}
+
+		if match := gemfileLockSpecRegex.FindStringSubmatch(line); match != nil {
+			current = -1
+			if seen[match[1]] {
+				continue
+			}
+			seen[match[1]] = true
+			specs = append(specs, GemfileLockSpec{Name: match[1], Version: match[2]})
+			current = len(specs) - 1
+		} else if match := gemfileLockDependencyRegex.FindStringSubmatch(line); match != nil && current >= 0 {
+			specs[current].Dependencies = append(specs[current].Dependencies, match[1])
+		}
+	}
+
+	return specs
+}
@@ -2,10 +2,31 @@ package analyzers
 
 import "codegraphgen/internal/core/graph"
 
+// GenericAnalyzer is the fallback analyzer. It recognizes Makefiles, Gemfiles,
+// Python dependency files, Cargo manifests and Dockerfiles by name; any other
+// file only produces its file entity.
 type GenericAnalyzer struct{}
 
+// genericLanguages are the languages handled by the fallback analyzer
+var genericLanguages = []string{"unknown", "make", "bundler", "pip", "cargo", "docker"}
+
 func (ga *GenericAnalyzer) Name() string                 { return "Generic Analyzer" }
-func (ga *GenericAnalyzer) SupportedLanguages() []string { return []string{"unknown"} }
+func (ga *GenericAnalyzer) SupportedLanguages() []string { return genericLanguages }
 func (ga *GenericAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	if file.Language == "make" {
+		return analyzeMakefile(file, fileEntity)
+	}
+	if IsGemfile(file.Name) {
+		return analyzeGemfile(file, fileEntity)
+	}
+	if IsPythonDependencyFile(file.Name) {
+		return analyzePythonDependencies(file, fileEntity)
+	}
+	if IsCargoManifest(file.Name) {
+		return analyzeCargoManifest(file, fileEntity)
+	}
+	if IsDockerfile(file.Name) {
+		return analyzeDockerfile(file, fileEntity)
+	}
 	return []graph.Entity{fileEntity}, []graph.Relationship{}, nil
 }
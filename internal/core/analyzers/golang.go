@@ -1,9 +1,13 @@
 package analyzers
 
 import (
+	"go/build/constraint"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"codegraphgen/internal/core/graph"
 )
@@ -30,16 +34,30 @@ type GoField struct {
 	Type       string
 	LineNumber int
 	IsExported bool
+	IsEmbedded bool
+	Tags       map[string]string
 }
 
 // GoFunction represents a Go function
 type GoFunction struct {
 	Name        string
 	LineNumber  int
+	EndLine     int
 	IsExported  bool
 	Receiver    string
 	Parameters  []string
 	ReturnTypes []string
+	Complexity  int
+	// ReturnsError marks functions whose last result is an error; CheckedErrors
+	// counts the err != nil checks of the body and UncheckedErrorCalls the calls
+	// whose error is discarded with _ or not assigned at all
+	ReturnsError        bool
+	CheckedErrors       int
+	UncheckedErrorCalls int
+	// IsInit marks package init functions, which run automatically and can't
+	// be called; InitIndex is their position among the init functions of the file
+	IsInit    bool
+	InitIndex int
 }
 
 // GoInterface represents a Go interface
@@ -67,6 +85,31 @@ type GoConstant struct {
 	Value      string
 }
 
+// GoVariable represents a Go variable declaration
+type GoVariable struct {
+	Name         string
+	LineNumber   int
+	IsExported   bool
+	Type         string
+	IsShortDecl  bool
+	Function     string // Enclosing function, empty for package-level variables
+	FunctionLine int
+}
+
+// GoGenerateDirective represents a //go:generate directive
+type GoGenerateDirective struct {
+	Command    string
+	Tool       string
+	LineNumber int
+}
+
+// GoEmbedPattern represents a pattern of a //go:embed directive
+type GoEmbedPattern struct {
+	Pattern    string
+	Variable   string
+	LineNumber int
+}
+
 // FunctionCall represents a function call relationship
 type FunctionCall struct {
 	Caller     string
@@ -99,6 +142,11 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 
 	content := file.Content
 
+	// Record the build constraints the file is compiled under
+	if expr, err := ParseGoBuildConstraint(content); err == nil && expr != nil {
+		fileEntity.Properties["buildConstraints"] = expr.String()
+	}
+
 	// Extract package declaration
 	packageRegex := regexp.MustCompile(`package\s+(\w+)`)
 	if match := packageRegex.FindStringSubmatch(content); len(match) > 1 {
@@ -122,11 +170,48 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 		})
 		entities = append(entities, importEntity)
 		relationships = append(relationships, graph.CreateRelationship(
-			fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, nil))
+			fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+				"importedAt": imp.LineNumber,
+			}))
+	}
+
+	// Extract go:generate directives, which annotate the file
+	for _, directive := range extractGoGenerateDirectives(content) {
+		annotationEntity := graph.CreateEntity("go:generate", graph.EntityTypeAnnotation, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": directive.LineNumber,
+			"directive":  directive.Command,
+			"tool":       directive.Tool,
+			"language":   "go",
+		})
+		entities = append(entities, annotationEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			annotationEntity.ID, fileEntity.ID, graph.RelationshipTypeAnnotates, nil))
+	}
+
+	// Files embedded with go:embed are dependencies of the file, like imports
+	for _, embed := range extractGoEmbedPatterns(content) {
+		properties := graph.Properties{
+			"source":     embed.Pattern,
+			"isEmbed":    true,
+			"lineNumber": embed.LineNumber,
+			"language":   "go",
+		}
+		if embed.Variable != "" {
+			properties["variable"] = embed.Variable
+		}
+		embedEntity := graph.CreateEntity(embed.Pattern, graph.EntityTypeImport, properties)
+		entities = append(entities, embedEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, embedEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+				"importedAt": embed.LineNumber,
+				"embed":      true,
+			}))
 	}
 
 	// Extract structs (similar to classes)
 	structs := extractGoStructs(content)
+	structIDs := make(map[string]string, len(structs))
 	for _, st := range structs {
 		structEntity := graph.CreateEntity(st.Name, graph.EntityTypeClass, graph.Properties{
 			"sourceFile": file.Path,
@@ -136,36 +221,89 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 			"structType": true,
 		})
 		entities = append(entities, structEntity)
+		if _, exists := structIDs[st.Name]; !exists {
+			structIDs[st.Name] = structEntity.ID
+		}
 		relationships = append(relationships, graph.CreateRelationship(
 			fileEntity.ID, structEntity.ID, graph.RelationshipTypeDefines, nil))
 
 		// Extract struct fields
+		tagEntities := make(map[string]bool)
 		for _, field := range st.Fields {
-			fieldEntity := graph.CreateEntity(field.Name, graph.EntityTypeProperty, graph.Properties{
+			fieldProperties := graph.Properties{
 				"sourceFile": file.Path,
 				"lineNumber": field.LineNumber,
 				"type":       field.Type,
 				"isExported": field.IsExported,
 				"language":   "go",
-			})
+			}
+			if field.IsEmbedded {
+				fieldProperties["isEmbedded"] = true
+			}
+			if len(field.Tags) > 0 {
+				fieldProperties["tags"] = field.Tags
+			}
+			fieldEntity := graph.CreateEntity(field.Name, graph.EntityTypeProperty, fieldProperties)
 			entities = append(entities, fieldEntity)
 			relationships = append(relationships, graph.CreateRelationship(
 				structEntity.ID, fieldEntity.ID, graph.RelationshipTypeContains, nil))
+
+			// Each struct tag key (json, db, validate, ...) configures the field
+			tagKeys := make([]string, 0, len(field.Tags))
+			for key := range field.Tags {
+				tagKeys = append(tagKeys, key)
+			}
+			sort.Strings(tagKeys)
+			for _, key := range tagKeys {
+				tagEntity := graph.CreateEntity(key, graph.EntityTypeConfiguration, graph.Properties{
+					"sourceFile": file.Path,
+					"lineNumber": field.LineNumber,
+					"key":        key,
+					"value":      field.Tags[key],
+					"field":      field.Name,
+					"struct":     st.Name,
+					"kind":       "structTag",
+					"language":   "go",
+				})
+				// Fields declared together ("A, B int `json:...`") share their tag entities
+				if !tagEntities[tagEntity.ID] {
+					tagEntities[tagEntity.ID] = true
+					entities = append(entities, tagEntity)
+				}
+				relationships = append(relationships, graph.CreateRelationship(
+					fieldEntity.ID, tagEntity.ID, graph.RelationshipTypeConfigures, nil))
+			}
 		}
 	}
 
 	// Extract functions
 	functions := extractGoFunctions(content)
+	var tableDriven map[int]bool
+	if strings.HasSuffix(file.Path, "_test.go") {
+		tableDriven = detectTableDrivenTests(content, structs)
+	}
 	for _, fn := range functions {
 		funcEntity := graph.CreateEntity(fn.Name, graph.EntityTypeFunction, graph.Properties{
-			"sourceFile":  file.Path,
-			"lineNumber":  fn.LineNumber,
-			"isExported":  fn.IsExported,
-			"receiver":    fn.Receiver,
-			"parameters":  fn.Parameters,
-			"returnTypes": fn.ReturnTypes,
-			"language":    "go",
+			"sourceFile":           file.Path,
+			"lineNumber":           fn.LineNumber,
+			"endLine":              fn.EndLine,
+			"isExported":           fn.IsExported,
+			"receiver":             fn.Receiver,
+			"parameters":           fn.Parameters,
+			"returnTypes":          fn.ReturnTypes,
+			"language":             "go",
+			"cyclomaticComplexity": fn.Complexity,
+			"returnsError":         fn.ReturnsError,
+			"checkedErrors":        fn.CheckedErrors,
+			"uncheckedErrorCalls":  fn.UncheckedErrorCalls,
 		})
+		if tableDriven[fn.LineNumber] {
+			funcEntity.Properties["isTableDriven"] = true
+		}
+		if fn.IsInit {
+			funcEntity.Properties["isInit"] = true
+			funcEntity.Properties["callIndex"] = fn.InitIndex
+		}
 		entities = append(entities, funcEntity)
 
 		if fn.Receiver != "" {
@@ -173,19 +311,12 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 			// Extract the receiver type name from syntax like "db *MemgraphDatabase" or "m MemgraphDatabase"
 			receiverType := extractReceiverType(fn.Receiver)
 
-			// Look for the receiver struct in entities
-			var foundReceiver bool
-			for _, entity := range entities {
-				if entity.Type == graph.EntityTypeClass && entity.Label == receiverType {
-					relationships = append(relationships, graph.CreateRelationship(
-						entity.ID, funcEntity.ID, graph.RelationshipTypeContains, nil))
-					foundReceiver = true
-					break
-				}
-			}
-
-			// If we didn't find the receiver struct in current entities, still connect to file
-			if !foundReceiver {
+			// Look for the receiver struct among the structs of the file
+			if structID, ok := structIDs[receiverType]; ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					structID, funcEntity.ID, graph.RelationshipTypeContains, nil))
+			} else {
+				// If the receiver struct isn't declared in this file, still connect to file
 				relationships = append(relationships, graph.CreateRelationship(
 					fileEntity.ID, funcEntity.ID, graph.RelationshipTypeDefines, nil))
 			}
@@ -199,15 +330,17 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 	// Extract function calls and create CALLS relationships
 	functionCalls := extractFunctionCalls(content, functions)
 	for _, call := range functionCalls {
-		// Find the calling and called function entities
+		// Find the calling and called function entities. A file can have several
+		// init functions, so the caller is the one containing the call; init
+		// functions can't be called, so they are never the callee.
 		var callerEntity, calleeEntity *graph.Entity
 		for i := range entities {
 			entity := &entities[i]
 			if entity.Type == graph.EntityTypeFunction {
-				if entity.Label == call.Caller {
+				if entity.Label == call.Caller && (callerEntity == nil || goEntityContainsLine(*entity, call.LineNumber)) {
 					callerEntity = entity
 				}
-				if entity.Label == call.Callee {
+				if entity.Label == call.Callee && entity.Properties["isInit"] != true {
 					calleeEntity = entity
 				}
 			}
@@ -217,7 +350,7 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 		if callerEntity != nil && calleeEntity != nil && callerEntity.ID != calleeEntity.ID {
 			relationships = append(relationships, graph.CreateRelationship(
 				callerEntity.ID, calleeEntity.ID, graph.RelationshipTypeCalls, graph.Properties{
-					"lineNumber": call.LineNumber,
+					"calledAt": call.LineNumber,
 				}))
 		}
 	}
@@ -252,6 +385,41 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 			fileEntity.ID, typeEntity.ID, graph.RelationshipTypeDefines, nil))
 	}
 
+	// Extract variables and short declarations
+	variables := extractGoVariables(content)
+	for _, variable := range variables {
+		scope := "package"
+		if variable.Function != "" {
+			scope = "local"
+		}
+
+		varEntity := graph.CreateEntity(variable.Name, graph.EntityTypeVariable, graph.Properties{
+			"sourceFile":  file.Path,
+			"lineNumber":  variable.LineNumber,
+			"isExported":  variable.IsExported,
+			"type":        variable.Type,
+			"isShortDecl": variable.IsShortDecl,
+			"scope":       scope,
+			"language":    "go",
+		})
+		entities = append(entities, varEntity)
+
+		// Local variables belong to their enclosing function, package-level ones to the file
+		ownerID := fileEntity.ID
+		relType := graph.RelationshipTypeDefines
+		if variable.Function != "" {
+			for _, entity := range entities {
+				if entity.Type == graph.EntityTypeFunction && entity.Label == variable.Function &&
+					entity.Properties["lineNumber"] == variable.FunctionLine {
+					ownerID = entity.ID
+					relType = graph.RelationshipTypeContains
+					break
+				}
+			}
+		}
+		relationships = append(relationships, graph.CreateRelationship(ownerID, varEntity.ID, relType, nil))
+	}
+
 	// Extract constants
 	constants := extractGoConstants(content)
 	for _, constant := range constants {
@@ -271,6 +439,163 @@ func analyzeGoFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity
 	return entities, relationships, nil
 }
 
+// ParseGoBuildConstraint returns the build constraint of a Go file from its
+// "//go:build" line, or else from its "// +build" lines, which are combined with
+// &&. Constraints are only read from the comments before the package clause. It
+// returns nil if the file has no build constraint.
+func ParseGoBuildConstraint(content string) (constraint.Expr, error) {
+	var goBuild constraint.Expr
+	var plusBuild []constraint.Expr
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+
+		switch {
+		case constraint.IsGoBuild(line):
+			expr, err := constraint.Parse(line)
+			if err != nil {
+				return nil, err
+			}
+			goBuild = expr
+		case constraint.IsPlusBuild(line):
+			expr, err := constraint.Parse(line)
+			if err != nil {
+				return nil, err
+			}
+			plusBuild = append(plusBuild, expr)
+		}
+	}
+
+	if goBuild != nil {
+		return goBuild, nil
+	}
+	var combined constraint.Expr
+	for _, expr := range plusBuild {
+		if combined == nil {
+			combined = expr
+		} else {
+			combined = &constraint.AndExpr{X: combined, Y: expr}
+		}
+	}
+	return combined, nil
+}
+
+// goKnownOS and goKnownArch are the GOOS and GOARCH values the go command
+// recognizes in file names
+var (
+	goKnownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	goKnownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+		"sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// GoFileNameTags returns the build tags implied by the name of a Go file, like
+// the go command: the GOOS and GOARCH of a name ending in _GOOS, _GOARCH or
+// _GOOS_GOARCH, optionally followed by _test, e.g. linux and amd64 for
+// poll_linux_amd64.go. The part of the name before the first _ never counts.
+func GoFileNameTags(path string) []string {
+	name := strings.TrimSuffix(filepath.Base(path), ".go")
+	name = strings.TrimSuffix(name, "_test")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+
+	parts := strings.Split(name[i+1:], "_")
+	n := len(parts)
+	if n >= 2 && goKnownOS[parts[n-2]] && goKnownArch[parts[n-1]] {
+		return []string{parts[n-2], parts[n-1]}
+	}
+	if goKnownOS[parts[n-1]] || goKnownArch[parts[n-1]] {
+		return []string{parts[n-1]}
+	}
+	return nil
+}
+
+// extractGoGenerateDirectives returns the //go:generate directives of a file.
+// Like go generate, only directives at the start of a line are recognized. The
+// tool is the command's first word, e.g. stringer for "stringer -type=Direction".
+func extractGoGenerateDirectives(content string) []GoGenerateDirective {
+	var directives []GoGenerateDirective
+	for i, line := range strings.Split(content, "\n") {
+		command, ok := strings.CutPrefix(strings.TrimRight(line, " \t\r"), "//go:generate ")
+		if !ok {
+			continue
+		}
+		command = strings.TrimSpace(command)
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			continue
+		}
+		directives = append(directives, GoGenerateDirective{
+			Command:    command,
+			Tool:       fields[0],
+			LineNumber: i + 1,
+		})
+	}
+	return directives
+}
+
+// goEmbedPatternRegex matches the patterns of a //go:embed directive, which are
+// separated by spaces and may be quoted
+var goEmbedPatternRegex = regexp.MustCompile("\"([^\"]*)\"|`([^`]*)`|(\\S+)")
+
+// extractGoEmbedPatterns returns the patterns of the //go:embed directives of a
+// file with the variable declared below each directive
+func extractGoEmbedPatterns(content string) []GoEmbedPattern {
+	var patterns []GoEmbedPattern
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "//go:embed ")
+		if !ok {
+			continue
+		}
+
+		// The directive applies to the next declaration, after any further
+		// directives and comments
+		var variable string
+		for _, next := range lines[i+1:] {
+			next = strings.TrimSpace(next)
+			if next == "" || strings.HasPrefix(next, "//") {
+				continue
+			}
+			fields := strings.Fields(strings.TrimPrefix(next, "var "))
+			if len(fields) > 0 {
+				variable = fields[0]
+			}
+			break
+		}
+
+		for _, match := range goEmbedPatternRegex.FindAllStringSubmatch(rest, -1) {
+			pattern := match[1] + match[2] + match[3]
+			if pattern == "" {
+				continue
+			}
+			patterns = append(patterns, GoEmbedPattern{
+				Pattern:    pattern,
+				Variable:   variable,
+				LineNumber: i + 1,
+			})
+		}
+	}
+	return patterns
+}
+
 func extractGoImports(content string) []GoImport {
 	var imports []GoImport
 	lines := strings.Split(content, "\n")
@@ -346,9 +671,7 @@ func extractGoStructs(content string) []GoStruct {
 			structName := match[1]
 			isExported := len(structName) > 0 && structName[0] >= 'A' && structName[0] <= 'Z'
 
-			// Extract fields (simplified)
-			var fields []GoField
-			// In a real implementation, you'd parse the struct body
+			fields := extractGoStructFields(lines, i)
 
 			structs = append(structs, GoStruct{
 				Name:       structName,
@@ -362,19 +685,114 @@ func extractGoStructs(content string) []GoStruct {
 	return structs
 }
 
+var (
+	goStructFieldRegex    = regexp.MustCompile("^(\\w+(?:\\s*,\\s*\\w+)*)\\s+([^`]+?)\\s*(`[^`]*`)?$")
+	goEmbeddedFieldRegex  = regexp.MustCompile("^(\\*?[\\w.]+(?:\\[[^\\]]*\\])?)\\s*(`[^`]*`)?$")
+	goStructTagEntryRegex = regexp.MustCompile(`([^\s:"]+):"((?:[^"\\]|\\.)*)"`)
+)
+
+// extractGoStructFields returns the fields of the struct declared on line index
+// start, with their struct tags. Fields of nested anonymous structs are not
+// returned; the field holding the nested struct has the type "struct".
+func extractGoStructFields(lines []string, start int) []GoField {
+	end := findBlockEnd(lines, start)
+
+	var fields []GoField
+	depth := 0
+	for i := start + 1; i < end-1; i++ {
+		opens, closes := countBraces(lines[i])
+		line := strings.TrimSpace(stripLineComment(lines[i]))
+		if depth == 0 && line != "" {
+			fields = append(fields, parseGoStructFieldLine(line, i+1)...)
+		}
+		depth += opens - closes
+	}
+
+	return fields
+}
+
+// parseGoStructFieldLine parses a line of a struct body: named fields such as
+// "A, B int `json:\"a\"`" or an embedded type such as "*sync.Mutex"
+func parseGoStructFieldLine(line string, lineNumber int) []GoField {
+	if match := goStructFieldRegex.FindStringSubmatch(line); match != nil {
+		fieldType := strings.TrimSpace(strings.TrimSuffix(match[2], "{"))
+		tags := parseGoStructTag(match[3])
+
+		var fields []GoField
+		for _, name := range strings.Split(match[1], ",") {
+			name = strings.TrimSpace(name)
+			fields = append(fields, GoField{
+				Name:       name,
+				Type:       fieldType,
+				LineNumber: lineNumber,
+				IsExported: unicode.IsUpper([]rune(name)[0]),
+				Tags:       tags,
+			})
+		}
+		return fields
+	}
+
+	if match := goEmbeddedFieldRegex.FindStringSubmatch(line); match != nil {
+		// An embedded field is named after its type without package and type arguments
+		name := strings.TrimPrefix(match[1], "*")
+		if index := strings.Index(name, "["); index >= 0 {
+			name = name[:index]
+		}
+		if index := strings.LastIndex(name, "."); index >= 0 {
+			name = name[index+1:]
+		}
+		if name == "" {
+			return nil
+		}
+		return []GoField{{
+			Name:       name,
+			Type:       match[1],
+			LineNumber: lineNumber,
+			IsExported: unicode.IsUpper([]rune(name)[0]),
+			IsEmbedded: true,
+			Tags:       parseGoStructTag(match[2]),
+		}}
+	}
+
+	return nil
+}
+
+// parseGoStructTag parses a raw struct tag such as `json:"name,omitempty" db:"col"`
+// into its keys and values. It returns nil for an empty tag.
+func parseGoStructTag(tag string) map[string]string {
+	tag = strings.Trim(tag, "`")
+	matches := goStructTagEntryRegex.FindAllStringSubmatch(tag, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string, len(matches))
+	for _, match := range matches {
+		value, err := strconv.Unquote(`"` + match[2] + `"`)
+		if err != nil {
+			value = match[2]
+		}
+		tags[match[1]] = value
+	}
+	return tags
+}
+
 func extractGoFunctions(content string) []GoFunction {
 	var functions []GoFunction
 	lines := strings.Split(content, "\n")
 
-	// Function regex that handles receivers
-	funcRegex := regexp.MustCompile(`func\s*(?:\([^)]*\))?\s*(\w+)\s*\([^)]*\)`)
-	receiverRegex := regexp.MustCompile(`func\s*\(([^)]*)\)\s*(\w+)`)
+	// Function regex that handles receivers. Both are anchored so that func types
+	// in parameter lists are not mistaken for declarations; the parameter list may
+	// continue on the following lines.
+	funcRegex := regexp.MustCompile(`^func\s*(?:\([^)]*\))?\s*(\w+)\s*\(`)
+	receiverRegex := regexp.MustCompile(`^func\s*\(([^)]*)\)\s*(\w+)`)
 
+	initCount := 0
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 
-		if match := funcRegex.FindStringSubmatch(line); len(match) > 1 {
-			funcName := match[1]
+		if match := funcRegex.FindStringSubmatchIndex(line); match != nil {
+			funcName := line[match[2]:match[3]]
 			isExported := len(funcName) > 0 && funcName[0] >= 'A' && funcName[0] <= 'Z'
 
 			var receiver string
@@ -383,18 +801,264 @@ func extractGoFunctions(content string) []GoFunction {
 				funcName = receiverMatch[2]
 			}
 
-			functions = append(functions, GoFunction{
-				Name:       funcName,
-				LineNumber: i + 1,
-				IsExported: isExported,
-				Receiver:   receiver,
-			})
+			parameters, returnTypes := extractGoSignature(lines, i, line[match[3]:])
+			endLine := findBlockEnd(lines, i)
+
+			fn := GoFunction{
+				Name:         funcName,
+				LineNumber:   i + 1,
+				EndLine:      endLine,
+				IsExported:   isExported,
+				Receiver:     receiver,
+				Parameters:   parameters,
+				ReturnTypes:  returnTypes,
+				Complexity:   goCyclomaticComplexity(lines[i:endLine]),
+				ReturnsError: len(returnTypes) > 0 && returnTypes[len(returnTypes)-1] == "error",
+			}
+			// Methods named init are ordinary methods
+			if funcName == "init" && receiver == "" {
+				fn.IsInit = true
+				fn.InitIndex = initCount
+				initCount++
+			}
+			functions = append(functions, fn)
 		}
 	}
 
+	// Count the error checks once the functions of the file returning an error are known
+	declared := make(map[string]bool)
+	for _, fn := range functions {
+		declared[fn.Name] = declared[fn.Name] || fn.ReturnsError
+	}
+	for i := range functions {
+		fn := &functions[i]
+		fn.CheckedErrors, fn.UncheckedErrorCalls = countGoErrorChecks(lines[fn.LineNumber-1:fn.EndLine], declared)
+	}
+
 	return functions
 }
 
+var (
+	goErrorCheckRegex     = regexp.MustCompile(`\b\w*(?:err|Err)\w*\s*!=\s*nil\b`)
+	goDiscardedErrorRegex = regexp.MustCompile(`^((?:[\w.\[\]*]+\s*,\s*)*)_\s*:?=\s*(.*)$`)
+	goCallStatementRegex  = regexp.MustCompile(`^(?:[\w\[\]]+\.)*(\w+)\(.*\)$`)
+	goCallRegex           = regexp.MustCompile(`^(?:\w+\.)*(\w+)\(`)
+	goChainedCallRegex    = regexp.MustCompile(`^\.(\w+)\(`)
+)
+
+// goCommaOkFuncs are functions of the standard library whose second result
+// reports whether a value was found rather than an error
+var goCommaOkFuncs = map[string]bool{
+	"Load": true, "LoadOrStore": true, "LoadAndDelete": true,
+	"Lookup": true, "LookupEnv": true, "LookupId": true, "LookupGroup": true, "LookupGroupId": true,
+	"Cut": true, "CutPrefix": true, "CutSuffix": true,
+}
+
+// goCalledFunc returns the name of the function whose results expr is, when
+// expr is a call such as f(x), pkg.F(x) or a.B().C(). Type assertions,
+// indexing and receives aren't calls and return "".
+func goCalledFunc(expr string) string {
+	match := goCallRegex.FindStringSubmatchIndex(expr)
+	if match == nil {
+		return ""
+	}
+	name := expr[match[2]:match[3]]
+	for {
+		closing := matchingParen(expr, match[1]-1)
+		if closing < 0 {
+			return ""
+		}
+		expr = expr[closing+1:]
+		if expr == "" || expr == ";" {
+			return name
+		}
+		if match = goChainedCallRegex.FindStringSubmatchIndex(expr); match == nil {
+			return ""
+		}
+		name = expr[match[2]:match[3]]
+	}
+}
+
+// countGoErrorChecks counts the err != nil checks of a function body and the
+// calls whose error is not checked: calls whose last result is assigned to _,
+// such as _ = f.Close() or n, _ := strconv.Atoi(s), and calls made as a
+// statement to the functions of the file returning an error. declared maps the
+// functions of the file to whether they return an error; a discarded result of
+// a function of the file that returns none is not counted, nor are the second
+// results of map lookups, receives, type assertions and goCommaOkFuncs.
+func countGoErrorChecks(lines []string, declared map[string]bool) (int, int) {
+	checked, unchecked := 0, 0
+	for _, line := range lines {
+		code := strings.TrimSpace(goStringLiteralRegex.ReplaceAllString(stripLineComment(line), `""`))
+		checked += len(goErrorCheckRegex.FindAllString(code, -1))
+
+		if match := goDiscardedErrorRegex.FindStringSubmatch(code); match != nil {
+			name := goCalledFunc(match[2])
+			if name == "" || (match[1] != "" && goCommaOkFuncs[name]) {
+				continue
+			}
+			if returnsError, ok := declared[name]; !ok || returnsError {
+				unchecked++
+			}
+		} else if match := goCallStatementRegex.FindStringSubmatch(code); match != nil && declared[match[1]] {
+			unchecked++
+		}
+	}
+	return checked, unchecked
+}
+
+// maxGoSignatureLines bounds how far a signature split over several lines is followed
+const maxGoSignatureLines = 20
+
+// extractGoSignature parses the parameter and result lists of a function declared on
+// line start, where rest is the text following the function name. Parameters are
+// returned as "name type" (or just the type when unnamed); results as types only.
+func extractGoSignature(lines []string, start int, rest string) ([]string, []string) {
+	signature := stripLineComment(rest)
+	next := start + 1
+	// Pull in continuation lines until the signature's parentheses are closed
+	more := func() bool {
+		if next >= len(lines) || next-start >= maxGoSignatureLines {
+			return false
+		}
+		signature += " " + strings.TrimSpace(stripLineComment(lines[next]))
+		next++
+		return true
+	}
+
+	var paramsEnd int
+	for {
+		paramsEnd = matchingParen(signature, strings.Index(signature, "("))
+		if paramsEnd >= 0 || !more() {
+			break
+		}
+	}
+	if paramsEnd < 0 {
+		return nil, nil
+	}
+	open := strings.Index(signature, "(")
+	parameters := parseGoParameterList(signature[open+1 : paramsEnd])
+
+	results := strings.TrimSpace(signature[paramsEnd+1:])
+	if strings.HasPrefix(results, "(") {
+		var resultsEnd int
+		for {
+			resultsEnd = matchingParen(results, 0)
+			if resultsEnd >= 0 || !more() {
+				break
+			}
+			results = strings.TrimSpace(signature[paramsEnd+1:])
+		}
+		if resultsEnd < 0 {
+			return parameters, nil
+		}
+		var returnTypes []string
+		for _, result := range parseGoParameterList(results[1:resultsEnd]) {
+			returnTypes = append(returnTypes, goParameterType(result))
+		}
+		return parameters, returnTypes
+	}
+
+	// A single unnamed result runs up to the opening brace of the body,
+	// skipping the braces of interface{} and struct{} types
+	depth := 0
+	for i := 0; i < len(results); i++ {
+		if results[i] == '}' {
+			depth--
+		}
+		if results[i] != '{' {
+			continue
+		}
+		prefix := strings.TrimSpace(results[:i])
+		if depth > 0 || strings.HasSuffix(prefix, "interface") || strings.HasSuffix(prefix, "struct") {
+			depth++
+			continue
+		}
+		results = results[:i]
+		break
+	}
+	results = strings.TrimSpace(results)
+	if results == "" {
+		return parameters, nil
+	}
+	return parameters, []string{results}
+}
+
+// parseGoParameterList splits a Go parameter list into "name type" entries,
+// expanding grouped names such as "a, b int" into "a int" and "b int"
+func parseGoParameterList(list string) []string {
+	var items []string
+	depth, begin := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth--
+			}
+			if list[i] != ',' || depth > 0 {
+				continue
+			}
+		}
+		if item := strings.Join(strings.Fields(list[begin:i]), " "); item != "" {
+			items = append(items, item)
+		}
+		begin = i + 1
+	}
+
+	named := false
+	for _, item := range items {
+		if isNamedGoParameter(item) {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return items
+	}
+
+	// In a named list a bare identifier shares the type of the next named parameter
+	parameters := make([]string, len(items))
+	paramType := ""
+	for i := len(items) - 1; i >= 0; i-- {
+		if isNamedGoParameter(items[i]) {
+			paramType = goParameterType(items[i])
+			parameters[i] = items[i]
+		} else {
+			parameters[i] = items[i] + " " + paramType
+		}
+	}
+	return parameters
+}
+
+// isNamedGoParameter reports whether a parameter entry starts with a name
+func isNamedGoParameter(item string) bool {
+	name, _, found := strings.Cut(item, " ")
+	if !found {
+		return false
+	}
+	switch name {
+	case "chan", "func", "map", "interface", "struct", "<-chan":
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// goParameterType returns the type of a parameter entry
+func goParameterType(item string) string {
+	if isNamedGoParameter(item) {
+		_, paramType, _ := strings.Cut(item, " ")
+		return paramType
+	}
+	return item
+}
+
 func extractGoInterfaces(content string) []GoInterface {
 	var interfaces []GoInterface
 	lines := strings.Split(content, "\n")
@@ -476,6 +1140,207 @@ func extractGoConstants(content string) []GoConstant {
 	return constants
 }
 
+// extractGoVariables extracts var declarations and := short declarations.
+// A brace-depth counter tracks the enclosing function so that local variables
+// can be attributed to it; declarations at depth 0 are package-level.
+func extractGoVariables(content string) []GoVariable {
+	var variables []GoVariable
+	lines := strings.Split(content, "\n")
+
+	funcRegex := regexp.MustCompile(`^func\s*(?:\([^)]*\))?\s*(\w+)\s*\(`)
+	shortDeclRegex := regexp.MustCompile(`(?:^|[\s(;{])((?:\w+\s*,\s*)*\w+)\s*:=\s*(.*)`)
+	varRegex := regexp.MustCompile(`^var\s+(\w+(?:\s*,\s*\w+)*)\s*([^=]*?)\s*(?:=\s*(.+))?$`)
+	varSpecRegex := regexp.MustCompile(`^(\w+(?:\s*,\s*\w+)*)\s*([^=]*?)\s*(?:=\s*(.+))?$`)
+
+	var currentFunction string
+	var currentFunctionLine int
+	depth := 0
+	inVarBlock := false
+
+	for i, rawLine := range lines {
+		line := strings.TrimSpace(stripLineComment(rawLine))
+		lineNumber := i + 1
+
+		if line == "" {
+			continue
+		}
+
+		if depth == 0 {
+			if match := funcRegex.FindStringSubmatch(line); len(match) > 1 {
+				currentFunction = match[1]
+				currentFunctionLine = lineNumber
+			}
+		}
+
+		addVariables := func(names, varType, value string, isShortDecl bool) {
+			nameList := strings.Split(names, ",")
+			if varType == "" && len(nameList) == 1 {
+				varType = inferGoType(value)
+			}
+			for _, name := range nameList {
+				name = strings.TrimSpace(name)
+				if name == "" || name == "_" {
+					continue
+				}
+				variable := GoVariable{
+					Name:        name,
+					LineNumber:  lineNumber,
+					IsExported:  name[0] >= 'A' && name[0] <= 'Z',
+					Type:        strings.TrimSpace(varType),
+					IsShortDecl: isShortDecl,
+				}
+				if depth > 0 {
+					variable.Function = currentFunction
+					variable.FunctionLine = currentFunctionLine
+				}
+				variables = append(variables, variable)
+			}
+		}
+
+		switch {
+		case inVarBlock:
+			if strings.HasPrefix(line, ")") {
+				inVarBlock = false
+			} else if match := varSpecRegex.FindStringSubmatch(line); len(match) > 1 {
+				addVariables(match[1], match[2], match[3], false)
+			}
+		case line == "var (":
+			inVarBlock = true
+		case strings.HasPrefix(line, "var "):
+			if match := varRegex.FindStringSubmatch(line); len(match) > 1 {
+				addVariables(match[1], match[2], match[3], false)
+			}
+		case depth > 0:
+			if match := shortDeclRegex.FindStringSubmatch(line); len(match) > 2 {
+				// Drop the condition of "if x := f(); x > 0 {" style statements
+				value, _, _ := strings.Cut(match[2], ";")
+				addVariables(match[1], "", value, true)
+			}
+		}
+
+		opens, closes := countBraces(line)
+		depth += opens - closes
+		if depth <= 0 {
+			depth = 0
+			currentFunction = ""
+		}
+	}
+
+	return variables
+}
+
+// GoTestTableRegex matches the start of a slice literal of test cases: an
+// anonymous struct type (group 1) or a named element type (group 2)
+var GoTestTableRegex = regexp.MustCompile(`\[\]\s*(?:(struct)\s*\{|\*?(\w+)\s*\{)`)
+
+// detectTableDrivenTests finds Test* functions whose body contains a slice literal
+// of struct type, either anonymous ([]struct{...}{...}) or a struct declared in the
+// same file ([]testCase{...}). The result is keyed by the function's line number.
+func detectTableDrivenTests(content string, structs []GoStruct) map[int]bool {
+	tableDriven := make(map[int]bool)
+	lines := strings.Split(content, "\n")
+
+	testFuncRegex := regexp.MustCompile(`^func\s+Test\w*\s*\(`)
+
+	structNames := make(map[string]bool)
+	for _, st := range structs {
+		structNames[st.Name] = true
+	}
+
+	testLine := 0
+	depth := 0
+
+	for i, rawLine := range lines {
+		line := strings.TrimSpace(stripLineComment(rawLine))
+
+		if depth == 0 {
+			testLine = 0
+			if testFuncRegex.MatchString(line) {
+				testLine = i + 1
+			}
+		}
+
+		if testLine != 0 {
+			for _, match := range GoTestTableRegex.FindAllStringSubmatch(line, -1) {
+				if match[1] != "" || structNames[match[2]] {
+					tableDriven[testLine] = true
+				}
+			}
+		}
+
+		opens, closes := countBraces(line)
+		depth += opens - closes
+		if depth < 0 {
+			depth = 0
+		}
+	}
+
+	return tableDriven
+}
+
+var (
+	goIntLiteralRegex   = regexp.MustCompile(`^-?\d+$`)
+	goFloatLiteralRegex = regexp.MustCompile(`^-?\d*\.\d+$`)
+	goCompositeRegex    = regexp.MustCompile(`^(&?)((?:\[\d*\]|map\[[^\]]+\])*\*?[\w.]+)\{`)
+	goMakeRegex         = regexp.MustCompile(`^make\(([^,)]+)`)
+	goNewRegex          = regexp.MustCompile(`^new\(([^)]+)\)`)
+)
+
+// inferGoType infers a variable's type from the right-hand side of its declaration
+// when it is visible without type checking, e.g. errors.New(...) -> error
+func inferGoType(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(value, "errors.New(") || strings.HasPrefix(value, "fmt.Errorf("):
+		return "error"
+	case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "`") || strings.HasPrefix(value, "fmt.Sprintf("):
+		return "string"
+	case value == "true" || value == "false":
+		return "bool"
+	case goIntLiteralRegex.MatchString(value):
+		return "int"
+	case goFloatLiteralRegex.MatchString(value):
+		return "float64"
+	}
+
+	if match := goCompositeRegex.FindStringSubmatch(value); len(match) > 2 {
+		if match[1] == "&" {
+			return "*" + match[2]
+		}
+		return match[2]
+	}
+	if match := goMakeRegex.FindStringSubmatch(value); len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+	if match := goNewRegex.FindStringSubmatch(value); len(match) > 1 {
+		return "*" + strings.TrimSpace(match[1])
+	}
+
+	return ""
+}
+
+// goDecisionRegex matches the branch points counted by goCyclomaticComplexity
+var goDecisionRegex = regexp.MustCompile(`\b(?:if|for|case)\b|&&|\|\|`)
+
+// goStringLiteralRegex matches string and rune literals on a single line
+var goStringLiteralRegex = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`[^`]*`")
+
+// goCyclomaticComplexity computes the cyclomatic complexity of a function body
+// the way gocyclo does: 1 plus one for every if, for, non-default case, && and ||.
+// Comments and string literals are ignored.
+func goCyclomaticComplexity(lines []string) int {
+	complexity := 1
+	for _, line := range lines {
+		code := goStringLiteralRegex.ReplaceAllString(stripLineComment(line), `""`)
+		complexity += len(goDecisionRegex.FindAllString(code, -1))
+	}
+	return complexity
+}
+
 // extractFunctionCalls extracts function calls from Go code
 func extractFunctionCalls(content string, functions []GoFunction) []FunctionCall {
 	var calls []FunctionCall
@@ -487,13 +1352,19 @@ func extractFunctionCalls(content string, functions []GoFunction) []FunctionCall
 		functionNames[fn.Name] = true
 	}
 
-	// Track which function we're currently inside
-	var currentFunction string
+	// Map each line to the function whose body contains it
+	lineFunctions := make(map[int]string)
+	for _, fn := range functions {
+		for line := fn.LineNumber; line <= fn.EndLine; line++ {
+			lineFunctions[line] = fn.Name
+		}
+	}
 
 	// Function call regex patterns
 	directCallRegex := regexp.MustCompile(`(\w+)\s*\(`)          // functionName(
 	methodCallRegex := regexp.MustCompile(`\.(\w+)\s*\(`)        // .methodName(
 	receiverCallRegex := regexp.MustCompile(`(\w+)\.(\w+)\s*\(`) // receiver.method(
+	signatureRegex := regexp.MustCompile(`^func\s*(?:\([^)]*\))?\s*\w+\s*\(`)
 
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
@@ -504,14 +1375,18 @@ func extractFunctionCalls(content string, functions []GoFunction) []FunctionCall
 			continue
 		}
 
-		// Check if we're entering a new function
-		if match := regexp.MustCompile(`func\s*(?:\([^)]*\))?\s*(\w+)\s*\(`).FindStringSubmatch(line); len(match) > 1 {
-			currentFunction = match[1]
+		currentFunction, ok := lineFunctions[lineNumber]
+		if !ok {
 			continue
 		}
 
-		if currentFunction == "" {
-			continue
+		// Only the body part of a signature line can contain calls
+		if signatureRegex.MatchString(line) {
+			_, body, found := strings.Cut(line, "{")
+			if !found {
+				continue
+			}
+			line = body
 		}
 
 		// Find direct function calls (functionName())
@@ -569,6 +1444,13 @@ func extractFunctionCalls(content string, functions []GoFunction) []FunctionCall
 	return calls
 }
 
+// goEntityContainsLine reports whether line is within the lines of a function entity
+func goEntityContainsLine(entity graph.Entity, line int) bool {
+	start, _ := entity.Properties["lineNumber"].(int)
+	end, _ := entity.Properties["endLine"].(int)
+	return start <= line && line <= end
+}
+
 // extractReceiverType extracts the type name from a Go receiver string
 // e.g., "db *MemgraphDatabase" -> "MemgraphDatabase"
 // e.g., "m MemgraphDatabase" -> "MemgraphDatabase"
@@ -2,8 +2,10 @@ package analyzers
 
 import (
 	"codegraphgen/internal/core/graph"
+	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -54,12 +56,17 @@ func analyzeJavaFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Enti
 			})
 			entities = append(entities, importEntity)
 			relationships = append(relationships, graph.CreateRelationship(
-				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, nil))
+				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+					"importedAt": i + 1,
+				}))
 		}
 	}
 
+	// Type declarations by line, for matching annotations to what they annotate
+	declarations := make(map[int]graph.Entity)
+
 	// Extract classes
-	classRegex := regexp.MustCompile(`(?:public\s+|private\s+|protected\s+)?(?:abstract\s+)?(?:final\s+)?class\s+(\w+)(?:\s+extends\s+(\w+))?(?:\s+implements\s+(.+?))?`)
+	classRegex := regexp.MustCompile(`(?:public\s+|private\s+|protected\s+)?(?:abstract\s+)?(?:final\s+)?class\s+(\w+)(?:\s+extends\s+(\w+))?(?:\s+implements\s+([\w.]+(?:\s*,\s*[\w.]+)*))?`)
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if match := classRegex.FindStringSubmatch(line); len(match) > 1 {
@@ -92,11 +99,94 @@ func analyzeJavaFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Enti
 			entities = append(entities, classEntity)
 			relationships = append(relationships, graph.CreateRelationship(
 				fileEntity.ID, classEntity.ID, graph.RelationshipTypeDefines, nil))
+			declarations[i] = classEntity
+		}
+	}
+
+	// Extract enums with their constants
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		match := javaEnumRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		enumName := match[1]
+		enumEntity := graph.CreateEntity(enumName, graph.EntityTypeEnum, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": i + 1,
+			"language":   "java",
+			"isPublic":   strings.Contains(line, "public"),
+		})
+		entities = append(entities, enumEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, enumEntity.ID, graph.RelationshipTypeDefines, nil))
+		declarations[i] = enumEntity
+
+		for _, constant := range parseJavaEnumConstants(lines, i) {
+			constantEntity := graph.CreateEntity(constant.Name, graph.EntityTypeConstant, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": constant.LineNumber,
+				"language":   "java",
+				"enum":       enumName,
+			})
+			entities = append(entities, constantEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				enumEntity.ID, constantEntity.ID, graph.RelationshipTypeContains, nil))
+		}
+	}
+
+	// Extract annotation types (@interface)
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if match := javaAnnotationTypeRegex.FindStringSubmatch(line); match != nil {
+			annotationEntity := graph.CreateEntity(match[1], graph.EntityTypeAnnotation, graph.Properties{
+				"sourceFile":       file.Path,
+				"lineNumber":       i + 1,
+				"language":         "java",
+				"isPublic":         strings.Contains(line, "public"),
+				"isAnnotationType": true,
+			})
+			entities = append(entities, annotationEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, annotationEntity.ID, graph.RelationshipTypeDefines, nil))
+			declarations[i] = annotationEntity
+		}
+	}
+
+	// Match annotations such as Spring's @RestController or @Service to the type
+	// declarations they annotate
+	declarationLines := make([]int, 0, len(declarations))
+	for i := range declarations {
+		declarationLines = append(declarationLines, i)
+	}
+	sort.Ints(declarationLines)
+	for _, i := range declarationLines {
+		declaration := declarations[i]
+		for _, annotation := range javaTypeAnnotations(lines, i) {
+			properties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": annotation.LineNumber,
+				"language":   "java",
+				"annotates":  declaration.Label,
+			}
+			if annotation.Arguments != "" {
+				properties["arguments"] = annotation.Arguments
+			}
+			if javaSpringAnnotations[annotation.Name] {
+				properties["framework"] = "spring"
+			}
+			annotationEntity := graph.CreateEntity(annotation.Name, graph.EntityTypeAnnotation, properties)
+			entities = append(entities, annotationEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				annotationEntity.ID, declaration.ID, graph.RelationshipTypeAnnotates, nil))
 		}
 	}
 
 	// Extract methods (simplified)
-	methodRegex := regexp.MustCompile(`(?:public|private|protected)\s+(?:static\s+)?(?:final\s+)?(\w+)\s+(\w+)\s*\(`)
+	var methods []javaMethodSpan
+	// The return type may be generic or an array, e.g. List<String> or int[]
+	methodRegex := regexp.MustCompile(`(?:public|private|protected)\s+(?:static\s+)?(?:final\s+)?([\w.]+(?:<[^()]*>)?(?:\[\])*)\s+(\w+)\s*\(`)
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if match := methodRegex.FindStringSubmatch(line); len(match) > 2 {
@@ -112,9 +202,510 @@ func analyzeJavaFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Enti
 				"isStatic":   strings.Contains(line, "static"),
 			})
 			entities = append(entities, methodEntity)
+			methods = append(methods, javaMethodSpan{Index: len(entities) - 1, Start: i, End: findBlockEnd(lines, i)})
 			// Note: In a full implementation, you'd associate methods with their classes
+
+			// Document the method with the @param, @return and @throws tags of its Javadoc
+			if doc, ok := extractJavadoc(lines, i); ok {
+				docEntities, docRelationships := javadocEntities(file, methodEntity, doc, lines, i, declarations)
+				entities = append(entities, docEntities...)
+				relationships = append(relationships, docRelationships...)
+			}
 		}
 	}
 
+	// Extract lambdas and method references
+	lambdaEntities, lambdaRelationships := extractJavaLambdas(file, fileEntity, lines, entities, methods)
+	entities = append(entities, lambdaEntities...)
+	relationships = append(relationships, lambdaRelationships...)
+
 	return entities, relationships, nil
 }
+
+// Javadoc holds the block tags of a Javadoc comment
+type Javadoc struct {
+	Params  []JavadocTag
+	Return  string
+	Throws  []JavadocTag
+	Summary string
+}
+
+// JavadocTag is a @param or @throws tag: the parameter or exception type it
+// documents and its description
+type JavadocTag struct {
+	Name        string
+	Description string
+	LineNumber  int
+}
+
+// javadocTagRegex matches a Javadoc block tag and its first word
+var javadocTagRegex = regexp.MustCompile(`^@(\w+)\s*(\S*)\s*(.*)$`)
+
+// extractJavadoc returns the /** ... */ comment immediately preceding the
+// declaration at line index decl, skipping the annotations in between
+func extractJavadoc(lines []string, decl int) (Javadoc, bool) {
+	end := decl - 1
+	for end >= 0 {
+		line := strings.TrimSpace(lines[end])
+		if line == "" || strings.HasPrefix(line, "@") && !strings.HasSuffix(line, "*/") {
+			end--
+			continue
+		}
+		break
+	}
+	if end < 0 || !strings.HasSuffix(strings.TrimSpace(lines[end]), "*/") {
+		return Javadoc{}, false
+	}
+
+	start := end
+	for start >= 0 && !strings.Contains(lines[start], "/**") {
+		if start < end && strings.Contains(lines[start], "*/") {
+			return Javadoc{}, false
+		}
+		start--
+	}
+	if start < 0 {
+		return Javadoc{}, false
+	}
+
+	var doc Javadoc
+	var summary []string
+	// appendText continues the description of the last tag, or the summary
+	var appendText func(string)
+	appendText = func(text string) { summary = append(summary, text) }
+
+	for i := start; i <= end; i++ {
+		line := strings.TrimSpace(lines[i])
+		if i == start {
+			line = line[strings.Index(line, "/**")+3:]
+		}
+		line = strings.TrimSpace(strings.TrimSuffix(line, "*/"))
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		if line == "" {
+			continue
+		}
+
+		match := javadocTagRegex.FindStringSubmatch(line)
+		if match == nil {
+			appendText(line)
+			continue
+		}
+		tag := JavadocTag{Name: match[2], Description: match[3], LineNumber: i + 1}
+		switch match[1] {
+		case "param":
+			doc.Params = append(doc.Params, tag)
+			tags := &doc.Params[len(doc.Params)-1]
+			appendText = func(text string) { tags.Description = strings.TrimSpace(tags.Description + " " + text) }
+		case "throws", "exception":
+			doc.Throws = append(doc.Throws, tag)
+			tags := &doc.Throws[len(doc.Throws)-1]
+			appendText = func(text string) { tags.Description = strings.TrimSpace(tags.Description + " " + text) }
+		case "return":
+			doc.Return = strings.TrimSpace(match[2] + " " + match[3])
+			appendText = func(text string) { doc.Return = strings.TrimSpace(doc.Return + " " + text) }
+		default:
+			appendText = func(string) {}
+		}
+	}
+	doc.Summary = strings.Join(summary, " ")
+	return doc, true
+}
+
+// javadocEntities documents the method declared at line index decl with its
+// Javadoc: each @param becomes a PARAMETER entity the method ACCEPTS, typed
+// from the method signature, @return is stored as returnDoc, and each @throws
+// becomes a THROWS edge to the exception's class, declared in the file or
+// created for the exception type
+func javadocEntities(file graph.CodeFile, method graph.Entity, doc Javadoc, lines []string, decl int, declarations map[int]graph.Entity) ([]graph.Entity, []graph.Relationship) {
+	var entities []graph.Entity
+	var relationships []graph.Relationship
+
+	if doc.Summary != "" {
+		method.Properties["javadoc"] = doc.Summary
+	}
+	if doc.Return != "" {
+		method.Properties["returnDoc"] = doc.Return
+	}
+
+	types := javaParameterTypes(lines, decl)
+	for _, param := range doc.Params {
+		name := param.Name
+		properties := graph.Properties{
+			"sourceFile":  file.Path,
+			"lineNumber":  param.LineNumber,
+			"language":    "java",
+			"owner":       method.Label,
+			"description": param.Description,
+		}
+		// Type parameters are documented as @param <T>
+		if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+			name = strings.Trim(name, "<>")
+			properties["isTypeParameter"] = true
+		} else if paramType, ok := types[name]; ok {
+			properties["type"] = paramType
+		}
+		if name == "" {
+			continue
+		}
+		paramEntity := graph.CreateEntity(name, graph.EntityTypeParameter, properties)
+		entities = append(entities, paramEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			method.ID, paramEntity.ID, graph.RelationshipTypeAccepts, nil))
+	}
+
+	exceptionIDs := make(map[string]string)
+	for _, throws := range doc.Throws {
+		if throws.Name == "" {
+			continue
+		}
+		simpleName := throws.Name[strings.LastIndex(throws.Name, ".")+1:]
+		targetID := exceptionIDs[throws.Name]
+		for _, declaration := range declarations {
+			if targetID == "" && declaration.Type == graph.EntityTypeClass && declaration.Label == simpleName {
+				targetID = declaration.ID
+			}
+		}
+		if targetID == "" {
+			exceptionEntity := graph.CreateEntity(simpleName, graph.EntityTypeClass, graph.Properties{
+				"sourceFile":    file.Path,
+				"language":      "java",
+				"exceptionType": throws.Name,
+				"isException":   true,
+			})
+			entities = append(entities, exceptionEntity)
+			targetID = exceptionEntity.ID
+		}
+		exceptionIDs[throws.Name] = targetID
+		properties := graph.Properties{"lineNumber": throws.LineNumber}
+		if throws.Description != "" {
+			properties["description"] = throws.Description
+		}
+		relationships = append(relationships, graph.CreateRelationship(
+			method.ID, targetID, graph.RelationshipTypeThrows, properties))
+	}
+
+	return entities, relationships
+}
+
+// javaParameterTypes returns the types of the parameters of the method
+// declared at line index decl by name, e.g. {"names": "List<String>"}
+func javaParameterTypes(lines []string, decl int) map[string]string {
+	signature := strings.Join(lines[decl:min(decl+10, len(lines))], " ")
+	open := strings.Index(signature, "(")
+	if open == -1 {
+		return nil
+	}
+
+	types := make(map[string]string)
+	depth := 0
+	item := ""
+	addItem := func() {
+		fields := strings.Fields(item)
+		// Drop modifiers and annotations, keeping the type and the name
+		var kept []string
+		for _, field := range fields {
+			if field != "final" && !strings.HasPrefix(field, "@") {
+				kept = append(kept, field)
+			}
+		}
+		if len(kept) >= 2 {
+			name := kept[len(kept)-1]
+			types[name] = strings.Join(kept[:len(kept)-1], " ")
+		}
+		item = ""
+	}
+	for _, c := range signature[open+1:] {
+		switch c {
+		case '<', '(':
+			depth++
+		case '>':
+			depth--
+		case ')':
+			if depth == 0 {
+				addItem()
+				return types
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				addItem()
+				continue
+			}
+		}
+		item += string(c)
+	}
+	return types
+}
+
+// javaMethodSpan locates the entity of a method declared on line Start (0-based)
+// whose body ends on line End (1-based)
+type javaMethodSpan struct {
+	Index int
+	Start int
+	End   int
+}
+
+var (
+	javaLambdaRegex          = regexp.MustCompile(`(\([^()]*\)|\b[A-Za-z_$][\w$]*)\s*->`)
+	javaLambdaCallRegex      = regexp.MustCompile(`\.(\w+)\s*\(\s*$`)
+	javaMethodReferenceRegex = regexp.MustCompile(`\b([A-Z][\w$]*|this|super)::([A-Za-z_$][\w$]*)`)
+)
+
+// extractJavaLambdas creates a FUNCTION entity with isLambda for every lambda
+// expression, named lambda$<method>$<n> like the methods the compiler generates,
+// with the stream operation it is passed to (filter, map, ...) when there is one.
+// The enclosing method CONTAINS its lambdas. The method references of a method
+// (User::getName, this::validate) are recorded as its methodReferences, with
+// the line of their first occurrence at the same index of methodReferenceLines,
+// which analysis.LinkJavaMethodReferences links to the referenced methods.
+func extractJavaLambdas(file graph.CodeFile, fileEntity graph.Entity, lines []string, entities []graph.Entity, methods []javaMethodSpan) ([]graph.Entity, []graph.Relationship) {
+	var lambdas []graph.Entity
+	var relationships []graph.Relationship
+	counts := make(map[int]int)
+
+	for i, rawLine := range lines {
+		line := blankJavaStrings(stripLineComment(rawLine))
+
+		// The innermost method whose body holds the line
+		enclosing := -1
+		for m, method := range methods {
+			if method.Start <= i && i < method.End {
+				enclosing = m
+			}
+		}
+
+		matches := javaLambdaRegex.FindAllStringSubmatchIndex(line, -1)
+		// The arrow of a switch rule (case A, B -> ...) isn't a lambda
+		if trimmed := strings.TrimSpace(line); len(matches) > 0 &&
+			(strings.HasPrefix(trimmed, "case ") || strings.HasPrefix(trimmed, "default")) {
+			matches = matches[1:]
+		}
+		for _, match := range matches {
+			owner := fileEntity
+			ownerName := "static"
+			if enclosing >= 0 {
+				owner = entities[methods[enclosing].Index]
+				ownerName = owner.Label
+			}
+
+			properties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "java",
+				"isLambda":   true,
+				"parameters": parseJavaLambdaParameters(line[match[2]:match[3]]),
+			}
+			if enclosing >= 0 {
+				properties["enclosingMethod"] = ownerName
+			}
+			if call := javaLambdaCallRegex.FindStringSubmatch(line[:match[0]]); call != nil {
+				properties["streamOperation"] = call[1]
+			}
+
+			name := fmt.Sprintf("lambda$%s$%d", ownerName, counts[enclosing])
+			counts[enclosing]++
+			lambdaEntity := graph.CreateEntity(name, graph.EntityTypeFunction, properties)
+			lambdas = append(lambdas, lambdaEntity)
+
+			relType := graph.RelationshipTypeContains
+			if enclosing < 0 {
+				relType = graph.RelationshipTypeDefines
+			}
+			relationships = append(relationships, graph.CreateRelationship(
+				owner.ID, lambdaEntity.ID, relType, nil))
+		}
+
+		if enclosing < 0 {
+			continue
+		}
+		for _, match := range javaMethodReferenceRegex.FindAllStringSubmatch(line, -1) {
+			method := entities[methods[enclosing].Index]
+			reference := match[1] + "::" + match[2]
+			references, _ := method.Properties["methodReferences"].([]string)
+			if !containsString(references, reference) {
+				lines, _ := method.Properties["methodReferenceLines"].([]int)
+				method.Properties["methodReferences"] = append(references, reference)
+				method.Properties["methodReferenceLines"] = append(lines, i+1)
+			}
+		}
+	}
+
+	return lambdas, relationships
+}
+
+// parseJavaLambdaParameters returns the parameter names of a lambda, e.g.
+// "(String a, b)" -> [a b] and "x" -> [x]
+func parseJavaLambdaParameters(list string) []string {
+	parameters := []string{}
+	for _, parameter := range strings.Split(strings.Trim(list, "()"), ",") {
+		if fields := strings.Fields(parameter); len(fields) > 0 {
+			parameters = append(parameters, fields[len(fields)-1])
+		}
+	}
+	return parameters
+}
+
+// blankJavaStrings replaces the contents of string and character literals with
+// spaces, so arrows and :: inside them aren't mistaken for code
+func blankJavaStrings(line string) string {
+	blanked := []byte(line)
+	var quote byte
+	for i := 0; i < len(blanked); i++ {
+		c := blanked[i]
+		switch {
+		case quote == 0:
+			if c == '"' || c == '\'' {
+				quote = c
+			}
+		case c == '\\':
+			blanked[i] = ' '
+			if i+1 < len(blanked) {
+				i++
+				blanked[i] = ' '
+			}
+		case c == quote:
+			quote = 0
+		default:
+			blanked[i] = ' '
+		}
+	}
+	return string(blanked)
+}
+
+var (
+	javaEnumRegex           = regexp.MustCompile(`^(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:public|private|protected|static|final)\s+)*enum\s+(\w+)`)
+	javaAnnotationTypeRegex = regexp.MustCompile(`^(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:public|private|protected|static|abstract)\s+)*@interface\s+(\w+)`)
+	javaAnnotationRegex     = regexp.MustCompile(`@([\w.]+)(?:\s*\(([^)]*)\))?`)
+)
+
+// javaSpringAnnotations are the Spring annotations that declare the role of a class
+var javaSpringAnnotations = map[string]bool{
+	"Component":             true,
+	"Service":               true,
+	"Repository":            true,
+	"Controller":            true,
+	"RestController":        true,
+	"Configuration":         true,
+	"ControllerAdvice":      true,
+	"RestControllerAdvice":  true,
+	"SpringBootApplication": true,
+	"RequestMapping":        true,
+}
+
+// javaEnumConstant is a constant declared in the body of an enum
+type javaEnumConstant struct {
+	Name       string
+	LineNumber int
+}
+
+// javaAnnotation is an annotation applied to a declaration
+type javaAnnotation struct {
+	Name       string
+	Arguments  string
+	LineNumber int
+}
+
+// parseJavaEnumConstants returns the constants of the enum declared on line start.
+// The constant list ends at the first semicolon or at the end of the enum body;
+// arguments and class bodies of constants are skipped.
+func parseJavaEnumConstants(lines []string, start int) []javaEnumConstant {
+	var constants []javaEnumConstant
+	depth := -1 // -1 until the enum body opens
+	expectName := true
+
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case depth < 0:
+				if c == '{' {
+					depth = 0
+				}
+			case c == '(' || c == '{':
+				depth++
+			case c == ')' || c == '}':
+				if depth == 0 {
+					return constants
+				}
+				depth--
+			case depth > 0:
+			case c == ';':
+				return constants
+			case c == ',':
+				expectName = true
+			case c == '@':
+				// Skip the name of an annotation on a constant
+				for j+1 < len(line) && isJavaIdentifierChar(line[j+1]) {
+					j++
+				}
+			case expectName && isJavaIdentifierChar(c) && (c < '0' || c > '9'):
+				end := j
+				for end < len(line) && isJavaIdentifierChar(line[end]) {
+					end++
+				}
+				constants = append(constants, javaEnumConstant{Name: line[j:end], LineNumber: i + 1})
+				expectName = false
+				j = end - 1
+			}
+		}
+	}
+	return constants
+}
+
+// isJavaIdentifierChar reports whether c can be part of a Java identifier
+func isJavaIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// javaTypeAnnotations returns the annotations of the type declared on line decl:
+// those on the lines directly above it and those before the declaration keyword
+// on the line itself
+func javaTypeAnnotations(lines []string, decl int) []javaAnnotation {
+	var annotations []javaAnnotation
+
+	start := decl
+	for start > 0 {
+		previous := strings.TrimSpace(lines[start-1])
+		if !strings.HasPrefix(previous, "@") || strings.HasPrefix(previous, "@interface") {
+			break
+		}
+		start--
+	}
+
+	for i := start; i <= decl; i++ {
+		line := strings.TrimSpace(lines[i])
+		if i == decl {
+			// Only the modifiers before the declaration keyword can hold annotations
+			line = line[:javaDeclarationKeywordIndex(line)]
+		}
+		for _, match := range javaAnnotationRegex.FindAllStringSubmatch(line, -1) {
+			name := match[1]
+			if idx := strings.LastIndex(name, "."); idx >= 0 {
+				name = name[idx+1:]
+			}
+			annotations = append(annotations, javaAnnotation{
+				Name:       name,
+				Arguments:  strings.TrimSpace(match[2]),
+				LineNumber: i + 1,
+			})
+		}
+	}
+	return annotations
+}
+
+// javaDeclarationKeywordIndex returns the index of the class, enum or @interface
+// keyword in a declaration line
+func javaDeclarationKeywordIndex(line string) int {
+	best := len(line)
+	for _, keyword := range []string{"@interface ", "class ", "enum ", "interface "} {
+		if idx := strings.Index(line, keyword); idx >= 0 && idx < best {
+			best = idx
+		}
+	}
+	return best
+}
@@ -3,6 +3,10 @@ package analyzers
 import (
 	"codegraphgen/internal/core/graph"
 	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
 // JSONAnalyzer implements the LanguageAnalyzer interface for JSON
@@ -19,43 +23,224 @@ func analyzeJSONFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Enti
 	entities := []graph.Entity{fileEntity}
 	var relationships []graph.Relationship
 
-	// For package.json, extract dependencies
+	// For package.json, extract dependencies, scripts, entry points and engines
 	if file.Name == "package.json" {
 		var packageData map[string]interface{}
 		if err := json.Unmarshal([]byte(file.Content), &packageData); err == nil {
-			// Extract dependencies
-			if deps, ok := packageData["dependencies"].(map[string]interface{}); ok {
-				for name, version := range deps {
-					if versionStr, ok := version.(string); ok {
-						depEntity := graph.CreateEntity(name, graph.EntityTypeDependency, graph.Properties{
-							"version":    versionStr,
-							"sourceFile": file.Path,
-							"type":       "dependency",
-						})
-						entities = append(entities, depEntity)
-						relationships = append(relationships, graph.CreateRelationship(
-							fileEntity.ID, depEntity.ID, graph.RelationshipTypeDependsOn, nil))
-					}
+			packageEntities, packageRelationships := analyzePackageJSON(file, fileEntity, packageData)
+			entities = append(entities, packageEntities...)
+			relationships = append(relationships, packageRelationships...)
+		}
+	}
+
+	// For composer.json, extract PHP dependencies, autoload namespaces and scripts
+	if file.Name == "composer.json" {
+		var composerData map[string]interface{}
+		if err := json.Unmarshal([]byte(file.Content), &composerData); err == nil {
+			composerEntities, composerRelationships := analyzeComposerJSON(file, fileEntity, composerData)
+			entities = append(entities, composerEntities...)
+			relationships = append(relationships, composerRelationships...)
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// analyzePackageJSON extracts the dependencies and devDependencies, the scripts,
+// the main, module and exports entry points and the engines of a parsed
+// package.json
+func analyzePackageJSON(file graph.CodeFile, fileEntity graph.Entity, packageData map[string]interface{}) ([]graph.Entity, []graph.Relationship) {
+	var entities []graph.Entity
+	var relationships []graph.Relationship
+	define := func(entity graph.Entity, relType graph.RelationshipType) {
+		entities = append(entities, entity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, entity.ID, relType, nil))
+	}
+
+	for _, block := range []struct{ key, depType string }{
+		{"dependencies", "dependency"},
+		{"devDependencies", "devDependency"},
+	} {
+		deps, _ := packageData[block.key].(map[string]interface{})
+		for _, name := range sortedKeys(deps) {
+			if version, ok := deps[name].(string); ok {
+				define(graph.CreateEntity(name, graph.EntityTypeDependency, graph.Properties{
+					"version":    version,
+					"sourceFile": file.Path,
+					"type":       block.depType,
+				}), graph.RelationshipTypeDependsOn)
+			}
+		}
+	}
+
+	scripts, _ := packageData["scripts"].(map[string]interface{})
+	for _, name := range sortedKeys(scripts) {
+		script, ok := scripts[name].(string)
+		if !ok {
+			continue
+		}
+		define(graph.CreateEntity(name, graph.EntityTypeFunction, graph.Properties{
+			"sourceFile": file.Path,
+			"scriptName": name,
+			"command":    "npm run " + name,
+			"script":     script,
+			"kind":       "npmScript",
+		}), graph.RelationshipTypeDefines)
+	}
+
+	// An entry point file may be named by several fields, e.g. by main and by
+	// the require condition of exports, so it is one entity listing its fields
+	var targets []string
+	fields := make(map[string][]string)
+	addEntryPoint := func(target, field string) {
+		if _, ok := fields[target]; !ok {
+			targets = append(targets, target)
+		}
+		fields[target] = append(fields[target], field)
+	}
+	for _, field := range []string{"main", "module"} {
+		if target, ok := packageData[field].(string); ok && target != "" {
+			addEntryPoint(target, field)
+		}
+	}
+	collectPackageExports(packageData["exports"], "exports", addEntryPoint)
+	for _, target := range targets {
+		define(graph.CreateEntity(target, graph.EntityTypeExport, graph.Properties{
+			"sourceFile": file.Path,
+			"target":     target,
+			"fields":     fields[target],
+			"kind":       "entryPoint",
+		}), graph.RelationshipTypeDefines)
+	}
+
+	engines, _ := packageData["engines"].(map[string]interface{})
+	for _, engine := range sortedKeys(engines) {
+		if constraint, ok := engines[engine].(string); ok {
+			define(graph.CreateEntity(engine, graph.EntityTypeConfiguration, graph.Properties{
+				"sourceFile": file.Path,
+				"engine":     engine,
+				"version":    constraint,
+				"kind":       "engine",
+			}), graph.RelationshipTypeDefines)
+		}
+	}
+
+	return entities, relationships
+}
+
+// collectPackageExports walks the exports field of a package.json, which maps
+// subpaths and conditions to files, and passes each file with the key path
+// leading to it, such as exports["."].import. Fallback arrays list their files
+// in order.
+func collectPackageExports(value interface{}, field string, add func(target, field string)) {
+	switch exports := value.(type) {
+	case string:
+		add(exports, field)
+	case []interface{}:
+		for _, fallback := range exports {
+			collectPackageExports(fallback, field, add)
+		}
+	case map[string]interface{}:
+		for _, key := range sortedKeys(exports) {
+			keyField := field + "." + key
+			if strings.HasPrefix(key, ".") {
+				keyField = fmt.Sprintf("%s[%q]", field, key)
+			}
+			collectPackageExports(exports[key], keyField, add)
+		}
+	}
+}
+
+// analyzeComposerJSON extracts the require and require-dev dependencies, the PSR-4
+// autoload namespaces and the scripts of a parsed composer.json
+func analyzeComposerJSON(file graph.CodeFile, fileEntity graph.Entity, composerData map[string]interface{}) ([]graph.Entity, []graph.Relationship) {
+	var entities []graph.Entity
+	var relationships []graph.Relationship
+
+	for _, block := range []struct{ key, depType string }{
+		{"require", "dependency"},
+		{"require-dev", "devDependency"},
+	} {
+		deps, _ := composerData[block.key].(map[string]interface{})
+		for _, name := range sortedKeys(deps) {
+			version, ok := deps[name].(string)
+			if !ok {
+				continue
+			}
+			depEntity := graph.CreateEntity(name, graph.EntityTypeDependency, graph.Properties{
+				"name":       name,
+				"version":    version,
+				"sourceFile": file.Path,
+				"type":       block.depType,
+			})
+			entities = append(entities, depEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, depEntity.ID, graph.RelationshipTypeDependsOn, nil))
+		}
+	}
+
+	// PSR-4 maps namespace prefixes to one or more directories relative to composer.json
+	autoload, _ := composerData["autoload"].(map[string]interface{})
+	psr4, _ := autoload["psr-4"].(map[string]interface{})
+	for _, prefix := range sortedKeys(psr4) {
+		var directories []string
+		switch dirs := psr4[prefix].(type) {
+		case string:
+			directories = append(directories, filepath.Join(filepath.Dir(file.Path), dirs))
+		case []interface{}:
+			for _, dir := range dirs {
+				if dirStr, ok := dir.(string); ok {
+					directories = append(directories, filepath.Join(filepath.Dir(file.Path), dirStr))
 				}
 			}
+		}
+
+		namespaceEntity := graph.CreateEntity(strings.TrimSuffix(prefix, "\\"), graph.EntityTypeNamespace, graph.Properties{
+			"sourceFile":  file.Path,
+			"directories": directories,
+			"autoload":    "psr-4",
+			"language":    "php",
+		})
+		entities = append(entities, namespaceEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, namespaceEntity.ID, graph.RelationshipTypeDefines, nil))
+	}
 
-			// Extract devDependencies
-			if devDeps, ok := packageData["devDependencies"].(map[string]interface{}); ok {
-				for name, version := range devDeps {
-					if versionStr, ok := version.(string); ok {
-						depEntity := graph.CreateEntity(name, graph.EntityTypeDependency, graph.Properties{
-							"version":    versionStr,
-							"sourceFile": file.Path,
-							"type":       "devDependency",
-						})
-						entities = append(entities, depEntity)
-						relationships = append(relationships, graph.CreateRelationship(
-							fileEntity.ID, depEntity.ID, graph.RelationshipTypeDependsOn, nil))
-					}
+	// A script runs a single command or a list of commands
+	scripts, _ := composerData["scripts"].(map[string]interface{})
+	for _, name := range sortedKeys(scripts) {
+		var commands []string
+		switch script := scripts[name].(type) {
+		case string:
+			commands = append(commands, script)
+		case []interface{}:
+			for _, command := range script {
+				if commandStr, ok := command.(string); ok {
+					commands = append(commands, commandStr)
 				}
 			}
 		}
+
+		scriptEntity := graph.CreateEntity(name, graph.EntityTypeFunction, graph.Properties{
+			"sourceFile": file.Path,
+			"commands":   commands,
+			"kind":       "composerScript",
+		})
+		entities = append(entities, scriptEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, scriptEntity.ID, graph.RelationshipTypeDefines, nil))
 	}
 
-	return entities, relationships, nil
+	return entities, relationships
+}
+
+// sortedKeys returns the keys of a JSON object in a stable order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,215 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"regexp"
+	"strings"
+)
+
+var (
+	makeVariableRegex = regexp.MustCompile(`^(?:export\s+|override\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*(::=|:=|\?=|\+=|!=|=)\s*(.*)$`)
+	makeIncludeRegex  = regexp.MustCompile(`^(-include|sinclude|include)\s+(.+)$`)
+	makeRuleRegex     = regexp.MustCompile(`^([^:=#\t][^:=#]*?)\s*(::?)\s*(.*)$`)
+)
+
+// MakeTarget represents a target of a Makefile rule
+type MakeTarget struct {
+	Name         string
+	Dependencies []string
+	LineNumber   int
+	IsPhony      bool
+	IsPattern    bool
+}
+
+// MakeVariable represents a Makefile variable definition
+type MakeVariable struct {
+	Name       string
+	Value      string
+	Operator   string
+	LineNumber int
+}
+
+// MakeInclude represents an include directive
+type MakeInclude struct {
+	Path       string
+	Optional   bool
+	LineNumber int
+}
+
+// analyzeMakefile extracts targets, variables and includes from a Makefile.
+// Prerequisites that are targets of the same Makefile become DEPENDS_ON edges.
+func analyzeMakefile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	targets, variables, includes := parseMakefile(file.Content)
+
+	for _, inc := range includes {
+		importEntity := graph.CreateEntity(inc.Path, graph.EntityTypeImport, graph.Properties{
+			"source":     inc.Path,
+			"optional":   inc.Optional,
+			"lineNumber": inc.LineNumber,
+			"language":   "make",
+		})
+		entities = append(entities, importEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+				"importedAt": inc.LineNumber,
+			}))
+	}
+
+	for _, variable := range variables {
+		variableEntity := graph.CreateEntity(variable.Name, graph.EntityTypeVariable, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": variable.LineNumber,
+			"value":      variable.Value,
+			"operator":   variable.Operator,
+			"language":   "make",
+		})
+		entities = append(entities, variableEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, variableEntity.ID, graph.RelationshipTypeDefines, nil))
+	}
+
+	targetIDs := make(map[string]string, len(targets))
+	for _, target := range targets {
+		targetEntity := graph.CreateEntity(target.Name, graph.EntityTypeFunction, graph.Properties{
+			"sourceFile":   file.Path,
+			"lineNumber":   target.LineNumber,
+			"kind":         "target",
+			"dependencies": target.Dependencies,
+			"isPhony":      target.IsPhony,
+			"isPattern":    target.IsPattern,
+			"language":     "make",
+		})
+		targetIDs[target.Name] = targetEntity.ID
+		entities = append(entities, targetEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, targetEntity.ID, graph.RelationshipTypeDefines, nil))
+	}
+
+	for _, target := range targets {
+		for _, dep := range target.Dependencies {
+			if depID, ok := targetIDs[dep]; ok && dep != target.Name {
+				relationships = append(relationships, graph.CreateRelationship(
+					targetIDs[target.Name], depID, graph.RelationshipTypeDependsOn, nil))
+			}
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// parseMakefile extracts rules, variable definitions and include directives.
+// Targets defined by several rules are merged; recipe lines are ignored.
+func parseMakefile(content string) ([]MakeTarget, []MakeVariable, []MakeInclude) {
+	var targets []MakeTarget
+	var variables []MakeVariable
+	var includes []MakeInclude
+	targetIndex := make(map[string]int)
+	seenVariables := make(map[string]bool)
+	phony := make(map[string]bool)
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := lines[i]
+
+		// Recipe lines belong to the previous rule
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		// Join continuation lines
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + " " + strings.TrimSpace(lines[i])
+		}
+
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if matches := makeIncludeRegex.FindStringSubmatch(line); matches != nil {
+			for _, path := range strings.Fields(matches[2]) {
+				includes = append(includes, MakeInclude{
+					Path:       path,
+					Optional:   matches[1] != "include",
+					LineNumber: lineNumber,
+				})
+			}
+			continue
+		}
+
+		if matches := makeVariableRegex.FindStringSubmatch(line); matches != nil {
+			if !seenVariables[matches[1]] {
+				seenVariables[matches[1]] = true
+				variables = append(variables, MakeVariable{
+					Name:       matches[1],
+					Value:      strings.TrimSpace(matches[3]),
+					Operator:   matches[2],
+					LineNumber: lineNumber,
+				})
+			}
+			continue
+		}
+
+		matches := makeRuleRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		prerequisites := matches[3]
+		// Target-specific variables (target: VAR = value) are not rules
+		if strings.Contains(prerequisites, "=") {
+			continue
+		}
+		// Inline recipes follow a semicolon
+		if idx := strings.Index(prerequisites, ";"); idx >= 0 {
+			prerequisites = prerequisites[:idx]
+		}
+
+		var deps []string
+		for _, dep := range strings.Fields(prerequisites) {
+			// Order-only prerequisites follow a pipe
+			if dep != "|" {
+				deps = append(deps, dep)
+			}
+		}
+
+		for _, name := range strings.Fields(matches[1]) {
+			if name == ".PHONY" {
+				for _, dep := range deps {
+					phony[dep] = true
+				}
+				continue
+			}
+			// Other special targets (.SUFFIXES, .DEFAULT, ...) configure make itself
+			if strings.HasPrefix(name, ".") && strings.ToUpper(name) == name {
+				continue
+			}
+
+			if idx, ok := targetIndex[name]; ok {
+				targets[idx].Dependencies = append(targets[idx].Dependencies, deps...)
+				continue
+			}
+			targetIndex[name] = len(targets)
+			targets = append(targets, MakeTarget{
+				Name:         name,
+				Dependencies: append([]string(nil), deps...),
+				LineNumber:   lineNumber,
+				IsPattern:    strings.Contains(name, "%"),
+			})
+		}
+	}
+
+	for i := range targets {
+		targets[i].IsPhony = phony[targets[i].Name]
+	}
+
+	return targets, variables, includes
+}
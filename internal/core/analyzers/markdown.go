@@ -0,0 +1,236 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MarkdownAnalyzer implements the LanguageAnalyzer interface for Markdown
+// documentation. Headings and fenced code blocks become COMMENT entities,
+// links to local Markdown files become IMPORT entities and TODO/FIXME markers
+// become ANNOTATION entities.
+type MarkdownAnalyzer struct{}
+
+func (ma *MarkdownAnalyzer) Name() string                 { return "Markdown Analyzer" }
+func (ma *MarkdownAnalyzer) SupportedLanguages() []string { return []string{"markdown"} }
+func (ma *MarkdownAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	return analyzeMarkdownFile(file, fileEntity)
+}
+
+var (
+	markdownHeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	markdownFenceRegex   = regexp.MustCompile("^ {0,3}(```+|~~~+)\\s*([\\w+#.-]*)")
+	markdownLinkRegex    = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	markdownMarkerRegex  = regexp.MustCompile(`\b(TODO|FIXME)\b:?\s*(.*)`)
+)
+
+// MarkdownHeading represents an ATX heading
+type MarkdownHeading struct {
+	Text       string
+	Level      int
+	LineNumber int
+}
+
+// MarkdownCodeBlock represents a fenced code block. Heading is the index of
+// the heading of the section it appears in, or -1 before the first heading.
+type MarkdownCodeBlock struct {
+	Language   string
+	LineNumber int
+	EndLine    int
+	Heading    int
+}
+
+// MarkdownLink represents a link to another local Markdown file
+type MarkdownLink struct {
+	Text       string
+	Target     string
+	Anchor     string
+	LineNumber int
+}
+
+// MarkdownMarker represents a TODO or FIXME marker in the text
+type MarkdownMarker struct {
+	Marker     string
+	Text       string
+	LineNumber int
+	Heading    int
+}
+
+// analyzeMarkdownFile analyzes a Markdown file for documentation entities.
+// Each heading REFERENCES the code blocks of its section.
+func analyzeMarkdownFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	headings, codeBlocks, links, markers := parseMarkdown(file.Content)
+
+	headingIDs := make([]string, len(headings))
+	for i, heading := range headings {
+		headingEntity := graph.CreateEntity(heading.Text, graph.EntityTypeComment, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": heading.LineNumber,
+			"level":      heading.Level,
+			"kind":       "heading",
+			"language":   "markdown",
+		})
+		entities = append(entities, headingEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, headingEntity.ID, graph.RelationshipTypeDefines, nil))
+		headingIDs[i] = headingEntity.ID
+	}
+
+	for _, block := range codeBlocks {
+		label := "code block"
+		if block.Language != "" {
+			label = fmt.Sprintf("%s code block", block.Language)
+		}
+		blockEntity := graph.CreateEntity(label, graph.EntityTypeComment, graph.Properties{
+			"sourceFile":   file.Path,
+			"lineNumber":   block.LineNumber,
+			"endLine":      block.EndLine,
+			"codeLanguage": block.Language,
+			"kind":         "codeBlock",
+			"language":     "markdown",
+		})
+		entities = append(entities, blockEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, blockEntity.ID, graph.RelationshipTypeDefines, nil))
+		if block.Heading >= 0 {
+			relationships = append(relationships, graph.CreateRelationship(
+				headingIDs[block.Heading], blockEntity.ID, graph.RelationshipTypeReferences, nil))
+		}
+	}
+
+	for _, link := range links {
+		importEntity := graph.CreateEntity(link.Target, graph.EntityTypeImport, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": link.LineNumber,
+			"source":     link.Target,
+			"text":       link.Text,
+			"anchor":     link.Anchor,
+			"language":   "markdown",
+		})
+		entities = append(entities, importEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+				"importedAt": link.LineNumber,
+			}))
+	}
+
+	for _, marker := range markers {
+		annotationEntity := graph.CreateEntity(marker.Marker, graph.EntityTypeAnnotation, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": marker.LineNumber,
+			"text":       marker.Text,
+			"language":   "markdown",
+		})
+		entities = append(entities, annotationEntity)
+		target := fileEntity.ID
+		if marker.Heading >= 0 {
+			target = headingIDs[marker.Heading]
+		}
+		relationships = append(relationships, graph.CreateRelationship(
+			annotationEntity.ID, target, graph.RelationshipTypeAnnotates, nil))
+	}
+
+	return entities, relationships, nil
+}
+
+// parseMarkdown scans a Markdown document line by line. Headings, links and
+// markers inside fenced code blocks are ignored.
+func parseMarkdown(content string) ([]MarkdownHeading, []MarkdownCodeBlock, []MarkdownLink, []MarkdownMarker) {
+	var headings []MarkdownHeading
+	var codeBlocks []MarkdownCodeBlock
+	var links []MarkdownLink
+	var markers []MarkdownMarker
+
+	lines := strings.Split(content, "\n")
+	currentHeading := -1
+	fence := ""
+	var openBlock *MarkdownCodeBlock
+
+	for i, line := range lines {
+		lineNumber := i + 1
+		line = strings.TrimRight(line, "\r")
+
+		if match := markdownFenceRegex.FindStringSubmatch(line); match != nil {
+			if openBlock == nil {
+				fence = match[1]
+				openBlock = &MarkdownCodeBlock{
+					Language:   strings.ToLower(match[2]),
+					LineNumber: lineNumber,
+					Heading:    currentHeading,
+				}
+				continue
+			}
+			// A closing fence uses the same character and is at least as long
+			if match[1][0] == fence[0] && len(match[1]) >= len(fence) && match[2] == "" {
+				openBlock.EndLine = lineNumber
+				codeBlocks = append(codeBlocks, *openBlock)
+				openBlock = nil
+				continue
+			}
+		}
+		if openBlock != nil {
+			continue
+		}
+
+		if match := markdownHeadingRegex.FindStringSubmatch(line); match != nil && match[2] != "" {
+			headings = append(headings, MarkdownHeading{
+				Text:       match[2],
+				Level:      len(match[1]),
+				LineNumber: lineNumber,
+			})
+			currentHeading = len(headings) - 1
+		}
+
+		for _, match := range markdownLinkRegex.FindAllStringSubmatch(line, -1) {
+			if match[1] != "" {
+				continue // images
+			}
+			if target, anchor, ok := localMarkdownLink(match[3]); ok {
+				links = append(links, MarkdownLink{
+					Text:       match[2],
+					Target:     target,
+					Anchor:     anchor,
+					LineNumber: lineNumber,
+				})
+			}
+		}
+
+		if match := markdownMarkerRegex.FindStringSubmatch(line); match != nil {
+			markers = append(markers, MarkdownMarker{
+				Marker:     match[1],
+				Text:       strings.TrimSpace(match[2]),
+				LineNumber: lineNumber,
+				Heading:    currentHeading,
+			})
+		}
+	}
+
+	// An unterminated fence runs to the end of the document
+	if openBlock != nil {
+		openBlock.EndLine = len(lines)
+		codeBlocks = append(codeBlocks, *openBlock)
+	}
+
+	return headings, codeBlocks, links, markers
+}
+
+// localMarkdownLink reports whether a link target points to a Markdown file
+// in the same repository and splits off its anchor
+func localMarkdownLink(target string) (string, string, bool) {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", "", false
+	}
+	ext := strings.ToLower(path.Ext(u.Path))
+	if ext != ".md" && ext != ".markdown" {
+		return "", "", false
+	}
+	return u.Path, u.Fragment, true
+}
@@ -0,0 +1,341 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	pipRequirementRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[([^\]]*)\])?\s*(.*)$`)
+	pipEggRegex         = regexp.MustCompile(`#egg=([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pipTableRegex       = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+	pipKeyRegex         = regexp.MustCompile(`^("[^"]+"|'[^']+'|[A-Za-z0-9._-]+)\s*=\s*(.*)$`)
+	pipInlineKeyRegex   = regexp.MustCompile(`\b(version|extras|optional)\s*=\s*("[^"]*"|'[^']*'|\[[^\]]*\]|true|false)`)
+	pipPoetryGroupRegex = regexp.MustCompile(`^tool\.poetry\.group\.([^.]+)\.dependencies$`)
+	pipStringRegex      = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// PythonRequirement represents a package required by a requirements file,
+// Pipfile or pyproject.toml
+type PythonRequirement struct {
+	Name       string
+	Version    string
+	Extras     []string
+	Markers    string
+	Group      string
+	IsDev      bool
+	IsOptional bool
+	IsEditable bool
+	LineNumber int
+}
+
+// IsPythonDependencyFile reports whether a file name is a requirements file,
+// Pipfile or pyproject.toml
+func IsPythonDependencyFile(name string) bool {
+	switch name {
+	case "requirements.txt", "requirements-dev.txt", "Pipfile", "pyproject.toml":
+		return true
+	}
+	return false
+}
+
+// analyzePythonDependencies creates a DEPENDENCY entity for every package of a
+// requirements file, Pipfile or pyproject.toml. Editable installs of local
+// directories (-e .) aren't dependencies of their own: their paths are recorded
+// as the editableInstalls of the file entity, so analysis.LinkPythonDependencies
+// can link the file to the local packages.
+func analyzePythonDependencies(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	var requirements []PythonRequirement
+	var editablePaths []string
+	switch file.Name {
+	case "Pipfile", "pyproject.toml":
+		requirements = parsePythonTOMLDependencies(file.Content)
+	default:
+		requirements, editablePaths = parsePythonRequirements(file.Content, file.Name == "requirements-dev.txt")
+	}
+
+	if len(editablePaths) > 0 {
+		dir := filepath.Dir(file.Path)
+		installs := make([]string, 0, len(editablePaths))
+		for _, path := range editablePaths {
+			installs = append(installs, filepath.Join(dir, path))
+		}
+		fileEntity.Properties["editableInstalls"] = installs
+	}
+
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	seen := make(map[string]bool)
+	for _, requirement := range requirements {
+		key := strings.ToLower(requirement.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		depType := "dependency"
+		if requirement.IsDev {
+			depType = "devDependency"
+		}
+		properties := graph.Properties{
+			"name":       requirement.Name,
+			"version":    requirement.Version,
+			"sourceFile": file.Path,
+			"lineNumber": requirement.LineNumber,
+			"type":       depType,
+			"language":   "python",
+		}
+		if len(requirement.Extras) > 0 {
+			properties["extras"] = requirement.Extras
+		}
+		if requirement.Markers != "" {
+			properties["markers"] = requirement.Markers
+		}
+		if requirement.Group != "" {
+			properties["group"] = requirement.Group
+		}
+		if requirement.IsOptional {
+			properties["isOptional"] = true
+		}
+		if requirement.IsEditable {
+			properties["isEditable"] = true
+		}
+
+		depEntity := graph.CreateEntity(requirement.Name, graph.EntityTypeDependency, properties)
+		entities = append(entities, depEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, depEntity.ID, graph.RelationshipTypeDependsOn, nil))
+	}
+
+	return entities, relationships, nil
+}
+
+// parsePythonRequirements returns the packages of a requirements file and the
+// local paths installed in editable mode. Options other than -e are skipped,
+// including -r references to other requirements files.
+func parsePythonRequirements(content string, isDev bool) ([]PythonRequirement, []string) {
+	var requirements []PythonRequirement
+	var editablePaths []string
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "-e ") || strings.HasPrefix(line, "--editable ") {
+			target := strings.TrimSpace(line[strings.Index(line, " "):])
+			if match := pipEggRegex.FindStringSubmatch(target); match != nil {
+				// A VCS checkout such as git+https://...#egg=name
+				requirements = append(requirements, PythonRequirement{
+					Name:       match[1],
+					IsDev:      isDev,
+					IsEditable: true,
+					LineNumber: i + 1,
+				})
+			} else if !strings.Contains(target, "://") {
+				path := strings.TrimPrefix(target, "file:")
+				if index := strings.Index(path, "["); index >= 0 {
+					path = path[:index]
+				}
+				editablePaths = append(editablePaths, path)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		requirement, ok := parsePythonRequirement(line)
+		if !ok {
+			continue
+		}
+		requirement.IsDev = isDev
+		requirement.LineNumber = i + 1
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, editablePaths
+}
+
+// parsePythonRequirement parses a PEP 508 requirement such as
+// "requests[security]>=2.8,<3; python_version >= '3.8'"
+func parsePythonRequirement(spec string) (PythonRequirement, bool) {
+	var markers string
+	if index := strings.Index(spec, ";"); index >= 0 {
+		markers = strings.TrimSpace(spec[index+1:])
+		spec = spec[:index]
+	}
+
+	match := pipRequirementRegex.FindStringSubmatch(strings.TrimSpace(spec))
+	if match == nil {
+		return PythonRequirement{}, false
+	}
+
+	requirement := PythonRequirement{
+		Name:    match[1],
+		Extras:  splitPythonList(match[2]),
+		Markers: markers,
+	}
+	// Direct references (name @ url) have no version
+	if version := strings.TrimSpace(match[3]); !strings.HasPrefix(version, "@") {
+		requirement.Version = strings.Join(strings.Fields(version), "")
+	}
+	return requirement, true
+}
+
+// parsePythonTOMLDependencies returns the packages of a Pipfile ([packages] and
+// [dev-packages]) or a pyproject.toml: the PEP 621 dependencies and
+// optional-dependencies of [project] and the Poetry dependency tables. The
+// python version requirement is not a package and is skipped.
+func parsePythonTOMLDependencies(content string) []PythonRequirement {
+	var requirements []PythonRequirement
+
+	table := ""
+	// arrayKey is set while reading a multi-line array of requirement strings
+	arrayKey := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if arrayKey != "" {
+			requirements = append(requirements, pythonArrayRequirements(line, table, arrayKey, i+1)...)
+			if closesPythonArray(line) {
+				arrayKey = ""
+			}
+			continue
+		}
+
+		if match := pipTableRegex.FindStringSubmatch(line); match != nil {
+			table = strings.TrimSpace(match[1])
+			continue
+		}
+
+		match := pipKeyRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		key := strings.Trim(match[1], `"'`)
+		value := strings.TrimSpace(match[2])
+
+		switch {
+		case table == "project" && key == "dependencies", table == "project.optional-dependencies":
+			if !strings.HasPrefix(value, "[") {
+				continue
+			}
+			requirements = append(requirements, pythonArrayRequirements(value, table, key, i+1)...)
+			if !closesPythonArray(value[1:]) {
+				arrayKey = key
+			}
+		case isPythonPackageTable(table):
+			if strings.EqualFold(key, "python") {
+				continue
+			}
+			requirement := PythonRequirement{Name: key, LineNumber: i + 1}
+			requirement.IsDev, requirement.Group = pythonTableGroup(table)
+			if strings.HasPrefix(value, "{") {
+				for _, option := range pipInlineKeyRegex.FindAllStringSubmatch(value, -1) {
+					switch option[1] {
+					case "version":
+						requirement.Version = strings.Trim(option[2], `"'`)
+					case "extras":
+						requirement.Extras = splitPythonList(strings.Trim(option[2], "[]"))
+					case "optional":
+						requirement.IsOptional = option[2] == "true"
+					}
+				}
+			} else {
+				requirement.Version = strings.Trim(value, `"'`)
+			}
+			if requirement.Version == "*" {
+				requirement.Version = ""
+			}
+			requirements = append(requirements, requirement)
+		}
+	}
+
+	return requirements
+}
+
+// pythonArrayRequirements parses the requirement strings of a PEP 621 array, or
+// of one line of a multi-line array
+func pythonArrayRequirements(value, table, key string, lineNumber int) []PythonRequirement {
+	var requirements []PythonRequirement
+	for _, match := range pipStringRegex.FindAllStringSubmatch(value, -1) {
+		spec := match[1] + match[2]
+		requirement, ok := parsePythonRequirement(spec)
+		if !ok {
+			continue
+		}
+		requirement.LineNumber = lineNumber
+		if table == "project.optional-dependencies" {
+			requirement.IsOptional = true
+			requirement.Group = key
+			requirement.IsDev = isPythonDevGroup(key)
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements
+}
+
+// closesPythonArray reports whether a line of an array holds its closing
+// bracket outside of the requirement strings, which may contain brackets for
+// extras
+func closesPythonArray(line string) bool {
+	return strings.Contains(pipStringRegex.ReplaceAllString(line, ""), "]")
+}
+
+// isPythonPackageTable reports whether a TOML table lists packages by name
+func isPythonPackageTable(table string) bool {
+	switch table {
+	case "packages", "dev-packages", "tool.poetry.dependencies", "tool.poetry.dev-dependencies":
+		return true
+	}
+	return pipPoetryGroupRegex.MatchString(table)
+}
+
+// pythonTableGroup returns whether the packages of a table are development
+// dependencies, and the name of their Poetry group
+func pythonTableGroup(table string) (bool, string) {
+	switch table {
+	case "dev-packages", "tool.poetry.dev-dependencies":
+		return true, ""
+	}
+	if match := pipPoetryGroupRegex.FindStringSubmatch(table); match != nil {
+		return isPythonDevGroup(match[1]), match[1]
+	}
+	return false, ""
+}
+
+// isPythonDevGroup reports whether a dependency group or extra is only used
+// during development
+func isPythonDevGroup(group string) bool {
+	switch strings.ToLower(group) {
+	case "dev", "development", "test", "tests", "testing", "lint", "docs":
+		return true
+	}
+	return false
+}
+
+// splitPythonList splits a comma-separated list such as the extras of a
+// requirement, dropping quotes and empty items
+func splitPythonList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), `"'`); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
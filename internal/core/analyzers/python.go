@@ -3,6 +3,7 @@ package analyzers
 import (
 	"codegraphgen/internal/core/graph"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -22,6 +23,10 @@ func analyzePythonFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.En
 
 	content := file.Content
 
+	// Class and function declarations by line index, to attach docstrings and
+	// comment markers to
+	declarations := make(map[int]graph.Entity)
+
 	// Extract Python classes
 	classRegex := regexp.MustCompile(`^class\s+(\w+)(?:\(([^)]*)\))?:`)
 	lines := strings.Split(content, "\n")
@@ -48,14 +53,15 @@ func analyzePythonFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.En
 				"extends":    extends,
 			})
 			entities = append(entities, classEntity)
+			declarations[i] = classEntity
 			relationships = append(relationships, graph.CreateRelationship(
 				fileEntity.ID, classEntity.ID, graph.RelationshipTypeDefines, nil))
 		}
 	}
 
-	// Extract Python functions
-	funcRegex := regexp.MustCompile(`^def\s+(\w+)\s*\(`)
-	methodRegex := regexp.MustCompile(`^\s+def\s+(\w+)\s*\(`)
+	// Extract Python functions, including coroutines declared with async def
+	funcRegex := regexp.MustCompile(`^(?:async\s+)?def\s+(\w+)\s*\(`)
+	methodRegex := regexp.MustCompile(`^\s+(?:async\s+)?def\s+(\w+)\s*\(`)
 
 	for i, line := range lines {
 		// Top-level functions
@@ -67,6 +73,7 @@ func analyzePythonFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.En
 				"language":   "python",
 			})
 			entities = append(entities, funcEntity)
+			declarations[i] = funcEntity
 			relationships = append(relationships, graph.CreateRelationship(
 				fileEntity.ID, funcEntity.ID, graph.RelationshipTypeDefines, nil))
 		}
@@ -80,10 +87,121 @@ func analyzePythonFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.En
 				"language":   "python",
 			})
 			entities = append(entities, methodEntity)
+			declarations[i] = methodEntity
 			// Note: In a full implementation, you'd associate methods with their classes
 		}
 	}
 
+	// Extract class variables and the instance attributes assigned in __init__
+	for i := range lines {
+		classEntity, ok := declarations[i]
+		if !ok || classEntity.Type != graph.EntityTypeClass {
+			continue
+		}
+		for _, attribute := range extractPythonAttributes(lines, i) {
+			properties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": attribute.LineNumber,
+				"language":   "python",
+				"scope":      attribute.Scope,
+				"owner":      classEntity.Label,
+			}
+			if attribute.Type != "" {
+				properties["type"] = attribute.Type
+			}
+			attributeEntity := graph.CreateEntity(attribute.Name, graph.EntityTypeProperty, properties)
+			entities = append(entities, attributeEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				classEntity.ID, attributeEntity.ID, graph.RelationshipTypeContains, nil))
+		}
+	}
+
+	// Extract Flask and FastAPI routes, calling the function they decorate
+	for _, route := range extractPythonRoutes(content, lines) {
+		label := strings.Join(route.Methods, ",") + " " + route.Path
+		endpointEntity := graph.CreateEntity(label, graph.EntityTypeAPIEndpoint, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": route.LineNumber,
+			"language":   "python",
+			"path":       route.Path,
+			"methods":    route.Methods,
+			"framework":  route.Framework,
+		})
+		entities = append(entities, endpointEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, endpointEntity.ID, graph.RelationshipTypeDefines, nil))
+		if handler, ok := declarations[route.Handler]; ok {
+			relationships = append(relationships, graph.CreateRelationship(
+				endpointEntity.ID, handler.ID, graph.RelationshipTypeCalls, nil))
+		}
+	}
+
+	// Extract docstrings and the Sphinx parameter fields documented in them
+	declarationLines := make([]int, 0, len(declarations))
+	for line := range declarations {
+		declarationLines = append(declarationLines, line)
+	}
+	sort.Ints(declarationLines)
+
+	for _, line := range declarationLines {
+		declaration := declarations[line]
+		docstring, docLine, ok := extractPythonDocstring(lines, line)
+		declaration.Properties["hasDocstring"] = ok
+		if !ok {
+			continue
+		}
+
+		declaration.Properties["docstring"] = truncatePythonDocstring(docstring)
+
+		for _, param := range parsePythonSphinxParams(docstring, docLine) {
+			properties := graph.Properties{
+				"sourceFile":  file.Path,
+				"lineNumber":  param.LineNumber,
+				"language":    "python",
+				"owner":       declaration.Label,
+				"description": param.Description,
+			}
+			if param.Type != "" {
+				properties["type"] = param.Type
+			}
+			paramEntity := graph.CreateEntity(param.Name, graph.EntityTypeParameter, properties)
+			entities = append(entities, paramEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				declaration.ID, paramEntity.ID, graph.RelationshipTypeAccepts, nil))
+		}
+	}
+
+	// Extract TODO/FIXME/HACK comments, annotating the enclosing declaration. The
+	// body of a declaration follows the last line of its header.
+	blockOwners := make(map[int]graph.Entity, len(declarations))
+	for line, declaration := range declarations {
+		blockOwners[line] = declaration
+		blockOwners[pythonHeaderEnd(lines, line)] = declaration
+	}
+
+	for i, line := range lines {
+		match := pythonMarkerRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		annotationEntity := graph.CreateEntity(match[1], graph.EntityTypeAnnotation, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": i + 1,
+			"text":       strings.TrimSpace(match[2]),
+			"severity":   pythonMarkerSeverity[match[1]],
+			"language":   "python",
+		})
+		entities = append(entities, annotationEntity)
+
+		target := fileEntity.ID
+		if declaration, ok := enclosingPythonDeclaration(lines, i, blockOwners); ok {
+			target = declaration.ID
+		}
+		relationships = append(relationships, graph.CreateRelationship(
+			annotationEntity.ID, target, graph.RelationshipTypeAnnotates, nil))
+	}
+
 	// Extract imports
 	importRegex := regexp.MustCompile(`^(?:from\s+(\S+)\s+)?import\s+(.+)`)
 	for i, line := range lines {
@@ -103,9 +221,529 @@ func analyzePythonFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.En
 			})
 			entities = append(entities, importEntity)
 			relationships = append(relationships, graph.CreateRelationship(
-				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, nil))
+				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+					"importedAt": i + 1,
+				}))
+		}
+	}
+
+	// Track the module's public API declared in __all__
+	if exportedNames, hasAll := extractPythonAll(content); hasAll {
+		exported := make(map[string]bool)
+		for _, name := range exportedNames {
+			exported[name] = true
+		}
+
+		for _, entity := range entities {
+			if entity.Type != graph.EntityTypeClass && entity.Type != graph.EntityTypeFunction {
+				continue
+			}
+
+			isExported := exported[entity.Label]
+			entity.Properties["isExported"] = isExported
+			if isExported {
+				relationships = append(relationships, graph.CreateRelationship(
+					fileEntity.ID, entity.ID, graph.RelationshipTypeExports, nil))
+			}
+		}
+	}
+
+	// Record the names a package's __init__.py re-exports, so the package can be
+	// linked to them once the whole codebase has been analyzed
+	if file.Name == "__init__.py" {
+		if reExports := extractPythonReExports(content); len(reExports) > 0 {
+			names := make([]string, len(reExports))
+			modules := make([]string, len(reExports))
+			for i, reExport := range reExports {
+				names[i], modules[i] = reExport.Name, reExport.Module
+			}
+			fileEntity.Properties["reExports"] = names
+			fileEntity.Properties["reExportModules"] = modules
 		}
 	}
 
 	return entities, relationships, nil
 }
+
+// PythonRoute is an HTTP route declared by a Flask or FastAPI decorator
+type PythonRoute struct {
+	Path       string
+	Methods    []string
+	Framework  string
+	LineNumber int
+	// Handler is the line index of the decorated function, or -1 if none follows
+	Handler int
+}
+
+var (
+	// pythonRouteRegex matches the route decorators of Flask (@app.route) and
+	// FastAPI (@app.get, @router.post, @app.api_route), capturing the decorator
+	// name and its arguments, whose first is the path
+	pythonRouteRegex        = regexp.MustCompile(`^@\w+(?:\.\w+)*\.(route|api_route|get|post|put|patch|delete|head|options)\((.*)`)
+	pythonRoutePathRegex    = regexp.MustCompile(`^\s*(?:path\s*=\s*)?[rf]?['"]([^'"]*)['"]`)
+	pythonRouteMethodsRegex = regexp.MustCompile(`methods\s*=\s*[\[(]([^\])]*)[\])]`)
+	pythonStringRegex       = regexp.MustCompile(`['"](\w+)['"]`)
+	pythonFlaskImportRegex  = regexp.MustCompile(`(?m)^\s*(?:from|import)\s+flask\b`)
+	pythonDecoratedDefRegex = regexp.MustCompile(`^(?:async\s+)?def\s+\w+`)
+)
+
+// extractPythonRoutes finds the route decorators of a file. @app.route is
+// Flask's and defaults to GET, @app.api_route FastAPI's; the decorators named
+// after a method, such as @router.get, are FastAPI's unless the file imports
+// flask, whose 2.0 shortcuts look the same.
+func extractPythonRoutes(content string, lines []string) []PythonRoute {
+	shortcutFramework := "fastapi"
+	if pythonFlaskImportRegex.MatchString(content) {
+		shortcutFramework = "flask"
+	}
+
+	var routes []PythonRoute
+	for i, line := range lines {
+		match := pythonRouteRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		// The arguments may continue on the following lines
+		end := pythonDecoratorEnd(lines, i)
+		arguments := match[2]
+		for _, next := range lines[i+1 : end+1] {
+			arguments += " " + stripPythonComment(next)
+		}
+		path := pythonRoutePathRegex.FindStringSubmatch(arguments)
+		if path == nil {
+			continue
+		}
+
+		route := PythonRoute{Path: path[1], LineNumber: i + 1, Handler: -1}
+		switch match[1] {
+		case "route":
+			route.Framework = "flask"
+		case "api_route":
+			route.Framework = "fastapi"
+		default:
+			route.Framework = shortcutFramework
+			route.Methods = []string{strings.ToUpper(match[1])}
+		}
+
+		if route.Methods == nil {
+			if methods := pythonRouteMethodsRegex.FindStringSubmatch(arguments); methods != nil {
+				for _, method := range pythonStringRegex.FindAllStringSubmatch(methods[1], -1) {
+					route.Methods = append(route.Methods, strings.ToUpper(method[1]))
+				}
+			}
+			if len(route.Methods) == 0 {
+				route.Methods = []string{"GET"}
+			}
+		}
+
+		// The handler is the function below the decorator and any further
+		// decorators stacked on it
+		indent := pythonIndentation(line)
+		for j := end + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") || pythonIndentation(lines[j]) > indent {
+				continue
+			}
+			if pythonDecoratedDefRegex.MatchString(trimmed) {
+				route.Handler = j
+			}
+			if !strings.HasPrefix(trimmed, "@") {
+				break
+			}
+		}
+
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// pythonDecoratorEnd returns the line index where the arguments of the
+// decorator on line start are closed
+func pythonDecoratorEnd(lines []string, start int) int {
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		line := stripPythonComment(lines[i])
+		depth += strings.Count(line, "(") - strings.Count(line, ")")
+		if depth <= 0 {
+			return i
+		}
+	}
+	return start
+}
+
+// maxPythonDocstringLength is the number of characters of a docstring stored on
+// its class or function
+const maxPythonDocstringLength = 500
+
+var (
+	pythonDocstringStartRegex = regexp.MustCompile(`^[rRuUbB]?("""|\'\'\')`)
+	pythonSphinxParamRegex    = regexp.MustCompile(`^:param\s+(?:([\w.\[\], ]+?)\s+)?(\*{0,2}\w+)\s*:\s*(.*)`)
+	pythonSphinxTypeRegex     = regexp.MustCompile(`^:type\s+(\*{0,2}\w+)\s*:\s*(.*)`)
+	pythonMarkerRegex         = regexp.MustCompile(`#\s*(TODO|FIXME|HACK)\b:?\s*(.*)`)
+)
+
+// pythonMarkerSeverity is the severity of each kind of comment marker
+var pythonMarkerSeverity = map[string]string{
+	"FIXME": "high",
+	"HACK":  "medium",
+	"TODO":  "low",
+}
+
+// PythonSphinxParam represents a parameter documented with :param: and :type:
+// fields in a docstring
+type PythonSphinxParam struct {
+	Name        string
+	Type        string
+	Description string
+	LineNumber  int
+}
+
+// extractPythonDocstring returns the triple-quoted docstring that is the first
+// statement of the class or function declared at line index decl, with the
+// 1-based line number it starts on. Declarations spanning several lines are
+// supported.
+func extractPythonDocstring(lines []string, decl int) (string, int, bool) {
+	start := pythonHeaderEnd(lines, decl) + 1
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start >= len(lines) {
+		return "", 0, false
+	}
+
+	first := strings.TrimSpace(lines[start])
+	match := pythonDocstringStartRegex.FindStringSubmatch(first)
+	if match == nil {
+		return "", 0, false
+	}
+	quote := match[1]
+	rest := first[len(match[0]):]
+
+	// One-line docstring
+	if end := strings.Index(rest, quote); end >= 0 {
+		return strings.TrimSpace(rest[:end]), start + 1, true
+	}
+
+	docLines := []string{rest}
+	for i := start + 1; i < len(lines); i++ {
+		if end := strings.Index(lines[i], quote); end >= 0 {
+			docLines = append(docLines, lines[i][:end])
+			return dedentPythonDocstring(docLines), start + 1, true
+		}
+		docLines = append(docLines, lines[i])
+	}
+
+	// Unterminated docstring
+	return "", 0, false
+}
+
+// pythonHeaderEnd returns the index of the line ending with the colon of the
+// class or function declaration starting at line index decl
+func pythonHeaderEnd(lines []string, decl int) int {
+	header := decl
+	for header < len(lines)-1 && !strings.HasSuffix(stripPythonComment(lines[header]), ":") {
+		header++
+	}
+	return header
+}
+
+// dedentPythonDocstring removes the common indentation of the lines after the
+// first one, like inspect.cleandoc, and trims surrounding blank lines
+func dedentPythonDocstring(docLines []string) string {
+	indent := -1
+	for _, line := range docLines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	cleaned := []string{strings.TrimSpace(docLines[0])}
+	for _, line := range docLines[1:] {
+		if indent > 0 && len(line) >= indent {
+			line = line[indent:]
+		}
+		cleaned = append(cleaned, strings.TrimRight(line, " \t"))
+	}
+	return strings.Trim(strings.Join(cleaned, "\n"), "\n")
+}
+
+// truncatePythonDocstring shortens a docstring to maxPythonDocstringLength
+// characters
+func truncatePythonDocstring(docstring string) string {
+	runes := []rune(docstring)
+	if len(runes) <= maxPythonDocstringLength {
+		return docstring
+	}
+	return string(runes[:maxPythonDocstringLength])
+}
+
+// parsePythonSphinxParams returns the parameters documented in a docstring with
+// ":param name: description" fields, with their types from ":param type name:"
+// or ":type name: Type" fields. docLine is the line number of the first line of
+// the docstring.
+func parsePythonSphinxParams(docstring string, docLine int) []PythonSphinxParam {
+	var params []PythonSphinxParam
+	byName := make(map[string]int)
+	types := make(map[string]string)
+
+	for i, line := range strings.Split(docstring, "\n") {
+		line = strings.TrimSpace(line)
+		if match := pythonSphinxParamRegex.FindStringSubmatch(line); match != nil {
+			name := strings.TrimLeft(match[2], "*")
+			if _, seen := byName[name]; seen {
+				continue
+			}
+			byName[name] = len(params)
+			params = append(params, PythonSphinxParam{
+				Name:        name,
+				Type:        strings.TrimSpace(match[1]),
+				Description: strings.TrimSpace(match[3]),
+				LineNumber:  docLine + i,
+			})
+		} else if match := pythonSphinxTypeRegex.FindStringSubmatch(line); match != nil {
+			types[strings.TrimLeft(match[1], "*")] = strings.TrimSpace(match[2])
+		}
+	}
+
+	// :type fields may come before or after their :param field
+	for name, paramType := range types {
+		if index, ok := byName[name]; ok && params[index].Type == "" {
+			params[index].Type = paramType
+		}
+	}
+
+	return params
+}
+
+// enclosingPythonDeclaration returns the innermost class or function whose body
+// contains the line at index line, or that is declared on it, by walking up to
+// lines of lower indentation. blockOwners maps the first and last header line of
+// each declaration to it.
+func enclosingPythonDeclaration(lines []string, line int, blockOwners map[int]graph.Entity) (graph.Entity, bool) {
+	if declaration, ok := blockOwners[line]; ok {
+		return declaration, true
+	}
+	indent := pythonIndentation(lines[line])
+	for i := line - 1; i >= 0 && indent > 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lineIndent := pythonIndentation(lines[i])
+		if lineIndent >= indent {
+			continue
+		}
+		if declaration, ok := blockOwners[i]; ok {
+			return declaration, true
+		}
+		indent = lineIndent
+	}
+	return graph.Entity{}, false
+}
+
+var (
+	pythonClassVariableRegex = regexp.MustCompile(`^(\w+)\s*(?::\s*([^=]+?))?\s*(=[^=]|=$|$)`)
+	pythonSelfAttributeRegex = regexp.MustCompile(`^self\.(\w+)\s*(?::\s*([^=]+?))?\s*(?:=[^=]|=$)`)
+	pythonSelfTupleRegex     = regexp.MustCompile(`^((?:self\.\w+\s*,\s*)+self\.\w+)\s*,?\s*(?:=[^=]|=$)`)
+	pythonSelfNameRegex      = regexp.MustCompile(`self\.(\w+)`)
+	pythonDefNameRegex       = regexp.MustCompile(`^(?:async\s+)?def\s+(\w+)`)
+)
+
+// PythonAttribute is a class variable, assigned or annotated in the body of a
+// class, or an instance attribute, assigned to self in its __init__ method
+type PythonAttribute struct {
+	Name       string
+	Type       string
+	Scope      string
+	LineNumber int
+}
+
+// extractPythonAttributes returns the attributes of the class declared at line
+// index decl. Statements at the indentation of the class body, outside of its
+// methods, declare class variables (scope "class"); self.name assignments in
+// __init__ declare instance attributes (scope "instance"), each reported once.
+func extractPythonAttributes(lines []string, decl int) []PythonAttribute {
+	var attributes []PythonAttribute
+	classIndent := pythonIndentation(lines[decl])
+	bodyIndent := -1
+	method := ""
+	instance := make(map[string]bool)
+	// quote is the delimiter of the triple-quoted string the line is part of
+	quote := ""
+
+	for i := pythonHeaderEnd(lines, decl) + 1; i < len(lines); i++ {
+		if quote != "" {
+			quote = pythonOpenTripleQuote(lines[i], quote)
+			continue
+		}
+		line := stripPythonComment(lines[i])
+		if line == "" {
+			continue
+		}
+		indent := pythonIndentation(lines[i])
+		if indent <= classIndent {
+			break
+		}
+		if bodyIndent < 0 {
+			bodyIndent = indent
+		}
+		quote = pythonOpenTripleQuote(lines[i], "")
+
+		if indent == bodyIndent {
+			method = ""
+			if match := pythonDefNameRegex.FindStringSubmatch(line); match != nil {
+				method = match[1]
+				continue
+			}
+			match := pythonClassVariableRegex.FindStringSubmatch(line)
+			if match == nil || (match[2] == "" && match[3] == "") {
+				continue
+			}
+			attributes = append(attributes, PythonAttribute{
+				Name:       match[1],
+				Type:       match[2],
+				Scope:      "class",
+				LineNumber: i + 1,
+			})
+			continue
+		}
+
+		if method != "__init__" {
+			continue
+		}
+		var names []string
+		attributeType := ""
+		if match := pythonSelfAttributeRegex.FindStringSubmatch(line); match != nil {
+			names, attributeType = []string{match[1]}, match[2]
+		} else if match := pythonSelfTupleRegex.FindStringSubmatch(line); match != nil {
+			for _, name := range pythonSelfNameRegex.FindAllStringSubmatch(match[1], -1) {
+				names = append(names, name[1])
+			}
+		}
+		for _, name := range names {
+			if instance[name] {
+				continue
+			}
+			instance[name] = true
+			attributes = append(attributes, PythonAttribute{
+				Name:       name,
+				Type:       attributeType,
+				Scope:      "instance",
+				LineNumber: i + 1,
+			})
+		}
+	}
+	return attributes
+}
+
+// pythonOpenTripleQuote returns the delimiter of the triple-quoted string left
+// open at the end of a line, given the one open at its start, or "" if none is
+func pythonOpenTripleQuote(line, quote string) string {
+	for {
+		if quote != "" {
+			end := strings.Index(line, quote)
+			if end < 0 {
+				return quote
+			}
+			line, quote = line[end+3:], ""
+			continue
+		}
+		double, single := strings.Index(line, `"""`), strings.Index(line, "'''")
+		switch {
+		case double < 0 && single < 0:
+			return ""
+		case single < 0 || (double >= 0 && double < single):
+			line, quote = line[double+3:], `"""`
+		default:
+			line, quote = line[single+3:], "'''"
+		}
+	}
+}
+
+// pythonIndentation returns the number of leading whitespace characters of a line
+func pythonIndentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// stripPythonComment removes a trailing # comment and whitespace from a line
+func stripPythonComment(line string) string {
+	if index := strings.Index(line, "#"); index >= 0 {
+		line = line[:index]
+	}
+	return strings.TrimSpace(line)
+}
+
+// PythonReExport is a name an __init__.py makes available from its package,
+// with the module relative to the package it is imported from, e.g. sub.module
+// for "from .sub.module import X". Module is empty for names imported with
+// "from . import x" and listed in __all__.
+type PythonReExport struct {
+	Name   string
+	Module string
+}
+
+var (
+	// pythonRelativeImportRegex matches "from .module import a, b" and
+	// "from .module import (a, b)" with the names spread over several lines
+	pythonRelativeImportRegex = regexp.MustCompile(`(?m)^[ \t]*from[ \t]+\.(\w+(?:\.\w+)*)?[ \t]+import[ \t]+(?:\(([^)]*)\)|([^\n]+))`)
+	pythonCommentRegex        = regexp.MustCompile(`#[^\n]*`)
+)
+
+// extractPythonReExports returns the names an __init__.py makes available from its
+// package: names imported with "from . import x" or "from .module import X",
+// followed by the names listed in __all__
+func extractPythonReExports(content string) []PythonReExport {
+	var reExports []PythonReExport
+	seen := make(map[string]bool)
+	add := func(name, module string) {
+		if name != "" && name != "*" && !seen[name] {
+			seen[name] = true
+			reExports = append(reExports, PythonReExport{Name: name, Module: module})
+		}
+	}
+
+	for _, match := range pythonRelativeImportRegex.FindAllStringSubmatch(content, -1) {
+		imported := match[2] + match[3]
+		imported = pythonCommentRegex.ReplaceAllString(imported, "")
+		for _, item := range strings.Split(imported, ",") {
+			// "X as Y" re-exports the original entity X
+			fields := strings.Fields(item)
+			if len(fields) > 0 {
+				add(fields[0], match[1])
+			}
+		}
+	}
+
+	allNames, _ := extractPythonAll(content)
+	for _, name := range allNames {
+		add(name, "")
+	}
+
+	return reExports
+}
+
+// extractPythonAll returns the names listed in a module's __all__ declaration(s)
+// and whether __all__ is defined at all. Both list and tuple literals spanning
+// multiple lines are supported, as are "__all__ += [...]" extensions.
+func extractPythonAll(content string) ([]string, bool) {
+	allRegex := regexp.MustCompile(`(?m)^__all__\s*\+?=\s*[\[(]([^\])]*)[\])]`)
+	nameRegex := regexp.MustCompile(`['"](\w+)['"]`)
+
+	matches := allRegex.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	var names []string
+	for _, match := range matches {
+		for _, nameMatch := range nameRegex.FindAllStringSubmatch(match[1], -1) {
+			names = append(names, nameMatch[1])
+		}
+	}
+
+	return names, true
+}
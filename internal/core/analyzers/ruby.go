@@ -0,0 +1,283 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"regexp"
+	"strings"
+)
+
+// RubyAnalyzer implements the LanguageAnalyzer interface for Ruby
+type RubyAnalyzer struct{}
+
+func (ra *RubyAnalyzer) Name() string                 { return "Ruby Analyzer" }
+func (ra *RubyAnalyzer) SupportedLanguages() []string { return []string{"ruby"} }
+func (ra *RubyAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	return analyzeRubyFile(file, fileEntity)
+}
+
+var (
+	rubyRequireRegex    = regexp.MustCompile(`^(require|require_relative)\s*\(?\s*['"]([^'"]+)['"]`)
+	rubyClassRegex      = regexp.MustCompile(`^class\s+([A-Z][\w:]*)(?:\s*<\s*([A-Z][\w:]*))?`)
+	rubySingletonRegex  = regexp.MustCompile(`^class\s*<<\s*self\b`)
+	rubyModuleRegex     = regexp.MustCompile(`^module\s+([A-Z][\w:]*)`)
+	rubyDefRegex        = regexp.MustCompile(`^(?:(public|private|protected|private_class_method|public_class_method)\s+)?def\s+(self\.)?([\w]+[?!=]?|\[\]=?|[+\-*/%<>=!~^&|]+)`)
+	rubyVisibilityRegex = regexp.MustCompile(`^(public|private|protected|private_class_method|public_class_method)\b\s*(.*)$`)
+	rubyAttrRegex       = regexp.MustCompile(`^attr_(reader|writer|accessor)\s*\(?\s*(.+?)\)?$`)
+	rubySymbolRegex     = regexp.MustCompile(`:(\w+[?!]?)|['"](\w+[?!]?)['"]`)
+	rubyBlockStartRegex = regexp.MustCompile(`^(?:if|unless|while|until|case|begin|for)\b|(?:=|\|\||&&)\s*(?:if|unless|case|begin)\b|\bdo\s*(?:\|[^|]*\|)?$`)
+	rubyEndRegex        = regexp.MustCompile(`^end\b`)
+	rubyEndWordRegex    = regexp.MustCompile(`\bend\b`)
+	rubyEndlessDefRegex = regexp.MustCompile(`^\s*(?:\([^)]*\))?\s*=[^=~]`)
+)
+
+// rubyScope is a class, module, method or block of a Ruby file being analyzed
+type rubyScope struct {
+	kind string
+	// owner is the class or module entity whose methods are defined in the
+	// scope; the singleton class (class << self) shares its class's entity
+	owner      *graph.Entity
+	singleton  bool
+	visibility string
+}
+
+// analyzeRubyFile analyzes a Ruby source file. Classes are CLASS entities and
+// modules MODULE entities, both containing their methods and the attributes
+// defined by attr_reader, attr_writer and attr_accessor. The visibility of
+// each method follows Ruby's rules: it is public unless a bare private,
+// protected or public before it in the class body changes the default, or
+// private :name, private def name or private_class_method :name sets it.
+func analyzeRubyFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	var stack []rubyScope
+	// Methods by owner ID and name, referred to by index in entities
+	methodIndexes := make(map[string][]int)
+
+	currentOwner := func() (*rubyScope, bool) {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].kind == "class" || stack[i].kind == "module" {
+				return &stack[i], true
+			}
+			if stack[i].kind == "def" {
+				return nil, false
+			}
+		}
+		return nil, false
+	}
+	contain := func(owner *rubyScope, entity graph.Entity) {
+		entities = append(entities, entity)
+		source := fileEntity.ID
+		if owner != nil {
+			source = owner.owner.ID
+		}
+		relType := graph.RelationshipTypeContains
+		if owner == nil {
+			relType = graph.RelationshipTypeDefines
+		}
+		relationships = append(relationships, graph.CreateRelationship(source, entity.ID, relType, nil))
+	}
+	// setVisibility changes the visibility of the named methods of the owner
+	setVisibility := func(owner *rubyScope, names []string, visibility string, classMethods bool) {
+		for _, name := range names {
+			for _, index := range methodIndexes[owner.owner.ID+"|"+name] {
+				method := entities[index]
+				if isClassMethod, _ := method.Properties["isClassMethod"].(bool); isClassMethod == classMethods {
+					method.Properties["visibility"] = visibility
+				}
+			}
+		}
+	}
+
+	for i, rawLine := range strings.Split(file.Content, "\n") {
+		line := strings.TrimSpace(stripRubyComment(rawLine))
+		if line == "" {
+			continue
+		}
+
+		if match := rubyRequireRegex.FindStringSubmatch(line); match != nil {
+			source := match[2]
+			importEntity := graph.CreateEntity(source[strings.LastIndex(source, "/")+1:], graph.EntityTypeImport, graph.Properties{
+				"source":     source,
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "ruby",
+				"isRelative": match[1] == "require_relative",
+			})
+			entities = append(entities, importEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+					"importedAt": i + 1,
+				}))
+			continue
+		}
+
+		if rubySingletonRegex.MatchString(line) {
+			scope := rubyScope{kind: "block"}
+			if owner, ok := currentOwner(); ok {
+				scope = rubyScope{kind: "class", owner: owner.owner, singleton: true, visibility: "public"}
+			}
+			stack = append(stack, scope)
+			continue
+		}
+
+		if match := rubyClassRegex.FindStringSubmatch(line); match != nil {
+			properties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "ruby",
+			}
+			if match[2] != "" {
+				properties["extends"] = match[2]
+			}
+			owner, _ := currentOwner()
+			classEntity := graph.CreateEntity(match[1], graph.EntityTypeClass, properties)
+			contain(owner, classEntity)
+			if !strings.HasSuffix(line, "end") {
+				stack = append(stack, rubyScope{kind: "class", owner: &classEntity, visibility: "public"})
+			}
+			continue
+		}
+
+		if match := rubyModuleRegex.FindStringSubmatch(line); match != nil {
+			owner, _ := currentOwner()
+			moduleEntity := graph.CreateEntity(match[1], graph.EntityTypeModule, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "ruby",
+			})
+			contain(owner, moduleEntity)
+			if !strings.HasSuffix(line, "end") {
+				stack = append(stack, rubyScope{kind: "module", owner: &moduleEntity, visibility: "public"})
+			}
+			continue
+		}
+
+		if match := rubyDefRegex.FindStringSubmatch(line); match != nil {
+			modifier, name := match[1], match[3]
+			owner, inOwner := currentOwner()
+			isClassMethod := match[2] != "" || inOwner && owner.singleton
+
+			entityType := graph.EntityTypeFunction
+			visibility := "public"
+			if inOwner {
+				entityType = graph.EntityTypeMethod
+				if !isClassMethod || owner.singleton {
+					visibility = owner.visibility
+				}
+			}
+			switch modifier {
+			case "public", "private", "protected":
+				visibility = modifier
+			case "private_class_method":
+				visibility = "private"
+			case "public_class_method":
+				visibility = "public"
+			}
+			// initialize is always private
+			if name == "initialize" && !isClassMethod {
+				visibility = "private"
+			}
+
+			properties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "ruby",
+				"visibility": visibility,
+			}
+			if inOwner {
+				properties["isClassMethod"] = isClassMethod
+				properties["owner"] = owner.owner.Label
+			} else {
+				owner = nil
+			}
+			methodEntity := graph.CreateEntity(name, entityType, properties)
+			contain(owner, methodEntity)
+			if owner != nil {
+				key := owner.owner.ID + "|" + name
+				methodIndexes[key] = append(methodIndexes[key], len(entities)-1)
+			}
+
+			// Endless (def x = ...) and one-line (def x; end) methods have no body to close
+			rest := line[len(match[0]):]
+			if !rubyEndlessDefRegex.MatchString(rest) && !rubyEndWordRegex.MatchString(rest) {
+				stack = append(stack, rubyScope{kind: "def"})
+			}
+			continue
+		}
+
+		if match := rubyVisibilityRegex.FindStringSubmatch(line); match != nil {
+			owner, ok := currentOwner()
+			if !ok {
+				continue
+			}
+			keyword, arguments := match[1], match[2]
+			var names []string
+			for _, symbol := range rubySymbolRegex.FindAllStringSubmatch(arguments, -1) {
+				names = append(names, symbol[1]+symbol[2])
+			}
+			switch {
+			case keyword == "private_class_method" || keyword == "public_class_method":
+				visibility := strings.TrimSuffix(keyword, "_class_method")
+				setVisibility(owner, names, visibility, true)
+			case arguments == "":
+				owner.visibility = keyword
+			default:
+				setVisibility(owner, names, keyword, owner.singleton)
+			}
+			continue
+		}
+
+		if match := rubyAttrRegex.FindStringSubmatch(line); match != nil {
+			owner, ok := currentOwner()
+			if !ok {
+				continue
+			}
+			for _, symbol := range rubySymbolRegex.FindAllStringSubmatch(match[2], -1) {
+				contain(owner, graph.CreateEntity(symbol[1]+symbol[2], graph.EntityTypeProperty, graph.Properties{
+					"sourceFile": file.Path,
+					"lineNumber": i + 1,
+					"language":   "ruby",
+					"accessor":   match[1],
+					"visibility": owner.visibility,
+					"owner":      owner.owner.Label,
+				}))
+			}
+			continue
+		}
+
+		if rubyEndRegex.MatchString(line) {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		if rubyBlockStartRegex.MatchString(line) && !rubyEndWordRegex.MatchString(line) {
+			stack = append(stack, rubyScope{kind: "block"})
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// stripRubyComment removes a trailing # comment from a line, keeping the #{}
+// interpolations of strings
+func stripRubyComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
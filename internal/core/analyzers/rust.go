@@ -0,0 +1,325 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"regexp"
+	"strings"
+)
+
+// RustAnalyzer implements the LanguageAnalyzer interface for Rust
+type RustAnalyzer struct{}
+
+func (ra *RustAnalyzer) Name() string                 { return "Rust Analyzer" }
+func (ra *RustAnalyzer) SupportedLanguages() []string { return []string{"rust"} }
+func (ra *RustAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	return analyzeRustFile(file, fileEntity)
+}
+
+// rustVisibility matches pub, pub(crate), pub(super) and pub(in path)
+const rustVisibility = `(?:(pub)(?:\([^)]*\))?\s+)?`
+
+var (
+	rustUseRegex   = regexp.MustCompile(`^` + rustVisibility + `use\s+([^;]+);`)
+	rustItemRegex  = regexp.MustCompile(`^` + rustVisibility + `(?:unsafe\s+)?(struct|enum|union|trait)\s+(\w+)`)
+	rustFnRegex    = regexp.MustCompile(`^` + rustVisibility + `(?:default\s+)?((?:(?:const|async|unsafe)\s+|extern\s+(?:"[^"]*"\s+)?)*)fn\s+(\w+)`)
+	rustImplRegex  = regexp.MustCompile(`^(?:unsafe\s+)?impl\b(.*)`)
+	rustWhereRegex = regexp.MustCompile(`\bwhere\b`)
+)
+
+// RustImpl is an impl block: an inherent impl of Type, or an impl of Trait for Type
+type RustImpl struct {
+	Trait string
+	Type  string
+}
+
+// analyzeRustFile analyzes a Rust source file. Structs and unions are CLASS
+// entities, enums ENUM entities and traits INTERFACE entities containing their
+// methods. The functions of impl blocks are METHOD entities recording their
+// implType, and the file records its trait impls as traitImpls ("Trait for
+// Type"); both are linked to the types and traits of the codebase by
+// analysis.LinkRustImplementations once all files are analyzed.
+func analyzeRustFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	lines := strings.Split(file.Content, "\n")
+	var traitImpls []string
+
+	define := func(entity graph.Entity) {
+		entities = append(entities, entity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, entity.ID, graph.RelationshipTypeDefines, nil))
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(stripRustLineComment(lines[i]))
+
+		if match := rustUseRegex.FindStringSubmatch(line); match != nil {
+			path := strings.Join(strings.Fields(match[2]), " ")
+			// Grouped and glob imports are named by the module they import from
+			name := strings.TrimSuffix(strings.SplitN(path, "::{", 2)[0], "::*")
+			if alias := strings.LastIndex(name, " as "); alias != -1 {
+				name = name[alias+4:]
+			}
+			name = name[strings.LastIndex(name, "::")+1:]
+			name = strings.TrimPrefix(name, ":")
+			importEntity := graph.CreateEntity(name, graph.EntityTypeImport, graph.Properties{
+				"source":     path,
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "rust",
+			})
+			entities = append(entities, importEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+					"importedAt": i + 1,
+				}))
+			continue
+		}
+
+		if match := rustItemRegex.FindStringSubmatch(line); match != nil {
+			kind, name := match[2], match[3]
+			end := findRustBlockEnd(lines, i)
+			properties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "rust",
+				"kind":       kind,
+				"isPublic":   match[1] != "",
+			}
+
+			switch kind {
+			case "struct", "union":
+				define(graph.CreateEntity(name, graph.EntityTypeClass, properties))
+			case "enum":
+				define(graph.CreateEntity(name, graph.EntityTypeEnum, properties))
+			case "trait":
+				traitEntity := graph.CreateEntity(name, graph.EntityTypeInterface, properties)
+				define(traitEntity)
+				for _, method := range extractRustFunctions(file, lines, i, end) {
+					method.Properties["trait"] = name
+					entities = append(entities, method)
+					relationships = append(relationships, graph.CreateRelationship(
+						traitEntity.ID, method.ID, graph.RelationshipTypeContains, nil))
+				}
+			}
+			i = end - 1
+			continue
+		}
+
+		if match := rustImplRegex.FindStringSubmatch(line); match != nil {
+			end := findRustBlockEnd(lines, i)
+			impl, ok := parseRustImpl(strings.Join(lines[i:end], " "))
+			if !ok {
+				continue
+			}
+			if impl.Trait != "" {
+				traitImpls = append(traitImpls, impl.Trait+" for "+impl.Type)
+			}
+			for _, method := range extractRustFunctions(file, lines, i, end) {
+				method.Properties["implType"] = impl.Type
+				if impl.Trait != "" {
+					method.Properties["trait"] = impl.Trait
+				}
+				define(method)
+			}
+			i = end - 1
+			continue
+		}
+
+		if match := rustFnRegex.FindStringSubmatch(line); match != nil {
+			end := findRustBlockEnd(lines, i)
+			define(graph.CreateEntity(match[3], graph.EntityTypeFunction, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "rust",
+				"isPublic":   match[1] != "",
+				"isAsync":    strings.Contains(match[2], "async"),
+			}))
+			i = end - 1
+		}
+	}
+
+	if len(traitImpls) > 0 {
+		fileEntity.Properties["traitImpls"] = traitImpls
+	}
+
+	return entities, relationships, nil
+}
+
+// extractRustFunctions returns the functions declared directly in the body of
+// the trait or impl block declared on line start, which ends before line end, as
+// METHOD entities. A block that opens and closes on its header line, such as
+// impl Default for A { fn default() -> Self { A } }, has its function read from
+// after the brace. Trait methods without a body are flagged with isAbstract.
+func extractRustFunctions(file graph.CodeFile, lines []string, start, end int) []graph.Entity {
+	var methods []graph.Entity
+	for i := start; i < end; i++ {
+		line := strings.TrimSpace(stripRustLineComment(lines[i]))
+		if i == start {
+			brace := strings.Index(line, "{")
+			if end != start+1 || brace == -1 {
+				continue
+			}
+			line = strings.TrimSpace(line[brace+1:])
+		}
+		match := rustFnRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		fnEnd := findRustBlockEnd(lines, i)
+		body := line
+		if fnEnd > i+1 {
+			body = strings.Join(lines[i:fnEnd], "\n")
+		}
+		methods = append(methods, graph.CreateEntity(match[3], graph.EntityTypeMethod, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": i + 1,
+			"language":   "rust",
+			"isPublic":   match[1] != "",
+			"isAsync":    strings.Contains(match[2], "async"),
+			"isAbstract": !strings.Contains(body, "{"),
+		}))
+		i = fnEnd - 1
+	}
+	return methods
+}
+
+// parseRustImpl parses the header of an impl block, e.g.
+// "impl<T: Debug> fmt::Display for Wrapper<T> where T: Clone {". Generic
+// arguments are dropped from the type and trait names; the trait keeps its path.
+func parseRustImpl(header string) (RustImpl, bool) {
+	match := rustImplRegex.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return RustImpl{}, false
+	}
+	rest := strings.TrimSpace(match[1])
+	if strings.HasPrefix(rest, "<") {
+		rest = strings.TrimSpace(rest[matchingAngleBracket(rest)+1:])
+	}
+	if brace := strings.Index(rest, "{"); brace != -1 {
+		rest = rest[:brace]
+	}
+	if where := rustWhereRegex.FindStringIndex(rest); where != nil {
+		rest = rest[:where[0]]
+	}
+
+	var impl RustImpl
+	if trait, implType, ok := strings.Cut(rest, " for "); ok {
+		impl.Trait = rustTypeName(strings.TrimPrefix(strings.TrimSpace(trait), "!"), true)
+		impl.Type = rustTypeName(implType, false)
+	} else {
+		impl.Type = rustTypeName(rest, false)
+	}
+	return impl, impl.Type != ""
+}
+
+// rustTypeName strips the generic arguments, references and, unless keepPath
+// is set, the module path from a type
+func rustTypeName(name string, keepPath bool) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimLeft(name, "&")
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(name, "mut "), "dyn "))
+	if angle := strings.Index(name, "<"); angle != -1 {
+		name = name[:angle]
+	}
+	name = strings.TrimSpace(name)
+	if !keepPath {
+		name = name[strings.LastIndex(name, "::")+1:]
+		name = strings.TrimPrefix(name, ":")
+	}
+	return name
+}
+
+// matchingAngleBracket returns the index of the > closing the < at the start of s
+func matchingAngleBracket(s string) int {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			// Skip the > of -> in Fn(T) -> U bounds
+			if i > 0 && s[i-1] == '-' {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
+
+// findRustBlockEnd returns the index of the line after the item starting on
+// line start: after the closing brace of its body, or after the ; ending an
+// item without one, such as a unit struct or a trait method signature
+func findRustBlockEnd(lines []string, start int) int {
+	depth := 0
+	opened := false
+	for i := start; i < len(lines); i++ {
+		opens, closes := countRustBraces(lines[i])
+		if opens > 0 {
+			opened = true
+		}
+		depth += opens - closes
+		if opened && depth <= 0 {
+			return i + 1
+		}
+		if !opened && strings.HasSuffix(strings.TrimSpace(stripRustLineComment(lines[i])), ";") {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// countRustBraces counts the braces of a line outside strings, character
+// literals and comments. A quote starts a character literal only when it is
+// closed one character later, e.g. '{' or '\n'; otherwise it is a lifetime.
+func countRustBraces(line string) (int, int) {
+	opens, closes := 0, 0
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		case c == '\'':
+			if i+2 < len(line) && line[i+1] == '\\' {
+				if end := strings.IndexByte(line[i+2:], '\''); end != -1 {
+					i += end + 2
+				}
+			} else if i+2 < len(line) && line[i+2] == '\'' {
+				i += 2
+			}
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return opens, closes
+		case c == '{':
+			opens++
+		case c == '}':
+			closes++
+		}
+	}
+	return opens, closes
+}
+
+// stripRustLineComment removes a trailing // comment from a line
+func stripRustLineComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,260 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"regexp"
+	"strings"
+)
+
+// ScalaAnalyzer implements the LanguageAnalyzer interface for Scala
+type ScalaAnalyzer struct{}
+
+func (sa *ScalaAnalyzer) Name() string                 { return "Scala Analyzer" }
+func (sa *ScalaAnalyzer) SupportedLanguages() []string { return []string{"scala"} }
+func (sa *ScalaAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	return analyzeScalaFile(file, fileEntity)
+}
+
+var (
+	scalaPackageRegex     = regexp.MustCompile(`^package\s+([\w.]+)`)
+	scalaImportRegex      = regexp.MustCompile(`^import\s+(\w+(?:\.\w+)*(?:\.\{[^}]*\}|\._)?)`)
+	scalaDeclarationRegex = regexp.MustCompile(`^(?:@\w+(?:\([^)]*\))?\s+)*((?:(?:private|protected)(?:\[\w+\])?\s+|(?:sealed|abstract|final|case|implicit)\s+)*)(class|trait|object)\s+(\w+)`)
+	scalaExtendsRegex     = regexp.MustCompile(`^\s*extends\s+([\w.]+)`)
+	scalaWithRegex        = regexp.MustCompile(`\bwith\s+([\w.]+)`)
+	scalaDefRegex         = regexp.MustCompile(`^(?:(?:override|private|protected|final|implicit)(?:\[\w+\])?\s+)*def\s+(\w+)\s*(?:\[[^\]]*\])?\s*(\([^)]*\))?\s*(?::\s*([^={]+))?`)
+)
+
+// scalaDeclaration is a class, trait or object declared in a Scala file
+type scalaDeclaration struct {
+	Kind       string
+	Name       string
+	Modifiers  string
+	Extends    string
+	Mixins     []string
+	LineNumber int
+}
+
+// analyzeScalaFile analyzes a Scala source file. Traits are INTERFACE entities
+// with their abstract and concrete methods, classes and objects are CLASS
+// entities. The parents of a declaration are stored as its extends and mixins
+// properties and linked by analysis.LinkScalaSupertypes once all files are
+// analyzed.
+func analyzeScalaFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	lines := strings.Split(file.Content, "\n")
+
+	var declarations []scalaDeclaration
+	for i, line := range lines {
+		line = strings.TrimSpace(stripLineComment(line))
+
+		if match := scalaPackageRegex.FindStringSubmatch(line); match != nil {
+			packageEntity := graph.CreateEntity(match[1], graph.EntityTypePackage, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "scala",
+			})
+			entities = append(entities, packageEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, packageEntity.ID, graph.RelationshipTypeDefines, nil))
+			continue
+		}
+
+		if match := scalaImportRegex.FindStringSubmatch(line); match != nil {
+			importPath := match[1]
+			// Wildcard and selector imports are named by the imported package
+			importName := strings.TrimSuffix(strings.SplitN(importPath, ".{", 2)[0], "._")
+			importName = importName[strings.LastIndex(importName, ".")+1:]
+			importEntity := graph.CreateEntity(importName, graph.EntityTypeImport, graph.Properties{
+				"source":     importPath,
+				"sourceFile": file.Path,
+				"lineNumber": i + 1,
+				"language":   "scala",
+			})
+			entities = append(entities, importEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+					"importedAt": i + 1,
+				}))
+			continue
+		}
+
+		if declaration, ok := parseScalaDeclaration(lines, i); ok {
+			declarations = append(declarations, declaration)
+		}
+	}
+
+	// Classes and traits by name, to recognize companion objects
+	companions := make(map[string]bool)
+	for _, declaration := range declarations {
+		if declaration.Kind != "object" {
+			companions[declaration.Name] = true
+		}
+	}
+
+	for _, declaration := range declarations {
+		entityType := graph.EntityTypeClass
+		if declaration.Kind == "trait" {
+			entityType = graph.EntityTypeInterface
+		}
+
+		properties := graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": declaration.LineNumber,
+			"language":   "scala",
+			"kind":       declaration.Kind,
+			"isCase":     strings.Contains(declaration.Modifiers, "case"),
+			"isSealed":   strings.Contains(declaration.Modifiers, "sealed"),
+			"isAbstract": strings.Contains(declaration.Modifiers, "abstract"),
+		}
+		if declaration.Extends != "" {
+			properties["extends"] = declaration.Extends
+		}
+		if len(declaration.Mixins) > 0 {
+			properties["mixins"] = declaration.Mixins
+		}
+		if declaration.Kind == "object" && companions[declaration.Name] {
+			properties["companionClass"] = declaration.Name
+		}
+
+		declarationEntity := graph.CreateEntity(declaration.Name, entityType, properties)
+		entities = append(entities, declarationEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, declarationEntity.ID, graph.RelationshipTypeDefines, nil))
+
+		if declaration.Kind != "trait" {
+			continue
+		}
+		for _, method := range parseScalaTraitMethods(lines, declaration.LineNumber-1) {
+			methodProperties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": method.LineNumber,
+				"language":   "scala",
+				"trait":      declaration.Name,
+				"parameters": method.Parameters,
+				"returnType": method.ReturnType,
+				"isAbstract": method.IsAbstract,
+			}
+			methodEntity := graph.CreateEntity(method.Name, graph.EntityTypeMethod, methodProperties)
+			entities = append(entities, methodEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				declarationEntity.ID, methodEntity.ID, graph.RelationshipTypeContains, nil))
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// parseScalaDeclaration parses the class, trait or object declared on line
+// start. The parents may follow on continuation lines starting with extends or
+// with; type arguments and constructor arguments are skipped.
+func parseScalaDeclaration(lines []string, start int) (scalaDeclaration, bool) {
+	line := strings.TrimSpace(stripLineComment(lines[start]))
+	match := scalaDeclarationRegex.FindStringSubmatchIndex(line)
+	if match == nil {
+		return scalaDeclaration{}, false
+	}
+
+	declaration := scalaDeclaration{
+		Modifiers:  line[match[2]:match[3]],
+		Kind:       line[match[4]:match[5]],
+		Name:       line[match[6]:match[7]],
+		LineNumber: start + 1,
+	}
+
+	header := line[match[1]:]
+	for i := start + 1; i < len(lines) && !strings.Contains(header, "{"); i++ {
+		next := strings.TrimSpace(stripLineComment(lines[i]))
+		if !strings.HasPrefix(next, "extends ") && !strings.HasPrefix(next, "with ") {
+			break
+		}
+		header += " " + next
+	}
+	header = stripScalaGroups(header)
+	if idx := strings.Index(header, "{"); idx >= 0 {
+		header = header[:idx]
+	}
+
+	if match := scalaExtendsRegex.FindStringSubmatch(header); match != nil {
+		declaration.Extends = match[1]
+	}
+	for _, match := range scalaWithRegex.FindAllStringSubmatch(header, -1) {
+		declaration.Mixins = append(declaration.Mixins, match[1])
+	}
+
+	return declaration, true
+}
+
+// stripScalaGroups removes the type parameters, constructor parameters and
+// arguments in brackets and parentheses from a declaration header, e.g.
+// "[T](name: String) extends Base(name) with Ordered[T]" becomes
+// " extends Base with Ordered"
+func stripScalaGroups(header string) string {
+	var builder strings.Builder
+	depth := 0
+	for i := 0; i < len(header); i++ {
+		switch c := header[i]; {
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+// scalaMethod is a method declared directly in the body of a trait
+type scalaMethod struct {
+	Name       string
+	Parameters string
+	ReturnType string
+	IsAbstract bool
+	LineNumber int
+}
+
+// parseScalaTraitMethods returns the methods declared in the body of the trait
+// declared on line start. Methods without a body are abstract; methods of
+// nested declarations are skipped.
+func parseScalaTraitMethods(lines []string, start int) []scalaMethod {
+	var methods []scalaMethod
+	depth := 0
+	opened := false
+
+	for i := start; i < len(lines); i++ {
+		if opened && depth == 1 {
+			line := strings.TrimSpace(stripLineComment(lines[i]))
+			if match := scalaDefRegex.FindStringSubmatch(line); match != nil {
+				methods = append(methods, scalaMethod{
+					Name:       match[1],
+					Parameters: strings.TrimSpace(strings.Trim(match[2], "()")),
+					ReturnType: strings.TrimSpace(match[3]),
+					IsAbstract: !strings.Contains(line, "=") && !strings.Contains(line, "{"),
+					LineNumber: i + 1,
+				})
+			}
+		}
+
+		opens, closes := countBraces(lines[i])
+		if opens > 0 {
+			opened = true
+		}
+		depth += opens - closes
+		if !opened {
+			// A trait without a body
+			next := strings.TrimSpace(lines[i])
+			if i > start && !strings.HasPrefix(next, "extends ") && !strings.HasPrefix(next, "with ") {
+				return methods
+			}
+			continue
+		}
+		if depth <= 0 {
+			return methods
+		}
+	}
+	return methods
+}
@@ -0,0 +1,400 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"regexp"
+	"strings"
+)
+
+// SQLStatement is a single statement of a SQL file
+type SQLStatement struct {
+	Text       string
+	LineNumber int
+}
+
+// SQLAnalyzer implements the LanguageAnalyzer interface for SQL DDL.
+// It understands MySQL and PostgreSQL flavours of CREATE TABLE, CREATE INDEX,
+// CREATE VIEW, CREATE PROCEDURE/FUNCTION and foreign key constraints.
+type SQLAnalyzer struct{}
+
+func (sa *SQLAnalyzer) Name() string                 { return "SQL Analyzer" }
+func (sa *SQLAnalyzer) SupportedLanguages() []string { return []string{"sql"} }
+func (sa *SQLAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	return analyzeSQLFile(file, fileEntity)
+}
+
+// SQL identifiers may be quoted with double quotes, backticks or brackets and
+// qualified with a schema
+const sqlIdentifier = "((?:[`\"\\[]?[\\w$]+[`\"\\]]?\\.)?[`\"\\[]?[\\w$]+[`\"\\]]?)"
+
+var (
+	sqlCreateTableRegex     = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + sqlIdentifier)
+	sqlAlterTableRegex      = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + sqlIdentifier)
+	sqlCreateIndexRegex     = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+|FULLTEXT\s+|SPATIAL\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?` + sqlIdentifier + `\s+ON\s+(?:ONLY\s+)?` + sqlIdentifier)
+	sqlCreateViewRegex      = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:ALGORITHM\s*=\s*\w+\s+)?(?:DEFINER\s*=\s*\S+\s+)?(?:SQL\s+SECURITY\s+\w+\s+)?(?:TEMP(?:ORARY)?\s+)?(MATERIALIZED\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?` + sqlIdentifier)
+	sqlCreateProcedureRegex = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:DEFINER\s*=\s*\S+\s+)?(PROCEDURE|FUNCTION)\s+(?:IF\s+NOT\s+EXISTS\s+)?` + sqlIdentifier)
+	sqlForeignKeyRegex      = regexp.MustCompile(`(?is)(?:FOREIGN\s+KEY\s*\(([^)]*)\)\s*)?REFERENCES\s+` + sqlIdentifier + `\s*(?:\(([^)]*)\))?`)
+	sqlTableSourceRegex     = regexp.MustCompile(`(?is)\b(?:FROM|JOIN)\s+` + sqlIdentifier)
+	sqlRoutineRegex         = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?(?:DEFINER\s*=\s*\S+\s+)?(?:PROCEDURE|FUNCTION|TRIGGER)\b`)
+	sqlDollarQuoteRegex     = regexp.MustCompile(`^\$(?:[A-Za-z_]\w*)?\$`)
+	sqlTrailingEndRegex     = regexp.MustCompile(`(?i)\bEND\s+$`)
+)
+
+// analyzeSQLFile analyzes a SQL file for schema objects
+func analyzeSQLFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	statements := splitSQLStatements(file.Content)
+
+	// Tables are created first so that later statements can reference them
+	tableIDs := make(map[string]string)
+	for _, stmt := range statements {
+		match := sqlCreateTableRegex.FindStringSubmatch(stmt.Text)
+		if match == nil {
+			continue
+		}
+
+		name := normalizeSQLIdentifier(match[1])
+		tableEntity := graph.CreateEntity(name, graph.EntityTypeDatabaseTable, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": stmt.LineNumber,
+			"columns":    extractSQLColumns(stmt.Text),
+			"language":   "sql",
+		})
+		entities = append(entities, tableEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, tableEntity.ID, graph.RelationshipTypeDefines, nil))
+		tableIDs[strings.ToLower(name)] = tableEntity.ID
+		if idx := strings.LastIndex(name, "."); idx != -1 {
+			// Also allow unqualified references to schema-qualified tables
+			tableIDs[strings.ToLower(name[idx+1:])] = tableEntity.ID
+		}
+	}
+
+	for _, stmt := range statements {
+		if match := sqlCreateTableRegex.FindStringSubmatch(stmt.Text); match != nil {
+			tableID, _ := lookupSQLTable(tableIDs, match[1])
+			relationships = append(relationships, sqlForeignKeyRelationships(stmt, tableID, tableIDs)...)
+			continue
+		}
+
+		if match := sqlAlterTableRegex.FindStringSubmatch(stmt.Text); match != nil {
+			if tableID, ok := lookupSQLTable(tableIDs, match[1]); ok {
+				relationships = append(relationships, sqlForeignKeyRelationships(stmt, tableID, tableIDs)...)
+			}
+			continue
+		}
+
+		if match := sqlCreateIndexRegex.FindStringSubmatch(stmt.Text); match != nil {
+			table := normalizeSQLIdentifier(match[2])
+			indexEntity := graph.CreateEntity(normalizeSQLIdentifier(match[1]), graph.EntityTypeConfiguration, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": stmt.LineNumber,
+				"table":      table,
+				"isUnique":   strings.Contains(strings.ToUpper(stmt.Text[:len(match[0])]), "UNIQUE"),
+				"kind":       "index",
+				"language":   "sql",
+			})
+			entities = append(entities, indexEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, indexEntity.ID, graph.RelationshipTypeDefines, nil))
+			if tableID, ok := lookupSQLTable(tableIDs, table); ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					indexEntity.ID, tableID, graph.RelationshipTypeConfigures, nil))
+			}
+			continue
+		}
+
+		if match := sqlCreateViewRegex.FindStringSubmatch(stmt.Text); match != nil {
+			referenced := extractSQLTableSources(stmt.Text[len(match[0]):])
+			viewEntity := graph.CreateEntity(normalizeSQLIdentifier(match[2]), graph.EntityTypeDatabaseTable, graph.Properties{
+				"sourceFile":     file.Path,
+				"lineNumber":     stmt.LineNumber,
+				"isView":         true,
+				"isMaterialized": match[1] != "",
+				"references":     referenced,
+				"language":       "sql",
+			})
+			entities = append(entities, viewEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, viewEntity.ID, graph.RelationshipTypeDefines, nil))
+			for _, table := range referenced {
+				if tableID, ok := lookupSQLTable(tableIDs, table); ok {
+					relationships = append(relationships, graph.CreateRelationship(
+						viewEntity.ID, tableID, graph.RelationshipTypeReferences, nil))
+				}
+			}
+			continue
+		}
+
+		if match := sqlCreateProcedureRegex.FindStringSubmatch(stmt.Text); match != nil {
+			procEntity := graph.CreateEntity(normalizeSQLIdentifier(match[2]), graph.EntityTypeFunction, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": stmt.LineNumber,
+				"kind":       strings.ToLower(match[1]),
+				"language":   "sql",
+			})
+			entities = append(entities, procEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				fileEntity.ID, procEntity.ID, graph.RelationshipTypeDefines, nil))
+		}
+	}
+
+	return entities, relationships, nil
+}
+
+// sqlForeignKeyRelationships creates DEPENDS_ON relationships for the foreign keys of
+// a CREATE TABLE or ALTER TABLE statement. Only tables defined in the same file can
+// be linked.
+func sqlForeignKeyRelationships(stmt SQLStatement, tableID string, tableIDs map[string]string) []graph.Relationship {
+	var relationships []graph.Relationship
+	if tableID == "" {
+		return relationships
+	}
+
+	seen := make(map[string]bool)
+	for _, loc := range sqlForeignKeyRegex.FindAllStringSubmatchIndex(stmt.Text, -1) {
+		referencedID, ok := lookupSQLTable(tableIDs, stmt.Text[loc[4]:loc[5]])
+		if !ok || referencedID == tableID {
+			continue
+		}
+
+		var column, referencedColumn string
+		if loc[2] != -1 {
+			column = stmt.Text[loc[2]:loc[3]]
+		} else {
+			// Inline column constraint: "user_id INTEGER REFERENCES users(id)"
+			definition := stmt.Text[:loc[0]]
+			definition = definition[strings.LastIndexAny(definition, ",(")+1:]
+			if fields := strings.Fields(definition); len(fields) > 0 {
+				column = fields[0]
+			}
+		}
+		if loc[6] != -1 {
+			referencedColumn = stmt.Text[loc[6]:loc[7]]
+		}
+
+		rel := graph.CreateRelationship(tableID, referencedID, graph.RelationshipTypeDependsOn, graph.Properties{
+			"column":           normalizeSQLIdentifier(strings.TrimSpace(column)),
+			"referencedColumn": normalizeSQLIdentifier(strings.TrimSpace(referencedColumn)),
+		})
+		if !seen[rel.ID] {
+			seen[rel.ID] = true
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships
+}
+
+// splitSQLStatements removes comments and splits SQL on semicolons outside of
+// string literals, recording the line each statement starts on. Semicolons in
+// dollar-quoted bodies ($$ ... $$) and in the BEGIN ... END body of a CREATE
+// PROCEDURE, FUNCTION or TRIGGER don't end the statement. A MySQL DELIMITER
+// directive replaces the semicolon as statement terminator until the next one.
+func splitSQLStatements(content string) []SQLStatement {
+	var statements []SQLStatement
+	var current strings.Builder
+	line, startLine := 1, 0
+	delimiter := ";"
+	dollarQuote := ""
+	blockDepth := 0
+
+	flush := func() {
+		text := strings.TrimSpace(current.String())
+		if text != "" {
+			statements = append(statements, SQLStatement{Text: text, LineNumber: startLine})
+		}
+		current.Reset()
+		startLine = 0
+		blockDepth = 0
+	}
+
+	for _, rawLine := range strings.SplitAfter(content, "\n") {
+		if dollarQuote == "" && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(rawLine)), "DELIMITER") {
+			if fields := strings.Fields(rawLine); len(fields) > 1 {
+				delimiter = fields[1]
+			}
+			line++
+			continue
+		}
+
+		inString := false
+		for i := 0; i < len(rawLine); i++ {
+			c := rawLine[i]
+			switch {
+			case dollarQuote != "":
+				if strings.HasPrefix(rawLine[i:], dollarQuote) {
+					current.WriteString(dollarQuote)
+					i += len(dollarQuote) - 1
+					dollarQuote = ""
+					continue
+				}
+			case inString:
+				if c == '\'' {
+					inString = false
+				}
+			case c == '\'':
+				inString = true
+			case delimiter != ";" && strings.HasPrefix(rawLine[i:], delimiter):
+				flush()
+				i += len(delimiter) - 1
+				continue
+			case c == '$':
+				if tag := sqlDollarQuoteRegex.FindString(rawLine[i:]); tag != "" {
+					if startLine == 0 {
+						startLine = line
+					}
+					current.WriteString(tag)
+					i += len(tag) - 1
+					dollarQuote = tag
+					continue
+				}
+			case c == '-' && i+1 < len(rawLine) && rawLine[i+1] == '-':
+				i = len(rawLine) - 1
+				c = '\n'
+			case c == ';' && delimiter == ";" && blockDepth == 0:
+				flush()
+				continue
+			case isSQLWordChar(c) && (i == 0 || !isSQLWordChar(rawLine[i-1])):
+				blockDepth += sqlBlockDepthChange(rawLine[i:], blockDepth, current.String())
+			}
+
+			if startLine == 0 && c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				startLine = line
+			}
+			current.WriteByte(c)
+		}
+		line++
+	}
+	flush()
+
+	// Block comments may span statements' lines; drop them after splitting
+	blockComment := regexp.MustCompile(`(?s)/\*.*?\*/`)
+	for i := range statements {
+		statements[i].Text = strings.TrimSpace(blockComment.ReplaceAllString(statements[i].Text, " "))
+	}
+
+	return statements
+}
+
+// sqlBlockDepthChange returns how the word at the start of rest changes the depth
+// of BEGIN ... END blocks in the statement read so far. The body of a routine
+// opens with BEGIN; inside it, BEGIN and CASE open nested blocks, closed by END
+// or END CASE, while END IF, END LOOP, END WHILE and END REPEAT close statements
+// that aren't counted.
+func sqlBlockDepthChange(rest string, depth int, statement string) int {
+	word := strings.ToUpper(rest[:sqlWordEnd(rest)])
+	switch {
+	case word == "BEGIN" && depth == 0:
+		if sqlRoutineRegex.MatchString(statement) {
+			return 1
+		}
+	case word == "BEGIN" && depth > 0:
+		return 1
+	case word == "CASE" && depth > 0:
+		// The CASE of END CASE closes a block instead
+		if !sqlTrailingEndRegex.MatchString(statement) {
+			return 1
+		}
+	case word == "END" && depth > 0:
+		next := strings.TrimLeft(rest[len(word):], " \t")
+		switch strings.ToUpper(next[:sqlWordEnd(next)]) {
+		case "IF", "LOOP", "WHILE", "REPEAT":
+			return 0
+		}
+		return -1
+	}
+	return 0
+}
+
+// sqlWordEnd returns the length of the word at the start of s
+func sqlWordEnd(s string) int {
+	end := 0
+	for end < len(s) && isSQLWordChar(s[end]) {
+		end++
+	}
+	return end
+}
+
+// isSQLWordChar reports whether c can be part of an unquoted SQL keyword or identifier
+func isSQLWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// extractSQLColumns returns the column names of a CREATE TABLE statement
+func extractSQLColumns(statement string) []string {
+	start := strings.Index(statement, "(")
+	if start == -1 {
+		return []string{}
+	}
+
+	// Split the column list on top-level commas
+	var definitions []string
+	depth, last := 0, start+1
+	for i := start; i < len(statement); i++ {
+		switch statement[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				definitions = append(definitions, statement[last:i])
+				i = len(statement)
+			}
+		case ',':
+			if depth == 1 {
+				definitions = append(definitions, statement[last:i])
+				last = i + 1
+			}
+		}
+	}
+
+	constraintKeywords := map[string]bool{
+		"CONSTRAINT": true, "PRIMARY": true, "FOREIGN": true, "UNIQUE": true,
+		"KEY": true, "INDEX": true, "CHECK": true, "FULLTEXT": true, "SPATIAL": true, "EXCLUDE": true,
+	}
+
+	columns := []string{}
+	for _, definition := range definitions {
+		fields := strings.Fields(definition)
+		if len(fields) == 0 || constraintKeywords[strings.ToUpper(fields[0])] {
+			continue
+		}
+		columns = append(columns, normalizeSQLIdentifier(fields[0]))
+	}
+	return columns
+}
+
+// extractSQLTableSources returns the distinct tables named in FROM and JOIN clauses
+func extractSQLTableSources(query string) []string {
+	seen := make(map[string]bool)
+	tables := []string{}
+	for _, match := range sqlTableSourceRegex.FindAllStringSubmatch(query, -1) {
+		table := normalizeSQLIdentifier(match[1])
+		if seen[strings.ToLower(table)] {
+			continue
+		}
+		seen[strings.ToLower(table)] = true
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// lookupSQLTable finds a table by name, ignoring case and, if needed, the schema
+func lookupSQLTable(tableIDs map[string]string, identifier string) (string, bool) {
+	name := strings.ToLower(normalizeSQLIdentifier(identifier))
+	if id, ok := tableIDs[name]; ok {
+		return id, true
+	}
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		id, ok := tableIDs[name[idx+1:]]
+		return id, ok
+	}
+	return "", false
+}
+
+// normalizeSQLIdentifier strips quoting from an identifier, e.g. `public`."users" -> public.users
+func normalizeSQLIdentifier(identifier string) string {
+	return strings.NewReplacer("`", "", "\"", "", "[", "", "]", "").Replace(identifier)
+}
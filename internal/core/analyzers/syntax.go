@@ -0,0 +1,105 @@
+package analyzers
+
+import "strings"
+
+// matchingParen returns the index of the parenthesis closing the one at open, or -1
+func matchingParen(s string, open int) int {
+	if open < 0 || open >= len(s) || s[open] != '(' {
+		return -1
+	}
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findBlockEnd returns the 1-based line on which the block opened on or after
+// line start closes. Brace depth is counted from the start line; when it returns
+// to 0 the block is complete. Declarations without a body end on their own line.
+func findBlockEnd(lines []string, start int) int {
+	depth := 0
+	opened := false
+
+	for i := start; i < len(lines); i++ {
+		opens, closes := countBraces(lines[i])
+		if opens > 0 {
+			opened = true
+		}
+		depth += opens - closes
+
+		if !opened {
+			// A complete signature without a body, e.g. a function implemented in assembly
+			line := strings.TrimSpace(stripLineComment(lines[i]))
+			if !strings.HasSuffix(line, "(") && !strings.HasSuffix(line, ",") {
+				return i + 1
+			}
+			continue
+		}
+		if depth <= 0 {
+			return i + 1
+		}
+	}
+
+	return len(lines)
+}
+
+// countBraces counts the opening and closing braces on a line of code,
+// ignoring braces inside string and character literals and // comments
+func countBraces(line string) (int, int) {
+	opens, closes := 0, 0
+	inString := false
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inString:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				inString = false
+			}
+		case c == '"' || c == '`' || c == '\'':
+			inString = true
+			quote = c
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return opens, closes
+		case c == '{':
+			opens++
+		case c == '}':
+			closes++
+		}
+	}
+	return opens, closes
+}
+
+// stripLineComment removes a trailing // comment from a line of code
+func stripLineComment(line string) string {
+	inString := false
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inString:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				inString = false
+			}
+		case c == '"' || c == '`' || c == '\'':
+			inString = true
+			quote = c
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return line[:i]
+		}
+	}
+	return line
+}
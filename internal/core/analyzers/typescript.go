@@ -3,6 +3,7 @@ package analyzers
 import (
 	"codegraphgen/internal/core/graph"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -24,6 +25,9 @@ type TypeScriptClass struct {
 	Implements []string
 	Methods    []TypeScriptMethod
 	Properties []TypeScriptProperty
+	Calls      []FunctionCall
+	Decorators []TypeScriptDecorator
+	Injections []TypeScriptInjection
 }
 
 type TypeScriptMethod struct {
@@ -34,12 +38,29 @@ type TypeScriptMethod struct {
 	IsAsync    bool
 	Parameters []string
 	ReturnType string
+	Decorators []TypeScriptDecorator
+}
+
+// TypeScriptDecorator is a decorator such as @Controller('/users') applied to
+// the class or member declared after it, with the source of its arguments
+type TypeScriptDecorator struct {
+	Name       string
+	Arguments  string
+	LineNumber int
+}
+
+// TypeScriptInjection is a NestJS @Inject(TOKEN) of a constructor parameter or
+// property of a class, naming the provider token injected
+type TypeScriptInjection struct {
+	Token      string
+	LineNumber int
 }
 
 type TypeScriptProperty struct {
 	Name       string
 	LineNumber int
 	Visibility string
+	IsPrivate  bool
 	IsStatic   bool
 	Type       string
 	IsReadonly bool
@@ -68,6 +89,27 @@ type TypeScriptType struct {
 	Definition string
 }
 
+// TypeScriptReExport is an export of names from another module, such as
+// export { Foo, Bar as Baz } from './module' or export * from './module'
+type TypeScriptReExport struct {
+	Name       string
+	ExportedAs string
+	Source     string
+	IsAll      bool
+	LineNumber int
+}
+
+// TypeScriptNamespace is a namespace or an ambient module declaration
+// (declare module 'foo') spanning lines LineNumber to EndLine
+type TypeScriptNamespace struct {
+	Name       string
+	LineNumber int
+	EndLine    int
+	IsExported bool
+	IsAmbient  bool
+	IsModule   bool
+}
+
 // TypeScriptAnalyzer implements the LanguageAnalyzer interface for TypeScript/JavaScript
 
 type TypeScriptAnalyzer struct{}
@@ -93,6 +135,7 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 	for _, imp := range imports {
 		importEntity := graph.CreateEntity(imp.Name, graph.EntityTypeImport, graph.Properties{
 			"source":      imp.Source,
+			"sourceFile":  file.Path,
 			"isDefault":   imp.IsDefault,
 			"isNamespace": imp.IsNamespace,
 			"lineNumber":  imp.LineNumber,
@@ -100,11 +143,36 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 		})
 		entities = append(entities, importEntity)
 		relationships = append(relationships, graph.CreateRelationship(
-			fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, nil))
+			fileEntity.ID, importEntity.ID, graph.RelationshipTypeImports, graph.Properties{
+				"importedAt": imp.LineNumber,
+			}))
+	}
+
+	// Extract re-exports, which analysis.LinkTypeScriptReExports links to the
+	// re-exported entities
+	for _, reExport := range extractTypeScriptReExports(content) {
+		label := reExport.ExportedAs
+		if label == "" {
+			label = reExport.Source
+		}
+		exportEntity := graph.CreateEntity(label, graph.EntityTypeExport, graph.Properties{
+			"name":        reExport.Name,
+			"exportedAs":  reExport.ExportedAs,
+			"source":      reExport.Source,
+			"sourceFile":  file.Path,
+			"lineNumber":  reExport.LineNumber,
+			"isReExport":  true,
+			"isExportAll": reExport.IsAll,
+			"language":    file.Language,
+		})
+		entities = append(entities, exportEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, exportEntity.ID, graph.RelationshipTypeDefines, nil))
 	}
 
 	// Extract classes
 	classes := extractTypeScriptClasses(content)
+	classIDs := make(map[string]string)
 	for _, cls := range classes {
 		classEntity := graph.CreateEntity(cls.Name, graph.EntityTypeClass, graph.Properties{
 			"sourceFile": file.Path,
@@ -115,11 +183,16 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 			"implements": cls.Implements,
 			"language":   file.Language,
 		})
+		if len(cls.Decorators) > 0 {
+			classEntity.Properties["decorators"] = typeScriptDecoratorNames(cls.Decorators)
+		}
 		entities = append(entities, classEntity)
 		relationships = append(relationships, graph.CreateRelationship(
 			fileEntity.ID, classEntity.ID, graph.RelationshipTypeDefines, nil))
+		classIDs[cls.Name] = classEntity.ID
 
 		// Extract methods
+		methodIDs := make(map[string]string)
 		for _, method := range cls.Methods {
 			methodEntity := graph.CreateEntity(method.Name, graph.EntityTypeMethod, graph.Properties{
 				"sourceFile": file.Path,
@@ -131,9 +204,45 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 				"returnType": method.ReturnType,
 				"language":   file.Language,
 			})
+			if len(method.Decorators) > 0 {
+				methodEntity.Properties["decorators"] = typeScriptDecoratorNames(method.Decorators)
+			}
 			entities = append(entities, methodEntity)
 			relationships = append(relationships, graph.CreateRelationship(
 				classEntity.ID, methodEntity.ID, graph.RelationshipTypeContains, nil))
+			methodIDs[method.Name] = methodEntity.ID
+		}
+
+		// Extract the routes of NestJS controllers, calling the method they decorate
+		for _, route := range extractNestRoutes(cls) {
+			endpointEntity := graph.CreateEntity(route.Method+" "+route.Path, graph.EntityTypeAPIEndpoint, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": route.LineNumber,
+				"language":   file.Language,
+				"method":     route.Method,
+				"path":       route.Path,
+				"framework":  "nestjs",
+				"controller": cls.Name,
+			})
+			entities = append(entities, endpointEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				classEntity.ID, endpointEntity.ID, graph.RelationshipTypeDefines, nil))
+			if handlerID, ok := methodIDs[route.Handler]; ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					endpointEntity.ID, handlerID, graph.RelationshipTypeCalls, nil))
+			}
+		}
+
+		// Create CALLS relationships between methods of the same class
+		for _, call := range cls.Calls {
+			callerID, callerOK := methodIDs[call.Caller]
+			calleeID, calleeOK := methodIDs[call.Callee]
+			if callerOK && calleeOK && callerID != calleeID {
+				relationships = append(relationships, graph.CreateRelationship(
+					callerID, calleeID, graph.RelationshipTypeCalls, graph.Properties{
+						"calledAt": call.LineNumber,
+					}))
+			}
 		}
 
 		// Extract properties
@@ -142,6 +251,7 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 				"sourceFile": file.Path,
 				"lineNumber": prop.LineNumber,
 				"visibility": prop.Visibility,
+				"isPrivate":  prop.IsPrivate,
 				"isStatic":   prop.IsStatic,
 				"type":       prop.Type,
 				"isReadonly": prop.IsReadonly,
@@ -153,6 +263,34 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 		}
 	}
 
+	// Link the classes to the providers injected with @Inject: a class of the
+	// file, or else a DEPENDENCY entity named after the token
+	for _, cls := range classes {
+		linked := make(map[string]bool)
+		for _, injection := range cls.Injections {
+			if linked[injection.Token] {
+				continue
+			}
+			linked[injection.Token] = true
+
+			dependencyID, ok := classIDs[injection.Token]
+			if !ok {
+				dependencyEntity := graph.CreateEntity(injection.Token, graph.EntityTypeDependency, graph.Properties{
+					"sourceFile":       file.Path,
+					"lineNumber":       injection.LineNumber,
+					"language":         file.Language,
+					"isInjectionToken": true,
+				})
+				entities = append(entities, dependencyEntity)
+				dependencyID = dependencyEntity.ID
+			}
+			relationships = append(relationships, graph.CreateRelationship(
+				classIDs[cls.Name], dependencyID, graph.RelationshipTypeDependsOn, graph.Properties{
+					"injectedAt": injection.LineNumber,
+				}))
+		}
+	}
+
 	// Extract functions
 	functions := extractTypeScriptFunctions(content)
 	for _, fn := range functions {
@@ -200,6 +338,46 @@ func analyzeTypeScriptFile(file graph.CodeFile, fileEntity graph.Entity) ([]grap
 			fileEntity.ID, typeEntity.ID, graph.RelationshipTypeDefines, nil))
 	}
 
+	// Extract namespaces and ambient modules
+	namespaces := extractTypeScriptNamespaces(content)
+	namespaceIDs := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		entityType := graph.EntityTypeNamespace
+		if ns.IsModule {
+			entityType = graph.EntityTypeModule
+		}
+		nsEntity := graph.CreateEntity(ns.Name, entityType, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": ns.LineNumber,
+			"endLine":    ns.EndLine,
+			"isExported": ns.IsExported,
+			"isAmbient":  ns.IsAmbient,
+			"language":   file.Language,
+		})
+		entities = append(entities, nsEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, nsEntity.ID, graph.RelationshipTypeDefines, nil))
+		namespaceIDs[i] = nsEntity.ID
+	}
+
+	// Namespaces contain the classes, interfaces, functions and nested namespaces
+	// declared within their braces
+	if len(namespaces) > 0 {
+		for _, entity := range entities {
+			switch entity.Type {
+			case graph.EntityTypeClass, graph.EntityTypeInterface, graph.EntityTypeFunction,
+				graph.EntityTypeNamespace, graph.EntityTypeModule:
+			default:
+				continue
+			}
+			lineNumber, _ := entity.Properties["lineNumber"].(int)
+			if i := enclosingTypeScriptNamespace(namespaces, lineNumber); i >= 0 {
+				relationships = append(relationships, graph.CreateRelationship(
+					namespaceIDs[i], entity.ID, graph.RelationshipTypeContains, nil))
+			}
+		}
+	}
+
 	return entities, relationships, nil
 }
 
@@ -269,7 +447,8 @@ func extractTypeScriptClasses(content string) []TypeScriptClass {
 	var classes []TypeScriptClass
 	lines := strings.Split(content, "\n")
 
-	classRegex := regexp.MustCompile(`(?:export\s+)?(?:abstract\s+)?class\s+(\w+)(?:\s+extends\s+(\w+))?(?:\s+implements\s+(.+?))?`)
+	classRegex := regexp.MustCompile(`(?:export\s+)?(?:abstract\s+)?class\s+(\w+)(?:\s+extends\s+(\w+))?(?:\s+implements\s+([\w.]+(?:\s*,\s*[\w.]+)*))?`)
+	decorators := extractTypeScriptDecorators(lines)
 
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
@@ -301,10 +480,23 @@ func extractTypeScriptClasses(content string) []TypeScriptClass {
 				Implements: implements,
 				Methods:    []TypeScriptMethod{},
 				Properties: []TypeScriptProperty{},
+				Decorators: decorators[i],
 			}
 
-			// Extract methods and properties from class body (simplified)
-			// In a full implementation, you'd parse the class body more thoroughly
+			// Extract methods, fields and the calls between methods from the class body
+			methods, properties, calls := extractTypeScriptClassBody(lines, i)
+			for j := range methods {
+				methods[j].Decorators = decorators[methods[j].LineNumber-1]
+			}
+			if len(methods) > 0 {
+				classInfo.Methods = methods
+			}
+			if len(properties) > 0 {
+				classInfo.Properties = properties
+			}
+			classInfo.Calls = calls
+			classInfo.Injections = extractNestInjections(lines, i)
+
 			classes = append(classes, classInfo)
 		}
 	}
@@ -312,6 +504,356 @@ func extractTypeScriptClasses(content string) []TypeScriptClass {
 	return classes
 }
 
+// Class members recognised by extractTypeScriptClassBody
+var (
+	tsClassMethodRegex = regexp.MustCompile(`^(?:(public|private|protected)\s+)?((?:(?:static|async|abstract|override)\s+)*)(\w+)\s*(?:<[^>]*>)?\s*\(((?:[^()]|\([^()]*\))*)\)\s*(?::\s*([^{;]+?))?\s*(?:[{;].*)?$`)
+	tsClassFieldRegex  = regexp.MustCompile(`^(?:(public|private|protected)\s+)?((?:(?:static|readonly|declare|override|abstract)\s+)*)(#?\w+)[?!]?\s*(?::\s*([^=;]+?))?\s*(?:=.*|;)?$`)
+	tsThisCallRegex    = regexp.MustCompile(`this\.(\w+)\s*\(`)
+)
+
+// tsControlKeywords are keywords that look like method signatures to tsClassMethodRegex
+var tsControlKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"return": true, "function": true, "new": true,
+}
+
+// tsNonFieldKeywords start class members that look like field declarations to
+// tsClassFieldRegex but aren't fields
+var tsNonFieldKeywords = map[string]bool{
+	"constructor": true, "get": true, "set": true, "async": true, "return": true,
+}
+
+// extractTypeScriptClassBody reads the class body starting at the class declaration
+// on line start until the matching closing brace. Method signatures and field
+// declarations (name = value, name: Type, #private and static fields) are
+// recognised at class depth; inside a method body, this.name(...) calls to other
+// methods of the class are recorded.
+func extractTypeScriptClassBody(lines []string, start int) ([]TypeScriptMethod, []TypeScriptProperty, []FunctionCall) {
+	var methods []TypeScriptMethod
+	var properties []TypeScriptProperty
+	var calls []FunctionCall
+
+	var currentMethod string
+	depth := 0
+	opened := false
+
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		// Only lines at class depth can start a new member
+		if opened && depth == 1 {
+			currentMethod = ""
+			if match := tsClassMethodRegex.FindStringSubmatch(line); len(match) > 3 && !tsControlKeywords[match[3]] {
+				visibility := match[1]
+				if visibility == "" {
+					visibility = "public"
+				}
+
+				var parameters []string
+				for _, param := range splitTypeScriptParameters(match[4]) {
+					if param = strings.TrimSpace(param); param != "" {
+						parameters = append(parameters, param)
+					}
+				}
+
+				returnType := strings.TrimSpace(match[5])
+				if returnType == "" {
+					returnType = "unknown"
+				}
+
+				methods = append(methods, TypeScriptMethod{
+					Name:       match[3],
+					LineNumber: i + 1,
+					Visibility: visibility,
+					IsStatic:   strings.Contains(match[2], "static"),
+					IsAsync:    strings.Contains(match[2], "async"),
+					Parameters: parameters,
+					ReturnType: returnType,
+				})
+				currentMethod = match[3]
+			} else if match := tsClassFieldRegex.FindStringSubmatch(line); match != nil && !tsNonFieldKeywords[match[3]] {
+				isPrivate := strings.HasPrefix(match[3], "#") || match[1] == "private"
+				visibility := match[1]
+				if visibility == "" {
+					visibility = "public"
+					if isPrivate {
+						visibility = "private"
+					}
+				}
+
+				fieldType := strings.TrimSpace(match[4])
+				if fieldType == "" {
+					fieldType = "unknown"
+				}
+
+				properties = append(properties, TypeScriptProperty{
+					Name:       match[3],
+					LineNumber: i + 1,
+					Visibility: visibility,
+					IsPrivate:  isPrivate,
+					IsStatic:   strings.Contains(match[2], "static"),
+					Type:       fieldType,
+					IsReadonly: strings.Contains(match[2], "readonly"),
+				})
+			}
+		}
+
+		if currentMethod != "" {
+			for _, call := range tsThisCallRegex.FindAllStringSubmatch(line, -1) {
+				calls = append(calls, FunctionCall{
+					Caller:     currentMethod,
+					Callee:     call[1],
+					LineNumber: i + 1,
+				})
+			}
+		}
+
+		opens := strings.Count(line, "{")
+		depth += opens - strings.Count(line, "}")
+		if opens > 0 {
+			opened = true
+		}
+		if opened && depth <= 0 {
+			break
+		}
+	}
+
+	return methods, properties, calls
+}
+
+var (
+	tsDecoratorRegex = regexp.MustCompile(`^@([\w.]+)`)
+	tsInjectRegex    = regexp.MustCompile(`@Inject\(\s*(?:forwardRef\(\s*\(\)\s*=>\s*([\w.]+)\s*\)|['"]([^'"]+)['"]|([\w.]+))\s*\)`)
+	tsStringRegex    = regexp.MustCompile("['\"`]([^'\"`]*)['\"`]")
+	tsPathRegex      = regexp.MustCompile(`\bpath\s*:\s*['"\x60]([^'"\x60]*)['"\x60]`)
+)
+
+// nestHTTPDecorators maps the NestJS route decorators to their HTTP method
+var nestHTTPDecorators = map[string]string{
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH", "Delete": "DELETE",
+	"Options": "OPTIONS", "Head": "HEAD", "All": "ALL",
+}
+
+// extractTypeScriptDecorators returns the decorators of the file by the index
+// of the line they decorate: the first line after them that is not a blank
+// line, a comment or another decorator. Decorator arguments may span lines.
+func extractTypeScriptDecorators(lines []string) map[int][]TypeScriptDecorator {
+	decorators := make(map[int][]TypeScriptDecorator)
+	var pending []TypeScriptDecorator
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		match := tsDecoratorRegex.FindStringSubmatch(line)
+		if match == nil {
+			if len(pending) > 0 {
+				decorators[i] = pending
+				pending = nil
+			}
+			continue
+		}
+
+		decorator := TypeScriptDecorator{Name: match[1], LineNumber: i + 1}
+		rest := strings.TrimSpace(line[len(match[0]):])
+		if strings.HasPrefix(rest, "(") {
+			// Read the arguments up to the matching parenthesis
+			text := rest
+			end := i
+			for close := matchingParenthesis(text); close == -1 && end+1 < len(lines); close = matchingParenthesis(text) {
+				end++
+				text += "\n" + strings.TrimSpace(lines[end])
+			}
+			close := matchingParenthesis(text)
+			if close == -1 {
+				close = len(text) - 1
+			}
+			decorator.Arguments = strings.TrimSpace(text[1:close])
+			rest = strings.TrimSpace(text[close+1:])
+			i = end
+		}
+		pending = append(pending, decorator)
+
+		// A declaration may follow the decorator on the same line
+		if rest != "" && !strings.HasPrefix(rest, "@") {
+			decorators[i] = pending
+			pending = nil
+		}
+	}
+	return decorators
+}
+
+// matchingParenthesis returns the index of the parenthesis closing the one at
+// the start of s, skipping strings, or -1 if s does not close it
+func matchingParenthesis(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTypeScriptParameters splits a parameter list on the commas that are not
+// nested in brackets or strings, such as those of @Param('id', ParseIntPipe)
+func splitTypeScriptParameters(list string) []string {
+	var parameters []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.IndexByte("([{<", c) != -1:
+			depth++
+		case strings.IndexByte(")]}>", c) != -1:
+			// Skip the > of => in arrow function types
+			if c != '>' || i == 0 || list[i-1] != '=' {
+				depth--
+			}
+		case c == ',' && depth == 0:
+			parameters = append(parameters, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(parameters, list[start:])
+}
+
+// typeScriptDecoratorNames returns the names of decorators
+func typeScriptDecoratorNames(decorators []TypeScriptDecorator) []string {
+	names := make([]string, 0, len(decorators))
+	for _, decorator := range decorators {
+		names = append(names, decorator.Name)
+	}
+	return names
+}
+
+// NestRoute is a route of a NestJS controller: a method decorated with @Get,
+// @Post, etc., with the path of the controller prepended to its own
+type NestRoute struct {
+	Method     string
+	Path       string
+	Handler    string
+	LineNumber int
+}
+
+// extractNestRoutes returns the routes of a class decorated with @Controller
+func extractNestRoutes(cls TypeScriptClass) []NestRoute {
+	prefix, isController := "", false
+	for _, decorator := range cls.Decorators {
+		if decorator.Name == "Controller" {
+			prefix, isController = nestDecoratorPath(decorator.Arguments), true
+		}
+	}
+	if !isController {
+		return nil
+	}
+
+	var routes []NestRoute
+	for _, method := range cls.Methods {
+		for _, decorator := range method.Decorators {
+			httpMethod, ok := nestHTTPDecorators[decorator.Name]
+			if !ok {
+				continue
+			}
+			routes = append(routes, NestRoute{
+				Method:     httpMethod,
+				Path:       joinRoutePaths(prefix, nestDecoratorPath(decorator.Arguments)),
+				Handler:    method.Name,
+				LineNumber: decorator.LineNumber,
+			})
+		}
+	}
+	return routes
+}
+
+// nestDecoratorPath returns the path given to a @Controller or route
+// decorator, either as its first string argument or as the path of its
+// options object; the first path of an array of paths is used
+func nestDecoratorPath(arguments string) string {
+	if strings.HasPrefix(arguments, "{") {
+		if match := tsPathRegex.FindStringSubmatch(arguments); match != nil {
+			return match[1]
+		}
+		return ""
+	}
+	if match := tsStringRegex.FindStringSubmatch(arguments); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// joinRoutePaths joins the segments of route paths into a path starting with /
+func joinRoutePaths(paths ...string) string {
+	var segments []string
+	for _, path := range paths {
+		for _, segment := range strings.Split(path, "/") {
+			if segment != "" {
+				segments = append(segments, segment)
+			}
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// extractNestInjections returns the @Inject(TOKEN) decorators of the body of
+// the class declared on line start. The token of @Inject(forwardRef(() => T))
+// is T.
+func extractNestInjections(lines []string, start int) []TypeScriptInjection {
+	var injections []TypeScriptInjection
+	depth := 0
+	opened := false
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		for _, match := range tsInjectRegex.FindAllStringSubmatch(line, -1) {
+			injections = append(injections, TypeScriptInjection{
+				Token:      match[1] + match[2] + match[3],
+				LineNumber: i + 1,
+			})
+		}
+
+		opens := strings.Count(line, "{")
+		depth += opens - strings.Count(line, "}")
+		if opens > 0 {
+			opened = true
+		}
+		if opened && depth <= 0 {
+			break
+		}
+	}
+	return injections
+}
+
+// tsSingleParamArrowRegex matches an arrow function with a single parameter
+// without parentheses (x => x)
+var tsSingleParamArrowRegex = regexp.MustCompile(`(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?(\w+)\s*=>`)
+
 func extractTypeScriptFunctions(content string) []TypeScriptFunction {
 	var functions []TypeScriptFunction
 	lines := strings.Split(content, "\n")
@@ -324,22 +866,32 @@ func extractTypeScriptFunctions(content string) []TypeScriptFunction {
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 
-		if match := funcRegex.FindStringSubmatch(line); len(match) > 1 {
+		if match := funcRegex.FindStringSubmatchIndex(line); match != nil {
 			functions = append(functions, TypeScriptFunction{
-				Name:       match[1],
+				Name:       line[match[2]:match[3]],
 				LineNumber: i + 1,
 				IsAsync:    strings.Contains(line, "async"),
 				IsExported: strings.Contains(line, "export"),
 				Parameters: []string{}, // Simplified for now
-				ReturnType: "unknown",
+				ReturnType: typeScriptReturnType(line, match[1]-1),
 			})
-		} else if match := arrowRegex.FindStringSubmatch(line); len(match) > 1 {
+		} else if match := arrowRegex.FindStringSubmatchIndex(line); match != nil {
 			functions = append(functions, TypeScriptFunction{
-				Name:       match[1],
+				Name:       line[match[2]:match[3]],
 				LineNumber: i + 1,
 				IsAsync:    strings.Contains(line, "async"),
 				IsExported: strings.Contains(line, "export"),
 				Parameters: []string{}, // Simplified for now
+				ReturnType: typeScriptReturnType(line, match[1]-1),
+			})
+		} else if match := tsSingleParamArrowRegex.FindStringSubmatch(line); match != nil {
+			functions = append(functions, TypeScriptFunction{
+				Name:       match[1],
+				LineNumber: i + 1,
+				IsAsync:    strings.Contains(line, "async"),
+				IsExported: strings.Contains(line, "export"),
+				Parameters: []string{match[2]},
+				// The parameter can't be annotated without parentheses, nor can the return type
 				ReturnType: "unknown",
 			})
 		}
@@ -348,6 +900,111 @@ func extractTypeScriptFunctions(content string) []TypeScriptFunction {
 	return functions
 }
 
+var (
+	tsReExportRegex    = regexp.MustCompile(`(?m)^[ \t]*export\s+(?:type\s+)?\{([^}]*)\}\s*from\s+['"]([^'"]+)['"]`)
+	tsExportAllRegex   = regexp.MustCompile(`(?m)^[ \t]*export\s+\*\s*(?:as\s+(\w+)\s+)?from\s+['"]([^'"]+)['"]`)
+	tsExportAliasRegex = regexp.MustCompile(`^(?:type\s+)?(\w+)(?:\s+as\s+(\w+))?$`)
+	tsLineCommentRegex = regexp.MustCompile(`//[^\n]*`)
+)
+
+// extractTypeScriptReExports finds the names exported from other modules:
+// export { Foo, Bar as Baz } from './module', also with the names spread over
+// several lines, export * from './module' and export * as ns from './module'
+func extractTypeScriptReExports(content string) []TypeScriptReExport {
+	var reExports []TypeScriptReExport
+	for _, match := range tsReExportRegex.FindAllStringSubmatchIndex(content, -1) {
+		lineNumber := strings.Count(content[:match[0]], "\n") + 1
+		specifiers := tsLineCommentRegex.ReplaceAllString(content[match[2]:match[3]], "")
+		source := content[match[4]:match[5]]
+		for _, specifier := range strings.Split(specifiers, ",") {
+			names := tsExportAliasRegex.FindStringSubmatch(strings.TrimSpace(specifier))
+			if names == nil {
+				continue
+			}
+			exportedAs := names[2]
+			if exportedAs == "" {
+				exportedAs = names[1]
+			}
+			reExports = append(reExports, TypeScriptReExport{
+				Name:       names[1],
+				ExportedAs: exportedAs,
+				Source:     source,
+				LineNumber: lineNumber,
+			})
+		}
+	}
+	for _, match := range tsExportAllRegex.FindAllStringSubmatchIndex(content, -1) {
+		reExport := TypeScriptReExport{
+			Name:       "*",
+			Source:     content[match[4]:match[5]],
+			IsAll:      true,
+			LineNumber: strings.Count(content[:match[0]], "\n") + 1,
+		}
+		if match[2] >= 0 {
+			reExport.ExportedAs = content[match[2]:match[3]]
+		}
+		reExports = append(reExports, reExport)
+	}
+	sort.SliceStable(reExports, func(i, j int) bool { return reExports[i].LineNumber < reExports[j].LineNumber })
+	return reExports
+}
+
+// typeScriptReturnType reads the return type annotation following the parameter
+// list that opens at index open, e.g. "): Promise<User> {" -> "Promise<User>".
+// Functions without an annotation on the same line return "unknown".
+func typeScriptReturnType(line string, open int) string {
+	closing := matchingParen(line, open)
+	if closing < 0 {
+		return "unknown"
+	}
+	rest := strings.TrimSpace(line[closing+1:])
+	if !strings.HasPrefix(rest, ":") {
+		return "unknown"
+	}
+	rest = rest[1:]
+
+	// The type ends at the body, the arrow or the end of the statement,
+	// skipping brackets of generic and object types
+	depth := 0
+	end := len(rest)
+scan:
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '(', '[', '<':
+			depth++
+		case ')', ']':
+			depth--
+		case '>':
+			if i > 0 && rest[i-1] == '=' {
+				if depth == 0 {
+					end = i - 1
+					break scan
+				}
+				continue
+			}
+			depth--
+		case '{':
+			if depth == 0 && strings.TrimSpace(rest[:i]) != "" {
+				end = i
+				break scan
+			}
+			depth++
+		case '}':
+			depth--
+		case ';':
+			if depth == 0 {
+				end = i
+				break scan
+			}
+		}
+	}
+
+	if returnType := strings.TrimSpace(rest[:end]); returnType != "" {
+		return returnType
+	}
+	return "unknown"
+}
+
 func extractTypeScriptInterfaces(content string) []TypeScriptInterface {
 	var interfaces []TypeScriptInterface
 	lines := strings.Split(content, "\n")
@@ -400,3 +1057,61 @@ func extractTypeScriptTypes(content string) []TypeScriptType {
 
 	return types
 }
+
+var (
+	tsNamespaceRegex     = regexp.MustCompile(`^(export\s+)?(declare\s+)?namespace\s+([\w.]+)`)
+	tsAmbientModuleRegex = regexp.MustCompile(`^(export\s+)?declare\s+module\s+(?:['"]([^'"]+)['"]|([\w.]+))`)
+)
+
+// extractTypeScriptNamespaces finds namespace declarations, including declare
+// namespace, and ambient module declarations such as declare module 'foo'.
+// The end of each declaration is found by tracking brace depth.
+func extractTypeScriptNamespaces(content string) []TypeScriptNamespace {
+	var namespaces []TypeScriptNamespace
+	lines := strings.Split(content, "\n")
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+
+		var ns TypeScriptNamespace
+		if match := tsNamespaceRegex.FindStringSubmatch(line); match != nil {
+			ns = TypeScriptNamespace{
+				Name:       match[3],
+				IsExported: match[1] != "",
+				IsAmbient:  match[2] != "",
+			}
+		} else if match := tsAmbientModuleRegex.FindStringSubmatch(line); match != nil {
+			name := match[2]
+			if name == "" {
+				name = match[3]
+			}
+			ns = TypeScriptNamespace{
+				Name:       name,
+				IsExported: match[1] != "",
+				IsAmbient:  true,
+				IsModule:   true,
+			}
+		} else {
+			continue
+		}
+
+		ns.LineNumber = i + 1
+		ns.EndLine = findBlockEnd(lines, i)
+		namespaces = append(namespaces, ns)
+	}
+
+	return namespaces
+}
+
+// enclosingTypeScriptNamespace returns the index of the innermost namespace whose
+// body contains lineNumber, or -1 if the line is not inside a namespace
+func enclosingTypeScriptNamespace(namespaces []TypeScriptNamespace, lineNumber int) int {
+	enclosing := -1
+	for i, ns := range namespaces {
+		if lineNumber > ns.LineNumber && lineNumber <= ns.EndLine &&
+			(enclosing < 0 || ns.LineNumber > namespaces[enclosing].LineNumber) {
+			enclosing = i
+		}
+	}
+	return enclosing
+}
@@ -0,0 +1,536 @@
+package analyzers
+
+import (
+	"codegraphgen/internal/core/graph"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubernetesHostRegex matches DNS names that may name a service; service names
+// start with a letter, which rules out plain numbers
+var kubernetesHostRegex = regexp.MustCompile(`^[a-z][-a-z0-9]*(\.[a-z0-9][-a-z0-9]*)*$`)
+
+// YAMLAnalyzer implements the LanguageAnalyzer interface for YAML.
+// It understands Kubernetes resources and GitHub Actions workflows;
+// other YAML files only produce a file entity.
+type YAMLAnalyzer struct{}
+
+func (ya *YAMLAnalyzer) Name() string                 { return "YAML Analyzer" }
+func (ya *YAMLAnalyzer) SupportedLanguages() []string { return []string{"yaml"} }
+func (ya *YAMLAnalyzer) Analyze(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	return analyzeYAMLFile(file, fileEntity)
+}
+
+// analyzeYAMLFile analyzes every document of a YAML file
+func analyzeYAMLFile(file graph.CodeFile, fileEntity graph.Entity) ([]graph.Entity, []graph.Relationship, error) {
+	entities := []graph.Entity{fileEntity}
+	var relationships []graph.Relationship
+
+	isWorkflow := strings.Contains(filepath.ToSlash(file.Path), ".github/workflows/")
+
+	decoder := yaml.NewDecoder(strings.NewReader(file.Content))
+	for {
+		var document yaml.Node
+		// Stops at the end of the stream, or at the first invalid document
+		// (e.g. a Helm template), keeping what was found so far
+		if err := decoder.Decode(&document); err != nil {
+			break
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		root := document.Content[0]
+
+		var docEntities []graph.Entity
+		var docRelationships []graph.Relationship
+		switch {
+		case isWorkflow:
+			docEntities, docRelationships = analyzeGitHubWorkflow(file, fileEntity, root)
+		case yamlMappingValue(root, "apiVersion") != nil && yamlMappingValue(root, "kind") != nil:
+			docEntities, docRelationships = analyzeKubernetesResource(file, fileEntity, root)
+		}
+		entities = append(entities, docEntities...)
+		relationships = append(relationships, docRelationships...)
+	}
+
+	return entities, relationships, nil
+}
+
+// analyzeKubernetesResource creates an entity for a Kubernetes resource, with its
+// container images as dependencies and its env vars as configuration.
+// Deployments, Services, Ingresses and ConfigMaps are typed entities labeled by
+// their name; other resources are classes labeled by their kind. The services and
+// config maps a workload references are recorded as properties, for
+// analysis.LinkKubernetesResources to link resources across documents and files.
+func analyzeKubernetesResource(file graph.CodeFile, fileEntity graph.Entity, root *yaml.Node) ([]graph.Entity, []graph.Relationship) {
+	var entities []graph.Entity
+	var relationships []graph.Relationship
+
+	kind := yamlScalar(yamlMappingValue(root, "kind"))
+	properties := graph.Properties{
+		"sourceFile": file.Path,
+		"lineNumber": root.Line,
+		"apiVersion": yamlScalar(yamlMappingValue(root, "apiVersion")),
+		"kind":       kind,
+		"language":   "yaml",
+	}
+
+	name := ""
+	if metadata := yamlMappingValue(root, "metadata"); metadata != nil {
+		name = yamlScalar(yamlMappingValue(metadata, "name"))
+		properties["name"] = name
+		if namespace := yamlScalar(yamlMappingValue(metadata, "namespace")); namespace != "" {
+			properties["namespace"] = namespace
+		}
+		if labels := yamlMappingValue(metadata, "labels"); labels != nil && labels.Kind == yaml.MappingNode {
+			labelMap := make(map[string]interface{})
+			for i := 0; i+1 < len(labels.Content); i += 2 {
+				labelMap[labels.Content[i].Value] = labels.Content[i+1].Value
+			}
+			properties["labels"] = labelMap
+		}
+	}
+
+	spec := yamlMappingValue(root, "spec")
+	entityType := graph.EntityTypeClass
+	label := kind
+	switch kind {
+	case "Deployment":
+		label = name
+	case "Service":
+		entityType, label = graph.EntityTypeAPIEndpoint, name
+		addKubernetesServiceProperties(properties, spec)
+	case "Ingress":
+		entityType, label = graph.EntityTypeAPIEndpoint, name
+		addKubernetesIngressProperties(properties, spec)
+	case "ConfigMap":
+		entityType, label = graph.EntityTypeConfiguration, name
+		if data := yamlMappingValue(root, "data"); data != nil && data.Kind == yaml.MappingNode {
+			keys := make([]string, 0, len(data.Content)/2)
+			for i := 0; i+1 < len(data.Content); i += 2 {
+				keys = append(keys, data.Content[i].Value)
+			}
+			properties["keys"] = keys
+		}
+	}
+	if label == "" {
+		label = kind
+	}
+
+	containers := findKubernetesContainers(spec)
+	if services := kubernetesServiceReferences(spec, containers); len(services) > 0 {
+		properties["serviceReferences"] = services
+	}
+	if configMaps := kubernetesConfigMapReferences(spec, containers); len(configMaps) > 0 {
+		properties["configMaps"] = configMaps
+	}
+
+	resourceEntity := graph.CreateEntity(label, entityType, properties)
+	entities = append(entities, resourceEntity)
+	relationships = append(relationships, graph.CreateRelationship(
+		fileEntity.ID, resourceEntity.ID, graph.RelationshipTypeDefines, nil))
+
+	for _, container := range containers {
+		containerName := yamlScalar(yamlMappingValue(container, "name"))
+
+		if imageNode := yamlMappingValue(container, "image"); imageNode != nil {
+			image, version := splitImageReference(imageNode.Value)
+			imageEntity := graph.CreateEntity(image, graph.EntityTypeDependency, graph.Properties{
+				"version":    version,
+				"sourceFile": file.Path,
+				"lineNumber": imageNode.Line,
+				"type":       "image",
+				"container":  containerName,
+			})
+			entities = append(entities, imageEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				resourceEntity.ID, imageEntity.ID, graph.RelationshipTypeDependsOn, nil))
+		}
+
+		env := yamlMappingValue(container, "env")
+		if env == nil || env.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, variable := range env.Content {
+			name := yamlScalar(yamlMappingValue(variable, "name"))
+			if name == "" {
+				continue
+			}
+			envProperties := graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": variable.Line,
+				"container":  containerName,
+				"language":   "yaml",
+			}
+			if value := yamlMappingValue(variable, "value"); value != nil {
+				envProperties["value"] = value.Value
+			}
+			if yamlMappingValue(variable, "valueFrom") != nil {
+				envProperties["valueFrom"] = true
+			}
+			envEntity := graph.CreateEntity(name, graph.EntityTypeConfiguration, envProperties)
+			entities = append(entities, envEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				envEntity.ID, resourceEntity.ID, graph.RelationshipTypeConfigures, nil))
+		}
+	}
+
+	return entities, relationships
+}
+
+// findKubernetesContainers collects containers and initContainers anywhere below spec,
+// which covers pods as well as pod templates of deployments, jobs and cron jobs
+func findKubernetesContainers(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	var containers []*yaml.Node
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if (key == "containers" || key == "initContainers") && value.Kind == yaml.SequenceNode {
+				containers = append(containers, value.Content...)
+				continue
+			}
+			containers = append(containers, findKubernetesContainers(value)...)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			containers = append(containers, findKubernetesContainers(item)...)
+		}
+	}
+	return containers
+}
+
+// addKubernetesServiceProperties records the type, ports and selector of a Service
+func addKubernetesServiceProperties(properties graph.Properties, spec *yaml.Node) {
+	serviceType := yamlScalar(yamlMappingValue(spec, "type"))
+	if serviceType == "" {
+		serviceType = "ClusterIP"
+	}
+	properties["serviceType"] = serviceType
+
+	if ports := yamlMappingValue(spec, "ports"); ports != nil && ports.Kind == yaml.SequenceNode {
+		var portNumbers []int
+		for _, port := range ports.Content {
+			if number, err := strconv.Atoi(yamlScalar(yamlMappingValue(port, "port"))); err == nil {
+				portNumbers = append(portNumbers, number)
+			}
+		}
+		properties["ports"] = portNumbers
+	}
+
+	if selector := yamlMappingValue(spec, "selector"); selector != nil && selector.Kind == yaml.MappingNode {
+		selectorMap := make(map[string]interface{})
+		for i := 0; i+1 < len(selector.Content); i += 2 {
+			selectorMap[selector.Content[i].Value] = selector.Content[i+1].Value
+		}
+		properties["selector"] = selectorMap
+	}
+}
+
+// addKubernetesIngressProperties records the hosts and paths of an Ingress, the
+// first of each as host and path, and the services its rules route to
+func addKubernetesIngressProperties(properties graph.Properties, spec *yaml.Node) {
+	var hosts, paths, backends []string
+	addBackend := func(backend *yaml.Node) {
+		// networking.k8s.io/v1 nests the service, v1beta1 names it directly
+		serviceName := yamlScalar(yamlMappingValue(yamlMappingValue(backend, "service"), "name"))
+		if serviceName == "" {
+			serviceName = yamlScalar(yamlMappingValue(backend, "serviceName"))
+		}
+		if serviceName != "" && !containsString(backends, serviceName) {
+			backends = append(backends, serviceName)
+		}
+	}
+
+	addBackend(yamlMappingValue(spec, "defaultBackend"))
+	if rules := yamlMappingValue(spec, "rules"); rules != nil && rules.Kind == yaml.SequenceNode {
+		for _, rule := range rules.Content {
+			if host := yamlScalar(yamlMappingValue(rule, "host")); host != "" {
+				hosts = append(hosts, host)
+			}
+			rulePaths := yamlMappingValue(yamlMappingValue(rule, "http"), "paths")
+			if rulePaths == nil || rulePaths.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, path := range rulePaths.Content {
+				if value := yamlScalar(yamlMappingValue(path, "path")); value != "" {
+					paths = append(paths, value)
+				}
+				addBackend(yamlMappingValue(path, "backend"))
+			}
+		}
+	}
+
+	if len(hosts) > 0 {
+		properties["host"] = hosts[0]
+		properties["hosts"] = hosts
+	}
+	if len(paths) > 0 {
+		properties["path"] = paths[0]
+		properties["paths"] = paths
+	}
+	if len(backends) > 0 {
+		properties["backendServices"] = backends
+	}
+}
+
+// kubernetesServiceReferences returns the hosts that may name services, read from
+// the env values of the containers (e.g. http://api.shop:8080 -> api.shop) and
+// from the servers of NFS volumes
+func kubernetesServiceReferences(spec *yaml.Node, containers []*yaml.Node) []string {
+	var hosts []string
+	add := func(value string) {
+		if host := kubernetesHost(value); host != "" && !containsString(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	for _, container := range containers {
+		if env := yamlMappingValue(container, "env"); env != nil && env.Kind == yaml.SequenceNode {
+			for _, variable := range env.Content {
+				add(yamlScalar(yamlMappingValue(variable, "value")))
+			}
+		}
+	}
+	for _, volume := range findKubernetesVolumes(spec) {
+		add(yamlScalar(yamlMappingValue(yamlMappingValue(volume, "nfs"), "server")))
+	}
+	return hosts
+}
+
+// kubernetesConfigMapReferences returns the names of the config maps mounted as
+// volumes, directly or projected, or loaded into the environment of a container
+func kubernetesConfigMapReferences(spec *yaml.Node, containers []*yaml.Node) []string {
+	var names []string
+	add := func(node *yaml.Node) {
+		if name := yamlScalar(yamlMappingValue(node, "name")); name != "" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, volume := range findKubernetesVolumes(spec) {
+		add(yamlMappingValue(volume, "configMap"))
+		sources := yamlMappingValue(yamlMappingValue(volume, "projected"), "sources")
+		if sources != nil && sources.Kind == yaml.SequenceNode {
+			for _, source := range sources.Content {
+				add(yamlMappingValue(source, "configMap"))
+			}
+		}
+	}
+	for _, container := range containers {
+		if envFrom := yamlMappingValue(container, "envFrom"); envFrom != nil && envFrom.Kind == yaml.SequenceNode {
+			for _, source := range envFrom.Content {
+				add(yamlMappingValue(source, "configMapRef"))
+			}
+		}
+		if env := yamlMappingValue(container, "env"); env != nil && env.Kind == yaml.SequenceNode {
+			for _, variable := range env.Content {
+				add(yamlMappingValue(yamlMappingValue(variable, "valueFrom"), "configMapKeyRef"))
+			}
+		}
+	}
+	return names
+}
+
+// findKubernetesVolumes collects the volumes anywhere below spec, like
+// findKubernetesContainers
+func findKubernetesVolumes(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	var volumes []*yaml.Node
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if key == "volumes" && value.Kind == yaml.SequenceNode {
+				volumes = append(volumes, value.Content...)
+				continue
+			}
+			volumes = append(volumes, findKubernetesVolumes(value)...)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			volumes = append(volumes, findKubernetesVolumes(item)...)
+		}
+	}
+	return volumes
+}
+
+// kubernetesHost returns the host of a URL or host:port value when it could be a
+// service DNS name such as api, api.shop or api.shop.svc.cluster.local, or ""
+func kubernetesHost(value string) string {
+	if _, rest, found := strings.Cut(value, "://"); found {
+		value = rest
+	}
+	if _, rest, found := strings.Cut(value, "@"); found {
+		value = rest
+	}
+	if index := strings.IndexAny(value, ":/?"); index >= 0 {
+		value = value[:index]
+	}
+	if !kubernetesHostRegex.MatchString(value) {
+		return ""
+	}
+	return value
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeGitHubWorkflow extracts the jobs and steps of a GitHub Actions workflow,
+// with the actions they use as dependencies
+func analyzeGitHubWorkflow(file graph.CodeFile, fileEntity graph.Entity, root *yaml.Node) ([]graph.Entity, []graph.Relationship) {
+	var entities []graph.Entity
+	var relationships []graph.Relationship
+
+	jobs := yamlMappingValue(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return entities, relationships
+	}
+
+	jobIDs := make(map[string]string)
+	needs := make(map[string][]string)
+
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobName, job := jobs.Content[i].Value, jobs.Content[i+1]
+
+		jobEntity := graph.CreateEntity(jobName, graph.EntityTypeFunction, graph.Properties{
+			"sourceFile": file.Path,
+			"lineNumber": jobs.Content[i].Line,
+			"runsOn":     yamlScalar(yamlMappingValue(job, "runs-on")),
+			"kind":       "job",
+			"language":   "yaml",
+		})
+		entities = append(entities, jobEntity)
+		relationships = append(relationships, graph.CreateRelationship(
+			fileEntity.ID, jobEntity.ID, graph.RelationshipTypeDefines, nil))
+		jobIDs[jobName] = jobEntity.ID
+
+		// A job's needs is either a single job name or a list of them
+		if needsNode := yamlMappingValue(job, "needs"); needsNode != nil {
+			if needsNode.Kind == yaml.SequenceNode {
+				for _, need := range needsNode.Content {
+					needs[jobName] = append(needs[jobName], need.Value)
+				}
+			} else {
+				needs[jobName] = append(needs[jobName], needsNode.Value)
+			}
+		}
+
+		// Reusable workflows are referenced at job level
+		if uses := yamlMappingValue(job, "uses"); uses != nil {
+			actionEntity := createActionEntity(file, uses)
+			entities = append(entities, actionEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				jobEntity.ID, actionEntity.ID, graph.RelationshipTypeDependsOn, nil))
+		}
+
+		steps := yamlMappingValue(job, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for j, step := range steps.Content {
+			uses := yamlMappingValue(step, "uses")
+
+			stepName := yamlScalar(yamlMappingValue(step, "name"))
+			if stepName == "" && uses != nil {
+				stepName = uses.Value
+			}
+			if stepName == "" {
+				stepName = fmt.Sprintf("%s step %d", jobName, j+1)
+			}
+
+			stepEntity := graph.CreateEntity(stepName, graph.EntityTypeFunction, graph.Properties{
+				"sourceFile": file.Path,
+				"lineNumber": step.Line,
+				"kind":       "step",
+				"job":        jobName,
+				"language":   "yaml",
+			})
+			entities = append(entities, stepEntity)
+			relationships = append(relationships, graph.CreateRelationship(
+				jobEntity.ID, stepEntity.ID, graph.RelationshipTypeContains, nil))
+
+			if uses != nil {
+				actionEntity := createActionEntity(file, uses)
+				entities = append(entities, actionEntity)
+				relationships = append(relationships, graph.CreateRelationship(
+					stepEntity.ID, actionEntity.ID, graph.RelationshipTypeDependsOn, nil))
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobName := jobs.Content[i].Value
+		for _, requiredJob := range needs[jobName] {
+			if requiredID, ok := jobIDs[requiredJob]; ok {
+				relationships = append(relationships, graph.CreateRelationship(
+					jobIDs[jobName], requiredID, graph.RelationshipTypeDependsOn, nil))
+			}
+		}
+	}
+
+	return entities, relationships
+}
+
+// createActionEntity creates a dependency entity for a "uses: owner/repo@version" reference
+func createActionEntity(file graph.CodeFile, uses *yaml.Node) graph.Entity {
+	action, version, _ := strings.Cut(uses.Value, "@")
+	return graph.CreateEntity(action, graph.EntityTypeDependency, graph.Properties{
+		"version":    version,
+		"sourceFile": file.Path,
+		"lineNumber": uses.Line,
+		"type":       "action",
+	})
+}
+
+// splitImageReference splits a container image reference into name and tag or digest,
+// e.g. "nginx:1.25" -> ("nginx", "1.25"), "registry:5000/app" -> ("registry:5000/app", "latest")
+func splitImageReference(image string) (string, string) {
+	if name, digest, found := strings.Cut(image, "@"); found {
+		return name, digest
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		return image[:idx], image[idx+1:]
+	}
+	return image, "latest"
+}
+
+// yamlMappingValue returns the value node for key in a mapping node, or nil
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlScalar returns the value of a scalar node, or "" for missing and non-scalar nodes
+func yamlScalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
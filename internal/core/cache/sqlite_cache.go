@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"codegraphgen/internal/core/graph"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DefaultPath is the location of the cache database relative to the analyzed
+// directory
+const DefaultPath = ".codegraphgen/cache.db"
+
+// formatVersion is part of every file hash, so results cached in an older
+// format are not reused
+const formatVersion = "2"
+
+// analyzerVersion identifies the build of the analyzers, so results cached by
+// another build are not reused after the analyzers' output changes
+var analyzerVersion = sync.OnceValue(buildVersion)
+
+// buildVersion returns the module version and VCS revision the binary was built
+// from, or the hash of the executable when the build has no revision or
+// includes uncommitted changes
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return executableHash()
+	}
+
+	var revision string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				return executableHash()
+			}
+		}
+	}
+	if revision == "" && (info.Main.Version == "" || info.Main.Version == "(devel)") {
+		return executableHash()
+	}
+	return info.Main.Version + "+" + revision
+}
+
+// executableHash returns the SHA-256 of the running executable, or an empty
+// string if it can't be read
+func executableHash() string {
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// sqliteSchema creates the table used by SQLiteCache
+const sqliteSchema = `
+	CREATE TABLE IF NOT EXISTS analysis_cache (
+		file_hash TEXT PRIMARY KEY,
+		entities TEXT NOT NULL,
+		relationships TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	)
+`
+
+// AnalysisCache stores the analysis results of files by the hash of their path
+// and content, so unchanged files don't have to be analyzed again
+type AnalysisCache interface {
+	Get(fileHash string) (*CachedResult, bool)
+	Put(fileHash string, entities []graph.Entity, relationships []graph.Relationship) error
+}
+
+// CachedResult holds the entities and relationships extracted from a file, as
+// stored in the cache in their JSON form. Numeric properties are therefore
+// returned as float64.
+type CachedResult struct {
+	Entities      []graph.Entity       `json:"entities"`
+	Relationships []graph.Relationship `json:"relationships"`
+	CreatedAt     time.Time            `json:"createdAt"`
+}
+
+// SQLiteCache implements AnalysisCache with a SQLite database
+type SQLiteCache struct {
+	db *sql.DB
+}
+
+// FileHash returns the cache key of a file for the running build of the
+// analyzers. The path is part of the key because entity IDs and properties
+// depend on it.
+func FileHash(path, content string) string {
+	hash := sha256.New()
+	for _, part := range []string{formatVersion, analyzerVersion(), path, content} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NewSQLiteCache opens the cache database at path, creating it and its
+// directory if necessary
+func NewSQLiteCache(path string) (*SQLiteCache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+	// SQLite allows a single writer; serialize access from concurrent analyses
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create cache schema: %w", err)
+	}
+
+	return &SQLiteCache{db: db}, nil
+}
+
+// Get returns the cached results for a file hash. Results that can't be read
+// are treated as a cache miss.
+func (c *SQLiteCache) Get(fileHash string) (*CachedResult, bool) {
+	var entitiesJSON, relationshipsJSON string
+	var createdAt int64
+	err := c.db.QueryRow(
+		"SELECT entities, relationships, created_at FROM analysis_cache WHERE file_hash = ?",
+		fileHash,
+	).Scan(&entitiesJSON, &relationshipsJSON, &createdAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("⚠️ Failed to read analysis cache: %v", err)
+		}
+		return nil, false
+	}
+
+	result := &CachedResult{CreatedAt: time.Unix(createdAt, 0)}
+	if err := json.Unmarshal([]byte(entitiesJSON), &result.Entities); err != nil {
+		log.Printf("⚠️ Ignoring invalid cached entities: %v", err)
+		return nil, false
+	}
+	if err := json.Unmarshal([]byte(relationshipsJSON), &result.Relationships); err != nil {
+		log.Printf("⚠️ Ignoring invalid cached relationships: %v", err)
+		return nil, false
+	}
+
+	// Later analysis passes add properties, so they must not be nil
+	for i := range result.Entities {
+		if result.Entities[i].Properties == nil {
+			result.Entities[i].Properties = graph.Properties{}
+		}
+	}
+	for i := range result.Relationships {
+		if result.Relationships[i].Properties == nil {
+			result.Relationships[i].Properties = graph.Properties{}
+		}
+	}
+
+	return result, true
+}
+
+// Put stores the results of a file, replacing any previous results for the hash
+func (c *SQLiteCache) Put(fileHash string, entities []graph.Entity, relationships []graph.Relationship) error {
+	entitiesJSON, err := json.Marshal(entities)
+	if err != nil {
+		return fmt.Errorf("failed to serialize entities: %w", err)
+	}
+	relationshipsJSON, err := json.Marshal(relationships)
+	if err != nil {
+		return fmt.Errorf("failed to serialize relationships: %w", err)
+	}
+
+	_, err = c.db.Exec(
+		"INSERT OR REPLACE INTO analysis_cache (file_hash, entities, relationships, created_at) VALUES (?, ?, ?, ?)",
+		fileHash, string(entitiesJSON), string(relationshipsJSON), time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store analysis results: %w", err)
+	}
+	return nil
+}
+
+// Close closes the cache database
+func (c *SQLiteCache) Close() error {
+	return c.db.Close()
+}
@@ -1,127 +1,486 @@
 package core
 
 import (
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/core/analyzers"
+	"codegraphgen/internal/core/cache"
 	"codegraphgen/internal/core/graph"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ProgressFunc is called by AnalyzeCodebase after each file has been analyzed
+// with the entities and relationships extracted from that file
+type ProgressFunc func(file graph.CodeFile, entities []graph.Entity, relationships []graph.Relationship)
+
+// StartFunc is called with the number of files about to be analyzed, before
+// the first of them is
+type StartFunc func(total int)
+
 // CodeProcessor handles analysis of source code files
 type CodeProcessor struct {
 	*TextProcessor
 	supportedExtensions map[string]bool
 	languageMap         map[string]string
+	fileNameLanguages   map[string]string
 	analyzerRegistry    *AnalyzerRegistry
+	skipDirectories     []string
+	progressFunc        ProgressFunc
+	startFunc           StartFunc
+	analysisCache       cache.AnalysisCache
+
+	// Concurrency is the number of files analyzed in parallel (1 analyzes sequentially)
+	Concurrency int
+
+	// BuildTags, when set, skips Go files whose build constraints aren't satisfied
+	// by these tags, e.g. linux-only files unless "linux" is given
+	BuildTags []string
+
+	// LogFiles prints a line for each file as its analysis starts
+	LogFiles bool
+
+	// TypeScriptPathAliases, when set, resolves the TypeScript imports using the
+	// path aliases of a tsconfig.json. Otherwise the tsconfig.json at the root
+	// of the analyzed directory is used, if there is one.
+	TypeScriptPathAliases *analysis.TypeScriptPathAliases
+
+	// IncludePatterns, when set, only analyzes the files whose slash-separated
+	// path relative to the analyzed directory matches one of these glob
+	// patterns, in which ** matches any number of directories, e.g. **/*.go
+	IncludePatterns []string
+
+	// ExcludePatterns skips the files whose relative path matches one of these
+	// glob patterns, e.g. vendor/**
+	ExcludePatterns []string
+
+	// MaxFileSize, when positive, skips files larger than this many bytes
+	MaxFileSize int64
+
+	// Output receives the messages reporting the analysis, os.Stdout when nil
+	Output io.Writer
+}
+
+// fileResult holds the outcome of analyzing one file
+type fileResult struct {
+	entities      []graph.Entity
+	relationships []graph.Relationship
+	err           error
 }
 
 // NewCodeProcessor creates a new CodeProcessor instance
 func NewCodeProcessor() *CodeProcessor {
 	supportedExtensions := map[string]bool{
-		".ts":   true,
-		".js":   true,
-		".tsx":  true,
-		".jsx":  true,
-		".py":   true,
-		".java": true,
-		".cpp":  true,
-		".c":    true,
-		".h":    true,
-		".hpp":  true,
-		".cs":   true,
-		".go":   true,
-		".rs":   true,
-		".rb":   true,
-		".php":  true,
-		".json": true,
-		".yaml": true,
-		".yml":  true,
-		".xml":  true,
-		".md":   true,
-		".txt":  true,
-		".sql":  true,
+		".ts":       true,
+		".js":       true,
+		".tsx":      true,
+		".jsx":      true,
+		".py":       true,
+		".java":     true,
+		".scala":    true,
+		".cpp":      true,
+		".c":        true,
+		".h":        true,
+		".hpp":      true,
+		".cs":       true,
+		".go":       true,
+		".rs":       true,
+		".rb":       true,
+		".php":      true,
+		".json":     true,
+		".yaml":     true,
+		".yml":      true,
+		".xml":      true,
+		".md":       true,
+		".markdown": true,
+		".txt":      true,
+		".sql":      true,
 	}
 
 	languageMap := map[string]string{
-		".ts":   "typescript",
-		".tsx":  "typescript",
-		".js":   "javascript",
-		".jsx":  "javascript",
-		".py":   "python",
-		".java": "java",
-		".cpp":  "cpp",
-		".c":    "c",
-		".h":    "c",
-		".hpp":  "cpp",
-		".cs":   "csharp",
-		".go":   "go",
-		".rs":   "rust",
-		".rb":   "ruby",
-		".php":  "php",
-		".json": "json",
-		".yaml": "yaml",
-		".yml":  "yaml",
-		".xml":  "xml",
-		".md":   "markdown",
-		".sql":  "sql",
+		".ts":       "typescript",
+		".tsx":      "typescript",
+		".js":       "javascript",
+		".jsx":      "javascript",
+		".py":       "python",
+		".java":     "java",
+		".scala":    "scala",
+		".cpp":      "cpp",
+		".c":        "c",
+		".h":        "c",
+		".hpp":      "cpp",
+		".cs":       "csharp",
+		".go":       "go",
+		".rs":       "rust",
+		".rb":       "ruby",
+		".php":      "php",
+		".json":     "json",
+		".yaml":     "yaml",
+		".yml":      "yaml",
+		".xml":      "xml",
+		".md":       "markdown",
+		".markdown": "markdown",
+		".sql":      "sql",
+	}
+
+	// Files recognized by name rather than extension
+	fileNameLanguages := map[string]string{
+		"Makefile":    "make",
+		"makefile":    "make",
+		"GNUmakefile": "make",
+		"Gemfile":     "bundler",
+
+		"requirements.txt":     "pip",
+		"requirements-dev.txt": "pip",
+		"Pipfile":              "pip",
+		"pyproject.toml":       "pip",
+
+		"Cargo.toml": "cargo",
+
+		"Dockerfile":    "docker",
+		"Containerfile": "docker",
 	}
 
 	return &CodeProcessor{
 		TextProcessor:       NewTextProcessor(),
 		supportedExtensions: supportedExtensions,
 		languageMap:         languageMap,
+		fileNameLanguages:   fileNameLanguages,
 		analyzerRegistry:    NewAnalyzerRegistry(),
+		skipDirectories:     append([]string(nil), DefaultSkipDirectories...),
+		Concurrency:         1,
+		LogFiles:            true,
+	}
+}
+
+// Languages returns the sorted languages files are detected as, by extension
+// or by name
+func (cp *CodeProcessor) Languages() []string {
+	seen := make(map[string]bool)
+	var languages []string
+	for _, mapping := range []map[string]string{cp.languageMap, cp.fileNameLanguages} {
+		for _, language := range mapping {
+			if !seen[language] {
+				seen[language] = true
+				languages = append(languages, language)
+			}
+		}
 	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Clone returns a processor with the same options, whose progress callbacks and
+// skipped directories can be changed without affecting cp
+func (cp *CodeProcessor) Clone() *CodeProcessor {
+	clone := *cp
+	clone.skipDirectories = append([]string(nil), cp.skipDirectories...)
+	return &clone
+}
+
+// SetProgressFunc registers a callback invoked after each file is analyzed
+func (cp *CodeProcessor) SetProgressFunc(fn ProgressFunc) {
+	cp.progressFunc = fn
+}
+
+// SetStartFunc registers a callback invoked before the files are analyzed
+func (cp *CodeProcessor) SetStartFunc(fn StartFunc) {
+	cp.startFunc = fn
+}
+
+// WithAnalysisCache makes the processor reuse the results of files found in the
+// cache instead of analyzing them, and store the results of analyzed files
+func (cp *CodeProcessor) WithAnalysisCache(analysisCache cache.AnalysisCache) *CodeProcessor {
+	cp.analysisCache = analysisCache
+	return cp
+}
+
+// AnalyzerRegistry returns the registry used to pick an analyzer for each file,
+// for replacing built-in analyzers
+func (cp *CodeProcessor) AnalyzerRegistry() *AnalyzerRegistry {
+	return cp.analyzerRegistry
+}
+
+// ScanCodebase returns the files under rootPath that AnalyzeCodebase would analyze,
+// without analyzing them
+func (cp *CodeProcessor) ScanCodebase(rootPath string) ([]graph.CodeFile, error) {
+	files, err := cp.scanDirectory(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+	return files, nil
 }
 
 // AnalyzeCodebase analyzes an entire codebase directory
 func (cp *CodeProcessor) AnalyzeCodebase(rootPath string) ([]graph.Entity, []graph.Relationship, error) {
-	fmt.Printf("🔍 Analyzing codebase at: %s\n", rootPath)
+	fmt.Fprintf(cp.output(), "🔍 Analyzing codebase at: %s\n", rootPath)
 
 	files, err := cp.scanDirectory(rootPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to scan directory: %w", err)
 	}
 
+	allEntities, allRelationships := cp.analyzeCodeFiles(files, rootPath)
+	return allEntities, allRelationships, nil
+}
+
+// AnalyzeFiles analyzes only the given files of the codebase at rootPath, e.g. the
+// files changed since a commit. Files that the codebase scan would skip are
+// ignored. Cross-file relationships such as imports and tests are only created
+// between the given files.
+func (cp *CodeProcessor) AnalyzeFiles(rootPath string, paths []string) ([]graph.Entity, []graph.Relationship, error) {
+	fmt.Fprintf(cp.output(), "🔍 Analyzing %d files of codebase at: %s\n", len(paths), rootPath)
+
+	var files []graph.CodeFile
+	for _, path := range paths {
+		if !cp.isScannedFile(rootPath, path) {
+			log.Printf("⏭️ Skipping file: %s", path)
+			continue
+		}
+		file, err := cp.createCodeFile(path)
+		if err != nil {
+			log.Printf("⚠️ Failed to read file %s: %v", path, err)
+			continue
+		}
+		files = append(files, *file)
+	}
+
+	allEntities, allRelationships := cp.analyzeCodeFiles(files, rootPath)
+	return allEntities, allRelationships, nil
+}
+
+// isScannedFile reports whether scanDirectory would analyze the file at path
+func (cp *CodeProcessor) isScannedFile(rootPath, path string) bool {
+	name := filepath.Base(path)
+	if !cp.supportedExtensions[strings.ToLower(filepath.Ext(path))] && cp.FileNameLanguage(name) == "" {
+		return false
+	}
+
+	relPath, err := filepath.Rel(rootPath, filepath.Dir(path))
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return false
+	}
+	for _, dir := range strings.Split(relPath, string(filepath.Separator)) {
+		if cp.shouldSkipDirectory(dir) {
+			return false
+		}
+	}
+	if !cp.matchesFilePatterns(relativeSlashPath(rootPath, path)) {
+		return false
+	}
+	if info, err := os.Stat(path); err == nil && cp.MaxFileSize > 0 && info.Size() > cp.MaxFileSize {
+		return false
+	}
+	return true
+}
+
+// analyzeCodeFiles analyzes the files of the codebase at rootPath and links them
+func (cp *CodeProcessor) analyzeCodeFiles(files []graph.CodeFile, rootPath string) ([]graph.Entity, []graph.Relationship) {
 	var allEntities []graph.Entity
 	var allRelationships []graph.Relationship
 
+	// Leave out Go files that aren't compiled with the configured build tags
+	if len(cp.BuildTags) > 0 {
+		var matched []graph.CodeFile
+		for _, file := range files {
+			if cp.matchesBuildTags(file) {
+				matched = append(matched, file)
+			}
+		}
+		if skipped := len(files) - len(matched); skipped > 0 {
+			fmt.Fprintf(cp.output(), "⏭️ Skipped %d Go files excluded by build tags %s\n", skipped, strings.Join(cp.BuildTags, ","))
+		}
+		files = matched
+	}
+
 	// Create directory structure entities
 	directories := cp.extractDirectories(files)
 	for _, dir := range directories {
 		allEntities = append(allEntities, cp.createDirectoryEntity(dir, rootPath))
 	}
 
-	// Process each file
-	for _, file := range files {
-		fmt.Printf("📄 Processing: %s\n", file.Path)
-
-		entities, relationships, err := cp.analyzeFile(file)
-		if err != nil {
-			log.Printf("⚠️ Failed to process %s: %v", file.Path, err)
+	// Process each file; results are combined in scan order regardless of concurrency
+	results := cp.analyzeFiles(files)
+	for i, file := range files {
+		result := results[i]
+		if result.err != nil {
+			log.Printf("⚠️ Failed to process %s: %v", file.Path, result.err)
 			continue
 		}
 
-		allEntities = append(allEntities, entities...)
-		allRelationships = append(allRelationships, relationships...)
+		allEntities = append(allEntities, result.entities...)
+		allRelationships = append(allRelationships, result.relationships...)
 
 		// Create file-to-directory relationships
 		fileRelationships := cp.createFileDirectoryRelationships(file, allEntities)
 		allRelationships = append(allRelationships, fileRelationships...)
 	}
 
+	// Link relative TypeScript/JavaScript imports to the files they load
+	if aliases := cp.typeScriptPathAliases(rootPath); aliases != nil {
+		analysis.ApplyTypeScriptPathAliases(allEntities, aliases, rootPath)
+	}
+	allRelationships = append(allRelationships, analysis.ResolveTypeScriptImports(allEntities, rootPath)...)
+
+	// Link re-exporting TypeScript/JavaScript files to what they re-export
+	allRelationships = append(allRelationships, analysis.LinkTypeScriptReExports(allEntities, rootPath)...)
+
+	// Merge imports and dependencies repeated across files into single entities
+	allEntities, idMap := analysis.DeduplicateByLabelAndType(allEntities)
+	allRelationships = analysis.RemapRelationships(allRelationships, idMap)
+
 	// Create import/dependency relationships
 	importRelationships := cp.createImportRelationships(allEntities)
 	allRelationships = append(allRelationships, importRelationships...)
 
-	fmt.Printf("✅ Analyzed %d files, found %d entities and %d relationships\n",
+	// Link test functions to the code they test
+	allRelationships = append(allRelationships, analysis.LinkTestsToCode(allEntities)...)
+
+	// Extract the cases of table-driven tests
+	caseEntities, caseRelationships := analysis.ExtractGoTestCases(files, allEntities)
+	allEntities = append(allEntities, caseEntities...)
+	allRelationships = append(allRelationships, caseRelationships...)
+
+	// Link functions to the types they return
+	allRelationships = append(allRelationships, analysis.LinkReturnTypes(allEntities)...)
+
+	// Link Scala classes, traits and objects to their parents and mixins
+	allRelationships = append(allRelationships, analysis.LinkScalaSupertypes(allEntities)...)
+
+	// Link Rust types to the traits they implement and their impl methods
+	rustEntities, rustRelationships := analysis.LinkRustImplementations(allEntities)
+	allEntities = append(allEntities, rustEntities...)
+	allRelationships = append(allRelationships, rustRelationships...)
+
+	// Link Java method references (Type::method) to the referenced methods
+	allRelationships = append(allRelationships, analysis.LinkJavaMethodReferences(allEntities)...)
+
+	// Link namespaces such as composer.json PSR-4 prefixes to their directories
+	allRelationships = append(allRelationships, analysis.LinkNamespaceDirectories(allEntities)...)
+
+	// Make Python package boundaries explicit
+	packageEntities, packageRelationships := analysis.LinkPythonPackages(allEntities)
+	allEntities = append(allEntities, packageEntities...)
+	allRelationships = append(allRelationships, packageRelationships...)
+
+	// Link Python packages to the dependencies of their requirements files
+	allRelationships = append(allRelationships, analysis.LinkPythonDependencies(allEntities)...)
+
+	// Link Kubernetes workloads to their services and config maps, and group
+	// resources by namespace
+	kubernetesEntities, kubernetesRelationships := analysis.LinkKubernetesResources(allEntities)
+	allEntities = append(allEntities, kubernetesEntities...)
+	allRelationships = append(allRelationships, kubernetesRelationships...)
+
+	fmt.Fprintf(cp.output(), "✅ Analyzed %d files, found %d entities and %d relationships\n",
 		len(files), len(allEntities), len(allRelationships))
 
-	return allEntities, allRelationships, nil
+	return allEntities, allRelationships
+}
+
+// output returns the writer receiving the messages reporting the analysis
+func (cp *CodeProcessor) output() io.Writer {
+	if cp.Output == nil {
+		return os.Stdout
+	}
+	return cp.Output
+}
+
+// typeScriptPathAliases returns the path aliases set on the processor, or else
+// those of the tsconfig.json at rootPath, or nil if neither exists
+func (cp *CodeProcessor) typeScriptPathAliases(rootPath string) *analysis.TypeScriptPathAliases {
+	if cp.TypeScriptPathAliases != nil {
+		return cp.TypeScriptPathAliases
+	}
+
+	tsconfigPath := filepath.Join(rootPath, "tsconfig.json")
+	if _, err := os.Stat(tsconfigPath); err != nil {
+		return nil
+	}
+	aliases, err := analysis.LoadTypeScriptPathAliases(tsconfigPath)
+	if err != nil {
+		log.Printf("⚠️ Ignoring TypeScript path aliases: %v", err)
+		return nil
+	}
+	return aliases
+}
+
+// analyzeFiles analyzes files using a pool of cp.Concurrency workers and returns
+// the results in the order of files. The progress callback is only ever invoked
+// from the calling goroutine.
+func (cp *CodeProcessor) analyzeFiles(files []graph.CodeFile) []fileResult {
+	results := make([]fileResult, len(files))
+	if cp.startFunc != nil {
+		cp.startFunc(len(files))
+	}
+
+	analyze := func(i int) fileResult {
+		if cp.LogFiles {
+			fmt.Fprintf(cp.output(), "📄 Processing: %s\n", files[i].Path)
+		}
+		entities, relationships, err := cp.analyzeFile(files[i])
+		return fileResult{entities: entities, relationships: relationships, err: err}
+	}
+	report := func(i int) {
+		if cp.progressFunc != nil && results[i].err == nil {
+			cp.progressFunc(files[i], results[i].entities, results[i].relationships)
+		}
+	}
+
+	workers := cp.Concurrency
+	if workers > len(files) {
+		workers = len(files)
+	}
+	if workers <= 1 {
+		for i := range files {
+			results[i] = analyze(i)
+			report(i)
+		}
+		return results
+	}
+
+	type indexedResult struct {
+		index  int
+		result fileResult
+	}
+
+	jobs := make(chan int)
+	done := make(chan indexedResult)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				done <- indexedResult{index: i, result: analyze(i)}
+			}
+		}()
+	}
+
+	go func() {
+		for i := range files {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	for r := range done {
+		results[r.index] = r.result
+		report(r.index)
+	}
+
+	return results
 }
 
 // scanDirectory recursively scans a directory for code files
@@ -136,7 +495,7 @@ func (cp *CodeProcessor) scanDirectory(dirPath string) ([]graph.CodeFile, error)
 		if d.IsDir() {
 			// Skip common directories that shouldn't be analyzed
 			// But don't skip the root directory even if it's "."
-			if path != dirPath && cp.shouldSkipDirectory(d.Name()) {
+			if path != dirPath && (cp.shouldSkipDirectory(d.Name()) || cp.isExcludedDirectory(relativeSlashPath(dirPath, path))) {
 				log.Printf("⏭️ Skipping directory: %s", path)
 				return filepath.SkipDir
 			}
@@ -145,7 +504,15 @@ func (cp *CodeProcessor) scanDirectory(dirPath string) ([]graph.CodeFile, error)
 
 		ext := strings.ToLower(filepath.Ext(path))
 		log.Printf("🔍 Checking file: %s (ext: %s)", path, ext)
-		if cp.supportedExtensions[ext] {
+		if !cp.matchesFilePatterns(relativeSlashPath(dirPath, path)) {
+			log.Printf("⏭️ Skipping file not matching the include and exclude patterns: %s", path)
+			return nil
+		}
+		if cp.exceedsMaxFileSize(d) {
+			log.Printf("⏭️ Skipping file larger than %d bytes: %s", cp.MaxFileSize, path)
+			return nil
+		}
+		if cp.supportedExtensions[ext] || cp.FileNameLanguage(d.Name()) != "" {
 			log.Printf("✅ Processing supported file: %s", path)
 			file, err := cp.createCodeFile(path)
 			if err != nil {
@@ -166,6 +533,42 @@ func (cp *CodeProcessor) scanDirectory(dirPath string) ([]graph.CodeFile, error)
 	return files, err
 }
 
+// DefaultSkipDirectories are the names of the build, dependency and tool
+// directories not scanned by default
+var DefaultSkipDirectories = []string{
+	"node_modules",
+	".git",
+	".svn",
+	"dist",
+	"build",
+	"out",
+	"target",
+	"bin",
+	"obj",
+	".vscode",
+	".idea",
+	"__pycache__",
+	"coverage",
+	".nyc_output",
+	"tmp",
+	"temp",
+	"logs",
+	"vendor", // Go vendor directory
+}
+
+// SetSkipDirectories replaces the directories not scanned. Each entry is a
+// directory name or a glob pattern matched against directory names, such as
+// "generated-*".
+func (cp *CodeProcessor) SetSkipDirectories(dirs []string) {
+	cp.skipDirectories = append([]string(nil), dirs...)
+}
+
+// AddSkipDirectories adds directory names or glob patterns to the directories
+// not scanned
+func (cp *CodeProcessor) AddSkipDirectories(dirs ...string) {
+	cp.skipDirectories = append(cp.skipDirectories, dirs...)
+}
+
 // shouldSkipDirectory determines if a directory should be skipped
 func (cp *CodeProcessor) shouldSkipDirectory(dirName string) bool {
 	// Don't skip current directory
@@ -173,28 +576,104 @@ func (cp *CodeProcessor) shouldSkipDirectory(dirName string) bool {
 		return false
 	}
 
-	skipDirs := map[string]bool{
-		"node_modules": true,
-		".git":         true,
-		".svn":         true,
-		"dist":         true,
-		"build":        true,
-		"out":          true,
-		"target":       true,
-		"bin":          true,
-		"obj":          true,
-		".vscode":      true,
-		".idea":        true,
-		"__pycache__":  true,
-		"coverage":     true,
-		".nyc_output":  true,
-		"tmp":          true,
-		"temp":         true,
-		"logs":         true,
-		"vendor":       true, // Go vendor directory
+	for _, pattern := range cp.skipDirectories {
+		if matched, err := filepath.Match(pattern, dirName); pattern == dirName || err == nil && matched {
+			return true
+		}
+	}
+
+	// Hidden directories are skipped, except .github which holds CI workflows
+	return strings.HasPrefix(dirName, ".") && dirName != ".github"
+}
+
+// matchesFilePatterns reports whether a file, by its slash-separated path
+// relative to the analyzed directory, matches an include pattern, if any are
+// set, and no exclude pattern
+func (cp *CodeProcessor) matchesFilePatterns(relPath string) bool {
+	for _, pattern := range cp.ExcludePatterns {
+		if matchPathPattern(pattern, relPath) {
+			return false
+		}
+	}
+	if len(cp.IncludePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range cp.IncludePatterns {
+		if matchPathPattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExcludedDirectory reports whether an exclude pattern like vendor/** covers
+// everything under a directory, so that it needn't be walked
+func (cp *CodeProcessor) isExcludedDirectory(relPath string) bool {
+	for _, pattern := range cp.ExcludePatterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok && matchPathPattern(prefix, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// exceedsMaxFileSize reports whether a file is larger than MaxFileSize
+func (cp *CodeProcessor) exceedsMaxFileSize(d fs.DirEntry) bool {
+	if cp.MaxFileSize <= 0 {
+		return false
+	}
+	info, err := d.Info()
+	return err == nil && info.Size() > cp.MaxFileSize
+}
+
+// relativeSlashPath returns path relative to root with forward slashes
+func relativeSlashPath(root, path string) string {
+	relPath, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// matchPathPattern matches a slash-separated path against a glob pattern. Each
+// segment of the pattern is matched with path.Match against a segment of the
+// path, except **, which matches any number of segments.
+func matchPathPattern(pattern, name string) bool {
+	return matchPathSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchPathSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPathSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
 	}
+	return len(name) == 0
+}
 
-	return skipDirs[dirName] || strings.HasPrefix(dirName, ".")
+// FileNameLanguage returns the language of a file recognized by its name
+// rather than its extension, or "" for other files. Dockerfile variants such as
+// Dockerfile.dev and api.Dockerfile are recognized as well.
+func (cp *CodeProcessor) FileNameLanguage(name string) string {
+	if language := cp.fileNameLanguages[name]; language != "" {
+		return language
+	}
+	if analyzers.IsDockerfile(name) {
+		return "docker"
+	}
+	return ""
 }
 
 // createCodeFile creates a graph.CodeFile from a file path
@@ -210,7 +689,10 @@ func (cp *CodeProcessor) createCodeFile(filePath string) (*graph.CodeFile, error
 	}
 
 	ext := strings.ToLower(filepath.Ext(filePath))
-	language := cp.languageMap[ext]
+	language := cp.FileNameLanguage(filepath.Base(filePath))
+	if language == "" {
+		language = cp.languageMap[ext]
+	}
 	if language == "" {
 		language = "unknown"
 	}
@@ -265,10 +747,70 @@ func (cp *CodeProcessor) createDirectoryEntity(dirPath, rootPath string) graph.E
 
 // analyzeFile analyzes a single code file
 func (cp *CodeProcessor) analyzeFile(file graph.CodeFile) ([]graph.Entity, []graph.Relationship, error) {
+	var fileHash string
+	if cp.analysisCache != nil {
+		fileHash = cache.FileHash(file.Path, cp.cacheContent(file))
+		if cached, ok := cp.analysisCache.Get(fileHash); ok {
+			return cached.Entities, cached.Relationships, nil
+		}
+	}
+
 	fileEntity := cp.createFileEntity(file)
 
 	analyzer := cp.analyzerRegistry.GetAnalyzer(file.Language)
-	return analyzer.Analyze(file, fileEntity)
+	entities, relationships, err := analyzer.Analyze(file, fileEntity)
+	if err != nil || cp.analysisCache == nil {
+		return entities, relationships, err
+	}
+
+	if err := cp.analysisCache.Put(fileHash, entities, relationships); err != nil {
+		log.Printf("⚠️ Failed to cache results of %s: %v", file.Path, err)
+	}
+	return entities, relationships, nil
+}
+
+// cacheContent returns the content the cached results of a file depend on: its
+// own content, plus the lockfile read along with a Gemfile
+func (cp *CodeProcessor) cacheContent(file graph.CodeFile) string {
+	if !analyzers.IsGemfile(file.Name) {
+		return file.Content
+	}
+	lock, _ := os.ReadFile(filepath.Join(filepath.Dir(file.Path), "Gemfile.lock"))
+	return file.Content + "\x00" + string(lock)
+}
+
+// goImpliedTags are GOOS values that also satisfy the tag of another GOOS
+var goImpliedTags = map[string]string{"android": "linux", "illumos": "solaris", "ios": "darwin"}
+
+// matchesBuildTags reports whether a file is compiled with the configured build
+// tags, by the _GOOS and _GOARCH suffixes of its name and its build constraint.
+// Files other than Go files, files without either and all files when no build
+// tags are configured match.
+func (cp *CodeProcessor) matchesBuildTags(file graph.CodeFile) bool {
+	if len(cp.BuildTags) == 0 || file.Language != "go" {
+		return true
+	}
+
+	hasTag := func(tag string) bool {
+		for _, buildTag := range cp.BuildTags {
+			if buildTag == tag || goImpliedTags[buildTag] == tag {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, tag := range analyzers.GoFileNameTags(file.Path) {
+		if !hasTag(tag) {
+			return false
+		}
+	}
+
+	expr, err := analyzers.ParseGoBuildConstraint(file.Content)
+	if err != nil || expr == nil {
+		return true
+	}
+	return expr.Eval(hasTag)
 }
 
 // createFileEntity creates an entity for a file
@@ -368,9 +910,12 @@ func (cp *CodeProcessor) ProcessSingleFile(filePath string) ([]graph.Entity, []g
 		return nil, nil, fmt.Errorf("failed to get file info for %s: %w", filePath, err)
 	}
 
-	// Determine language from extension
+	// Determine language from file name or extension
 	ext := strings.ToLower(filepath.Ext(filePath))
-	language := cp.languageMap[ext]
+	language := cp.FileNameLanguage(filepath.Base(filePath))
+	if language == "" {
+		language = cp.languageMap[ext]
+	}
 	if language == "" {
 		language = "unknown"
 	}
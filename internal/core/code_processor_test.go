@@ -0,0 +1,210 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"codegraphgen/internal/core/graph"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "**/*.go", path: "main.go", want: true},
+		{pattern: "**/*.go", path: "cmd/server/main.go", want: true},
+		{pattern: "**/*.go", path: "main.ts", want: false},
+		{pattern: "vendor/**", path: "vendor/github.com/pkg/errors/errors.go", want: true},
+		{pattern: "vendor/**", path: "internal/vendor/a.go", want: false},
+		{pattern: "*.go", path: "cmd/main.go", want: false},
+		{pattern: "cmd/*/main.go", path: "cmd/server/main.go", want: true},
+		{pattern: "**/testdata/**", path: "pkg/testdata/input.json", want: true},
+	}
+	for _, tt := range tests {
+		if got := matchPathPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchPathPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestScanCodebaseAppliesFilePatternsAndSize(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":            "package main",
+		"web/app.ts":         "export const app = 1",
+		"vendor/lib/lib.go":  "package lib",
+		"generated/large.go": "package generated\n" + strings.Repeat("// padding\n", 200),
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	processor := NewCodeProcessor()
+	processor.SetSkipDirectories(nil)
+	processor.IncludePatterns = []string{"**/*.go"}
+	processor.ExcludePatterns = []string{"vendor/**"}
+	processor.MaxFileSize = 1024
+
+	scanned, err := processor.ScanCodebase(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, file := range scanned {
+		got = append(got, relativeSlashPath(root, file.Path))
+	}
+	if want := []string{"main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scanned %v, want %v", got, want)
+	}
+
+	if !processor.isScannedFile(root, filepath.Join(root, "main.go")) {
+		t.Error("isScannedFile(main.go) = false, want true")
+	}
+	for _, name := range []string{"web/app.ts", "vendor/lib/lib.go", "generated/large.go"} {
+		if processor.isScannedFile(root, filepath.Join(root, filepath.FromSlash(name))) {
+			t.Errorf("isScannedFile(%s) = true, want false", name)
+		}
+	}
+}
+
+func TestMatchesBuildTags(t *testing.T) {
+	processor := NewCodeProcessor()
+	processor.BuildTags = []string{"linux", "amd64"}
+
+	tests := []struct {
+		path    string
+		content string
+		want    bool
+	}{
+		{path: "main.go", content: "package main", want: true},
+		{path: "poll_linux.go", content: "package poll", want: true},
+		{path: "poll_windows.go", content: "package poll", want: false},
+		{path: "poll_linux_amd64.go", content: "package poll", want: true},
+		{path: "poll_linux_arm64.go", content: "package poll", want: false},
+		{path: "poll_arm64_test.go", content: "package poll", want: false},
+		{path: "windows.go", content: "package poll", want: true},
+		{path: "poll_unknown.go", content: "package poll", want: true},
+		{path: "poll.go", content: "//go:build windows\n\npackage poll", want: false},
+		{path: "poll_linux.go", content: "//go:build !amd64\n\npackage poll", want: false},
+	}
+	for _, tt := range tests {
+		file := graph.CodeFile{Path: tt.path, Content: tt.content, Language: "go"}
+		if got := processor.matchesBuildTags(file); got != tt.want {
+			t.Errorf("matchesBuildTags(%s, %q) = %v, want %v", tt.path, tt.content, got, tt.want)
+		}
+	}
+}
+
+// writeBenchmarkCodebase writes a codebase of Go, TypeScript and Python files
+// calling each other to analyze in benchmarks
+func writeBenchmarkCodebase(b *testing.B, files int) string {
+	b.Helper()
+
+	root := b.TempDir()
+	for i := 0; i < files; i++ {
+		var name, content string
+		switch i % 3 {
+		case 0:
+			name = fmt.Sprintf("pkg/service%d/service.go", i)
+			content = fmt.Sprintf(`package service%d
+
+import "fmt"
+
+type Service struct {
+	Name string
+}
+
+func (s *Service) Run(n int) error {
+	for i := 0; i < n; i++ {
+		if err := s.step(i); err != nil {
+			return fmt.Errorf("step %%d: %%w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) step(i int) error {
+	fmt.Println(s.Name, i)
+	return nil
+}
+`, i)
+		case 1:
+			name = fmt.Sprintf("web/component%d.ts", i)
+			content = fmt.Sprintf(`import { render } from './render';
+
+export interface Props%d {
+  title: string;
+}
+
+export class Component%d {
+  constructor(private props: Props%d) {}
+
+  render(): string {
+    return render(this.props.title);
+  }
+}
+`, i, i, i)
+		default:
+			name = fmt.Sprintf("jobs/job%d.py", i)
+			content = fmt.Sprintf(`import os
+
+
+class Job%d:
+    def __init__(self, name):
+        self.name = name
+
+    def run(self):
+        return self.process(os.getcwd())
+
+    def process(self, path):
+        return [entry for entry in os.listdir(path)]
+`, i)
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return root
+}
+
+func BenchmarkAnalyzeCodebase(b *testing.B) {
+	root := writeBenchmarkCodebase(b, 300)
+
+	// Scanning logs every file
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			processor := NewCodeProcessor()
+			processor.Concurrency = workers
+			processor.LogFiles = false
+			processor.Output = io.Discard
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := processor.AnalyzeCodebase(root); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// FileOutputter writes the analysis result of each file to its own JSON file.
+// Its Write method matches ProgressFunc so it can be registered with
+// CodeProcessor.SetProgressFunc to stream results while a codebase is analyzed.
+type FileOutputter struct {
+	outputDir string
+	rootPath  string
+	written   int
+}
+
+// NewFileOutputter creates a FileOutputter writing below outputDir.
+// Output paths mirror the layout of the files relative to rootPath.
+func NewFileOutputter(outputDir, rootPath string) (*FileOutputter, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+
+	return &FileOutputter{
+		outputDir: outputDir,
+		rootPath:  rootPath,
+	}, nil
+}
+
+// Write writes <filename>.graph.json containing the entities and relationships of a file
+func (fo *FileOutputter) Write(file graph.CodeFile, entities []graph.Entity, relationships []graph.Relationship) {
+	outputPath := fo.outputPath(file)
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		log.Printf("⚠️ Failed to create output directory for %s: %v", file.Path, err)
+		return
+	}
+
+	data, err := json.MarshalIndent(graph.KnowledgeGraph{
+		Entities:      entities,
+		Relationships: relationships,
+	}, "", "  ")
+	if err != nil {
+		log.Printf("⚠️ Failed to marshal analysis result for %s: %v", file.Path, err)
+		return
+	}
+
+	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+		log.Printf("⚠️ Failed to write %s: %v", outputPath, err)
+		return
+	}
+
+	fo.written++
+}
+
+// Written returns the number of result files written so far
+func (fo *FileOutputter) Written() int {
+	return fo.written
+}
+
+// outputPath returns the output file path for a source file
+func (fo *FileOutputter) outputPath(file graph.CodeFile) string {
+	relativePath, err := filepath.Rel(fo.rootPath, file.Path)
+	if err != nil || relativePath == "." {
+		relativePath = file.Name
+	}
+	return filepath.Join(fo.outputDir, relativePath+".graph.json")
+}
@@ -81,6 +81,169 @@ type KnowledgeGraph struct {
 	Relationships []Relationship `json:"relationships"`
 }
 
+// Clone returns a deep copy of the graph: its entities and relationships and
+// their properties, including nested maps and slices, can be changed without
+// affecting the original
+func (kg *KnowledgeGraph) Clone() *KnowledgeGraph {
+	clone := &KnowledgeGraph{
+		Entities:      make([]Entity, len(kg.Entities)),
+		Relationships: make([]Relationship, len(kg.Relationships)),
+	}
+	for i, entity := range kg.Entities {
+		entity.Properties = cloneProperties(entity.Properties)
+		clone.Entities[i] = entity
+	}
+	for i, rel := range kg.Relationships {
+		rel.Properties = cloneProperties(rel.Properties)
+		clone.Relationships[i] = rel
+	}
+	return clone
+}
+
+// EntityIndex returns the entities of the graph by ID. The pointers refer to
+// the elements of Entities, so changes through them are made to the graph.
+// When entities share an ID, the first is indexed.
+func (kg *KnowledgeGraph) EntityIndex() map[string]*Entity {
+	index := make(map[string]*Entity, len(kg.Entities))
+	for i := range kg.Entities {
+		if _, exists := index[kg.Entities[i].ID]; !exists {
+			index[kg.Entities[i].ID] = &kg.Entities[i]
+		}
+	}
+	return index
+}
+
+// cloneProperties deep copies a property map
+func cloneProperties(properties Properties) Properties {
+	if properties == nil {
+		return nil
+	}
+	clone := make(Properties, len(properties))
+	for key, value := range properties {
+		clone[key] = clonePropertyValue(value)
+	}
+	return clone
+}
+
+// clonePropertyValue deep copies the maps and slices property values are made
+// of; other values are immutable and returned as is
+func clonePropertyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Properties:
+		return cloneProperties(v)
+	case map[string]interface{}:
+		return map[string]interface{}(cloneProperties(v))
+	case map[string]string:
+		clone := make(map[string]string, len(v))
+		for key, item := range v {
+			clone[key] = item
+		}
+		return clone
+	case map[string]int:
+		clone := make(map[string]int, len(v))
+		for key, item := range v {
+			clone[key] = item
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = clonePropertyValue(item)
+		}
+		return clone
+	case []map[string]interface{}:
+		clone := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneProperties(item)
+		}
+		return clone
+	case []string:
+		return append([]string(nil), v...)
+	case []int:
+		return append([]int(nil), v...)
+	case []float64:
+		return append([]float64(nil), v...)
+	}
+	return value
+}
+
+// FilterByConfidence returns the entities and relationships with a confidence of at
+// least minConfidence. Relationships to entities that were filtered out are dropped too.
+func (kg *KnowledgeGraph) FilterByConfidence(minConfidence float64) *KnowledgeGraph {
+	result := &KnowledgeGraph{
+		Entities:      []Entity{},
+		Relationships: []Relationship{},
+	}
+
+	kept := make(map[string]bool, len(kg.Entities))
+	for _, entity := range kg.Entities {
+		if entity.Confidence >= minConfidence {
+			result.Entities = append(result.Entities, entity)
+			kept[entity.ID] = true
+		}
+	}
+
+	for _, rel := range kg.Relationships {
+		if rel.Confidence >= minConfidence && kept[rel.Source] && kept[rel.Target] {
+			result.Relationships = append(result.Relationships, rel)
+		}
+	}
+
+	return result
+}
+
+// Subgraph returns the ego network of an entity: every entity reachable within
+// depth hops (following relationships in either direction) and the relationships
+// between them. An unknown entity ID yields an empty graph.
+func (kg *KnowledgeGraph) Subgraph(entityID string, depth int) *KnowledgeGraph {
+	result := &KnowledgeGraph{
+		Entities:      []Entity{},
+		Relationships: []Relationship{},
+	}
+
+	if _, exists := kg.EntityIndex()[entityID]; !exists {
+		return result
+	}
+
+	// Build an undirected adjacency list
+	neighbors := make(map[string][]string)
+	for _, rel := range kg.Relationships {
+		neighbors[rel.Source] = append(neighbors[rel.Source], rel.Target)
+		neighbors[rel.Target] = append(neighbors[rel.Target], rel.Source)
+	}
+
+	// Breadth-first search up to depth hops
+	visited := map[string]bool{entityID: true}
+	frontier := []string{entityID}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, id := range frontier {
+			for _, neighbor := range neighbors[id] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	added := make(map[string]bool)
+	for _, entity := range kg.Entities {
+		if visited[entity.ID] && !added[entity.ID] {
+			added[entity.ID] = true
+			result.Entities = append(result.Entities, entity)
+		}
+	}
+	for _, rel := range kg.Relationships {
+		if visited[rel.Source] && visited[rel.Target] {
+			result.Relationships = append(result.Relationships, rel)
+		}
+	}
+
+	return result
+}
+
 // CodeFile represents a source code file
 type CodeFile struct {
 	Path         string    `json:"path"`
@@ -117,6 +280,22 @@ type CodebaseAnalysis struct {
 	FileTypes         map[string]int    `json:"fileTypes"`
 	ComplexityMetrics ComplexityMetrics `json:"complexityMetrics"`
 	DependencyGraph   DependencyGraph   `json:"dependencyGraph"`
+	LanguageReports   []LanguageReport  `json:"languageReports"`
+}
+
+// LanguageReport represents the statistics of one language of a codebase
+type LanguageReport struct {
+	Language       string            `json:"language"`
+	Files          int               `json:"files"`
+	Lines          int               `json:"lines"`
+	Entities       int               `json:"entities"`
+	TopEntityTypes []EntityTypeCount `json:"topEntityTypes"`
+}
+
+// EntityTypeCount represents the number of entities of a type
+type EntityTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
 }
 
 // ComplexityMetrics represents code complexity metrics
@@ -177,9 +356,7 @@ func generateDeterministicID(entityType EntityType, label string, properties Pro
 
 // generateDeterministicRelationshipID generates a stable ID for relationships
 func generateDeterministicRelationshipID(sourceID, targetID string, relType RelationshipType) string {
-	key := fmt.Sprintf("%s|%s|%s", sourceID, string(relType), targetID)
-	// hash := sha256.Sum256([]byte(key))
-	return fmt.Sprintf("%x", key)
+	return db.RelationshipID(sourceID, targetID, relType)
 }
 
 // graph.CreateEntity creates a new entity with a deterministic ID
@@ -215,8 +392,26 @@ func CreateRelationship(source, target string, relType RelationshipType, propert
 
 // GraphStatistics represents statistics about the knowledge graph
 type GraphStatistics struct {
-	TotalEntities       int            `json:"totalEntities"`
-	TotalRelationships  int            `json:"totalRelationships"`
-	EntitiesByType      map[string]int `json:"entitiesByType"`
-	RelationshipsByType map[string]int `json:"relationshipsByType"`
+	TotalEntities       int              `json:"totalEntities"`
+	TotalRelationships  int              `json:"totalRelationships"`
+	EntitiesByType      map[string]int   `json:"entitiesByType"`
+	RelationshipsByType map[string]int   `json:"relationshipsByType"`
+	EntitiesByLanguage  map[string]int   `json:"entitiesByLanguage"`
+	Languages           []LanguageReport `json:"languages"`
+
+	// AverageDegree is the average number of relationships per entity,
+	// 2 * relationships / entities
+	AverageDegree float64 `json:"averageDegree"`
+	// Density is the fraction of the possible directed relationships between
+	// distinct entities that exist, relationships / (entities * (entities - 1))
+	Density float64 `json:"density"`
+	// LargestConnectedComponentSize is the number of entities of the largest
+	// group of entities connected by relationships in either direction
+	LargestConnectedComponentSize int `json:"largestConnectedComponentSize"`
+	// IsolatedEntityCount is the number of entities without relationships
+	IsolatedEntityCount int `json:"isolatedEntityCount"`
+	// MaxInDegree is the largest number of relationships ending at one entity
+	MaxInDegree int `json:"maxInDegree"`
+	// MaxOutDegree is the largest number of relationships starting at one entity
+	MaxOutDegree int `json:"maxOutDegree"`
 }
@@ -2,10 +2,14 @@ package core
 
 import (
 	"codegraphgen/db"
+	"codegraphgen/internal/analysis"
 	"codegraphgen/internal/core/graph"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -54,12 +58,12 @@ func (kg *KnowledgeGraphGenerator) GenerateKnowledgeGraph(text string) (*graph.K
 
 // StoreKnowledgeGraph stores entities and relationships in the database
 // Entities are updated if they already exist, relationships are merged
-func (kg *KnowledgeGraphGenerator) StoreKnowledgeGraph(entities []graph.Entity, relationships []graph.Relationship) error {
+func (kg *KnowledgeGraphGenerator) StoreKnowledgeGraph(ctx context.Context, entities []graph.Entity, relationships []graph.Relationship) error {
 	fmt.Println("💾 Storing knowledge graph in database...")
 
 	// Store/update entities first
 	for i, entity := range entities {
-		if err := kg.database.CreateEntity(entity); err != nil {
+		if err := kg.database.CreateEntity(ctx, entity); err != nil {
 			return fmt.Errorf("failed to create/update entity %s: %w", entity.Label, err)
 		}
 		if (i+1)%10 == 0 {
@@ -72,7 +76,7 @@ func (kg *KnowledgeGraphGenerator) StoreKnowledgeGraph(entities []graph.Entity,
 	// Then store/merge relationships
 	successfulRelationships := 0
 	for i, relationship := range relationships {
-		if err := kg.database.CreateRelationship(relationship); err != nil {
+		if err := kg.database.CreateRelationship(ctx, relationship); err != nil {
 			log.Printf("⚠️ Failed to create relationship %s->%s (%s): %v",
 				relationship.Source, relationship.Target, relationship.Type, err)
 		} else {
@@ -86,18 +90,63 @@ func (kg *KnowledgeGraphGenerator) StoreKnowledgeGraph(entities []graph.Entity,
 	fmt.Printf("✅ Successfully stored %d/%d relationships\n", successfulRelationships, len(relationships))
 	fmt.Println("✅ Knowledge graph stored successfully")
 
-	// Debug: Check if functions have relationships
-	if err := kg.debugFunctionRelationships(); err != nil {
+	// Debug: Check if functions have relationships, where the database
+	// understands the queries
+	if err := kg.debugFunctionRelationships(ctx); err != nil && !errors.Is(err, db.ErrUnsupportedQuery) {
 		log.Printf("⚠️ Debug check failed: %v", err)
 	}
 
 	return nil
 }
 
+// DefaultBatchSize is the number of entities or relationships written per batch
+// by BatchStoreKnowledgeGraph when no batch size is given
+const DefaultBatchSize = 500
+
+// BatchStoreKnowledgeGraph stores entities and relationships like
+// StoreKnowledgeGraph, but writes them to Memgraph in batches of batchSize with
+// UNWIND queries instead of one query per entity and relationship. A batchSize of
+// 0 or less uses DefaultBatchSize. Other databases are written one by one.
+func (kg *KnowledgeGraphGenerator) BatchStoreKnowledgeGraph(ctx context.Context, entities []graph.Entity, relationships []graph.Relationship, batchSize int) error {
+	memgraphDB, ok := kg.database.(*db.MemgraphDatabase)
+	if !ok {
+		return kg.StoreKnowledgeGraph(ctx, entities, relationships)
+	}
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	fmt.Printf("💾 Storing knowledge graph in database in batches of %d...\n", batchSize)
+
+	for start := 0; start < len(entities); start += batchSize {
+		end := min(start+batchSize, len(entities))
+		if err := memgraphDB.CreateEntities(ctx, entities[start:end]); err != nil {
+			return fmt.Errorf("failed to create/update entities: %w", err)
+		}
+		fmt.Printf("📊 Processed %d/%d entities\n", end, len(entities))
+	}
+
+	fmt.Printf("✅ Stored/updated %d entities\n", len(entities))
+
+	for start := 0; start < len(relationships); start += batchSize {
+		end := min(start+batchSize, len(relationships))
+		if err := memgraphDB.CreateRelationships(ctx, relationships[start:end]); err != nil {
+			return fmt.Errorf("failed to create/merge relationships: %w", err)
+		}
+		fmt.Printf("📊 Processed %d/%d relationships\n", end, len(relationships))
+	}
+
+	fmt.Printf("✅ Stored %d relationships\n", len(relationships))
+	fmt.Println("✅ Knowledge graph stored successfully")
+
+	return nil
+}
+
 // debugFunctionRelationships checks if function entities have relationships (for debugging)
-func (kg *KnowledgeGraphGenerator) debugFunctionRelationships() error {
+func (kg *KnowledgeGraphGenerator) debugFunctionRelationships(ctx context.Context) error {
 	// Find all function entities
-	functions, err := kg.QueryKnowledgeGraph(`
+	functions, err := kg.QueryKnowledgeGraph(ctx, `
 		MATCH (f:FUNCTION)
 		RETURN f.id as id, f.label as label
 		LIMIT 5
@@ -112,7 +161,7 @@ func (kg *KnowledgeGraphGenerator) debugFunctionRelationships() error {
 		if id, ok := fn["id"].(string); ok {
 			if label, ok := fn["label"].(string); ok {
 				// Check relationships for this function
-				rels, err := kg.QueryKnowledgeGraph(`
+				rels, err := kg.QueryKnowledgeGraph(ctx, `
 					MATCH (f {id: $id})-[r]-(other)
 					RETURN type(r) as relType, labels(other) as otherLabels, other.label as otherLabel
 				`, graph.Properties{"id": id})
@@ -137,8 +186,14 @@ func (kg *KnowledgeGraphGenerator) debugFunctionRelationships() error {
 }
 
 // QueryKnowledgeGraph executes a query against the knowledge graph
-func (kg *KnowledgeGraphGenerator) QueryKnowledgeGraph(cypher string, parameters graph.Properties) ([]db.QueryResult, error) {
-	return kg.database.Query(cypher, parameters)
+func (kg *KnowledgeGraphGenerator) QueryKnowledgeGraph(ctx context.Context, cypher string, parameters graph.Properties) ([]db.QueryResult, error) {
+	return kg.database.Query(ctx, cypher, parameters)
+}
+
+// ReadQueryKnowledgeGraph executes a query that must not modify the knowledge
+// graph, in a read transaction where the database supports one
+func (kg *KnowledgeGraphGenerator) ReadQueryKnowledgeGraph(ctx context.Context, cypher string, parameters graph.Properties) ([]db.QueryResult, error) {
+	return kg.database.ReadQuery(ctx, cypher, parameters)
 }
 
 // ProcessTextFile processes a text file and generates a knowledge graph
@@ -166,7 +221,7 @@ func (kg *KnowledgeGraphGenerator) ProcessMultipleTexts(texts []string) (*graph.
 		allRelationships = append(allRelationships, kgraph.Relationships...)
 	}
 
-	// Deduplicate entities based on label and type
+	// Deduplicate entities based on their ID
 	uniqueEntities := kg.deduplicateEntities(allEntities)
 
 	return &graph.KnowledgeGraph{
@@ -175,25 +230,90 @@ func (kg *KnowledgeGraphGenerator) ProcessMultipleTexts(texts []string) (*graph.
 	}, nil
 }
 
-// GetEntityConnections gets all connections for a specific entity
-func (kg *KnowledgeGraphGenerator) GetEntityConnections(entityID string) ([]db.QueryResult, error) {
-	cypher := `
-		MATCH (e {id: $entityId})-[r]-(connected)
-		RETURN e, r, connected
-	`
-	parameters := graph.Properties{"entityId": entityID}
-	return kg.QueryKnowledgeGraph(cypher, parameters)
+// GetEntityConnections gets all connections for a specific entity.
+// Each result holds the relationship as "r" and the connected entity as "m".
+func (kg *KnowledgeGraphGenerator) GetEntityConnections(ctx context.Context, entityID string) ([]db.QueryResult, error) {
+	cypher := "MATCH (n {id: $id})-[r]-(m) RETURN r, m"
+	parameters := graph.Properties{"id": entityID}
+	return kg.QueryKnowledgeGraph(ctx, cypher, parameters)
+}
+
+// connectedEntities returns the distinct entities connected to an entity
+func (kg *KnowledgeGraphGenerator) connectedEntities(ctx context.Context, entityID string) ([]db.Entity, error) {
+	results, err := kg.GetEntityConnections(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var entities []db.Entity
+	for _, result := range results {
+		if entity, ok := db.ToEntity(result["m"]); ok && !seen[entity.ID] {
+			seen[entity.ID] = true
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
 }
 
 // FindEntitiesByType finds all entities of a specific type
-func (kg *KnowledgeGraphGenerator) FindEntitiesByType(entityType string) ([]db.QueryResult, error) {
+func (kg *KnowledgeGraphGenerator) FindEntitiesByType(ctx context.Context, entityType string) ([]db.QueryResult, error) {
 	cypher := fmt.Sprintf("MATCH (n:%s) RETURN n", entityType)
-	return kg.QueryKnowledgeGraph(cypher, nil)
+	return kg.QueryKnowledgeGraph(ctx, cypher, nil)
+}
+
+// FindEntitiesByLabel finds entities whose label contains the given substring.
+// Results can be narrowed to an entity type and a language; empty filters match everything.
+func (kg *KnowledgeGraphGenerator) FindEntitiesByLabel(ctx context.Context, substring, entityType, language string) ([]graph.Entity, error) {
+	results, err := kg.QueryKnowledgeGraph(ctx, "MATCH (n) WHERE n.label CONTAINS $q RETURN n", graph.Properties{"q": substring})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search entities: %w", err)
+	}
+
+	var entities []graph.Entity
+	for _, result := range results {
+		entity, ok := db.ToEntity(result["n"])
+		if !ok {
+			continue
+		}
+		if entityType != "" && !strings.EqualFold(string(entity.Type), entityType) {
+			continue
+		}
+		if language != "" {
+			if lang, _ := entity.Properties["language"].(string); !strings.EqualFold(lang, language) {
+				continue
+			}
+		}
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+// GetEntitiesByExactLabel returns the entities whose label equals label, optionally
+// restricted to an entity type and a language ("" matches any)
+func (kg *KnowledgeGraphGenerator) GetEntitiesByExactLabel(ctx context.Context, label, entityType, language string) ([]graph.Entity, error) {
+	entities, err := kg.database.GetEntityByLabel(ctx, label, graph.EntityType(strings.ToUpper(entityType)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up entities: %w", err)
+	}
+
+	if language == "" {
+		return entities, nil
+	}
+
+	var filtered []graph.Entity
+	for _, entity := range entities {
+		if lang, _ := entity.Properties["language"].(string); strings.EqualFold(lang, language) {
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered, nil
 }
 
 // GetGraphStatistics returns statistics about the knowledge graph
-func (kg *KnowledgeGraphGenerator) GetGraphStatistics() (*graph.GraphStatistics, error) {
-	entityStats, err := kg.QueryKnowledgeGraph(`
+func (kg *KnowledgeGraphGenerator) GetGraphStatistics(ctx context.Context) (*graph.GraphStatistics, error) {
+	entityStats, err := kg.QueryKnowledgeGraph(ctx, `
 		MATCH (n)
 		RETURN labels(n)[0] as type, count(*) as count
 	`, nil)
@@ -201,7 +321,7 @@ func (kg *KnowledgeGraphGenerator) GetGraphStatistics() (*graph.GraphStatistics,
 		return nil, fmt.Errorf("failed to get entity stats: %w", err)
 	}
 
-	relationshipStats, err := kg.QueryKnowledgeGraph(`
+	relationshipStats, err := kg.QueryKnowledgeGraph(ctx, `
 		MATCH ()-[r]->()
 		RETURN type(r) as type, count(*) as count
 	`, nil)
@@ -209,12 +329,21 @@ func (kg *KnowledgeGraphGenerator) GetGraphStatistics() (*graph.GraphStatistics,
 		return nil, fmt.Errorf("failed to get relationship stats: %w", err)
 	}
 
+	languageStats, err := kg.QueryKnowledgeGraph(ctx, `
+		MATCH (n)
+		RETURN n.language as lang, count(*) as count
+	`, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language stats: %w", err)
+	}
+
 	entitiesByType := make(map[string]int)
 	relationshipsByType := make(map[string]int)
+	entitiesByLanguage := make(map[string]int)
 
 	for _, stat := range entityStats {
 		if entityType, ok := stat["type"].(string); ok {
-			if count, ok := stat["count"].(int); ok {
+			if count, ok := analysis.ToInt(stat["count"]); ok {
 				entitiesByType[entityType] = count
 			}
 		}
@@ -222,12 +351,27 @@ func (kg *KnowledgeGraphGenerator) GetGraphStatistics() (*graph.GraphStatistics,
 
 	for _, stat := range relationshipStats {
 		if relType, ok := stat["type"].(string); ok {
-			if count, ok := stat["count"].(int); ok {
+			if count, ok := analysis.ToInt(stat["count"]); ok {
 				relationshipsByType[relType] = count
 			}
 		}
 	}
 
+	for _, stat := range languageStats {
+		if lang, ok := stat["lang"].(string); ok && lang != "" {
+			if count, ok := analysis.ToInt(stat["count"]); ok {
+				entitiesByLanguage[lang] = count
+			}
+		}
+	}
+
+	// Files and lines are properties of the file entities, so the per-language
+	// report is built from the entities themselves
+	entities, err := kg.GetEntities(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language stats: %w", err)
+	}
+
 	totalEntities := 0
 	for _, count := range entitiesByType {
 		totalEntities += count
@@ -238,38 +382,106 @@ func (kg *KnowledgeGraphGenerator) GetGraphStatistics() (*graph.GraphStatistics,
 		totalRelationships += count
 	}
 
-	return &graph.GraphStatistics{
-		TotalEntities:       totalEntities,
-		TotalRelationships:  totalRelationships,
-		EntitiesByType:      entitiesByType,
-		RelationshipsByType: relationshipsByType,
-	}, nil
+	// The databases count the relationships of each entity themselves
+	degrees, err := kg.database.GetDegreeStatistics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get degree stats: %w", err)
+	}
+
+	largestComponentSize, err := kg.largestComponentSize(ctx, entities)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &graph.GraphStatistics{
+		TotalEntities:                 totalEntities,
+		TotalRelationships:            totalRelationships,
+		EntitiesByType:                entitiesByType,
+		RelationshipsByType:           relationshipsByType,
+		EntitiesByLanguage:            entitiesByLanguage,
+		Languages:                     analysis.LanguageStatistics(entities),
+		LargestConnectedComponentSize: largestComponentSize,
+		IsolatedEntityCount:           degrees.IsolatedEntities,
+		MaxInDegree:                   degrees.MaxInDegree,
+		MaxOutDegree:                  degrees.MaxOutDegree,
+	}
+	if totalEntities > 0 {
+		stats.AverageDegree = 2 * float64(totalRelationships) / float64(totalEntities)
+	}
+	if totalEntities > 1 {
+		stats.Density = float64(totalRelationships) / (float64(totalEntities) * float64(totalEntities-1))
+	}
+	return stats, nil
+}
+
+// largestComponentSize returns the number of entities of the largest weakly
+// connected component. Memgraph computes the components with the MAGE
+// weakly_connected_components procedure where it is installed; otherwise
+// they are computed from the endpoints of the relationships.
+func (kg *KnowledgeGraphGenerator) largestComponentSize(ctx context.Context, entities []graph.Entity) (int, error) {
+	if _, ok := kg.database.(*db.MemgraphDatabase); !ok {
+		relationships, err := kg.GetRelationships(ctx, 0)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get connectivity stats: %w", err)
+		}
+		return analysis.ComputeConnectivity(entities, relationships).LargestComponentSize, nil
+	}
+
+	components, err := kg.QueryKnowledgeGraph(ctx, `
+		CALL weakly_connected_components.get() YIELD component_id
+		RETURN component_id, count(*) AS size
+		ORDER BY size DESC
+		LIMIT 1
+	`, nil)
+	if err == nil {
+		if len(components) == 0 {
+			return 0, nil
+		}
+		if size, ok := analysis.ToInt(components[0]["size"]); ok {
+			return size, nil
+		}
+	}
+
+	edges, err := kg.QueryKnowledgeGraph(ctx, `
+		MATCH (a)-[]->(b)
+		RETURN DISTINCT a.id as source, b.id as target
+	`, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get connectivity stats: %w", err)
+	}
+
+	relationships := make([]graph.Relationship, 0, len(edges))
+	for _, edge := range edges {
+		source, _ := edge["source"].(string)
+		target, _ := edge["target"].(string)
+		relationships = append(relationships, graph.Relationship{Source: source, Target: target})
+	}
+	return analysis.ComputeConnectivity(entities, relationships).LargestComponentSize, nil
 }
 
 // ExportKnowledgeGraph exports the complete knowledge graph
-func (kg *KnowledgeGraphGenerator) ExportKnowledgeGraph() (*graph.KnowledgeGraph, error) {
-	entitiesResult, err := kg.QueryKnowledgeGraph("MATCH (n) RETURN n", nil)
+func (kg *KnowledgeGraphGenerator) ExportKnowledgeGraph(ctx context.Context) (*graph.KnowledgeGraph, error) {
+	entitiesResult, err := kg.QueryKnowledgeGraph(ctx, "MATCH (n) RETURN n", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export entities: %w", err)
 	}
 
-	relationshipsResult, err := kg.QueryKnowledgeGraph("MATCH (a)-[r]->(b) RETURN a, r, b", nil)
+	relationshipsResult, err := kg.QueryKnowledgeGraph(ctx, "MATCH (a)-[r]->(b) RETURN a, r, b", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export relationships: %w", err)
 	}
 
-	// This is a simplified export - in practice you'd want to properly reconstruct the entities and relationships
 	var entities []graph.Entity
 	var relationships []graph.Relationship
 
 	for _, result := range entitiesResult {
-		if entity, ok := result["n"].(graph.Entity); ok {
+		if entity, ok := db.ToEntity(result["n"]); ok {
 			entities = append(entities, entity)
 		}
 	}
 
 	for _, result := range relationshipsResult {
-		if relationship, ok := result["r"].(graph.Relationship); ok {
+		if relationship, ok := db.ToRelationship(result); ok {
 			relationships = append(relationships, relationship)
 		}
 	}
@@ -280,9 +492,175 @@ func (kg *KnowledgeGraphGenerator) ExportKnowledgeGraph() (*graph.KnowledgeGraph
 	}, nil
 }
 
+// GetEntities returns all entities with a confidence of at least minConfidence
+func (kg *KnowledgeGraphGenerator) GetEntities(ctx context.Context, minConfidence float64) ([]graph.Entity, error) {
+	cypher := "MATCH (n) RETURN n"
+	if _, ok := kg.database.(*db.MemgraphDatabase); ok {
+		cypher = "MATCH (n) WHERE n.confidence >= $minConfidence RETURN n"
+	}
+
+	results, err := kg.database.Query(ctx, cypher, graph.Properties{"minConfidence": minConfidence})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entities: %w", err)
+	}
+
+	entities := make([]graph.Entity, 0, len(results))
+	for _, result := range results {
+		// The in-memory backend returns every entity, so filter here as well
+		if entity, ok := db.ToEntity(result["n"]); ok && entity.Confidence >= minConfidence {
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}
+
+// GetRelationships returns all relationships with a confidence of at least minConfidence
+func (kg *KnowledgeGraphGenerator) GetRelationships(ctx context.Context, minConfidence float64) ([]graph.Relationship, error) {
+	relationships, err := kg.database.GetRelationships(ctx, minConfidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+	return relationships, nil
+}
+
+// GetSubgraph returns the neighborhood of an entity up to depth hops away
+func (kg *KnowledgeGraphGenerator) GetSubgraph(ctx context.Context, entityID string, depth int) (*graph.KnowledgeGraph, error) {
+	full, err := kg.ExportKnowledgeGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return full.Subgraph(entityID, depth), nil
+}
+
+// FindCircularImports returns the import cycles in the graph, each as the labels of
+// the entities along the cycle
+func (kg *KnowledgeGraphGenerator) FindCircularImports(ctx context.Context) ([][]string, error) {
+	if _, ok := kg.database.(*db.MemgraphDatabase); !ok {
+		full, err := kg.ExportKnowledgeGraph(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		labels := make(map[string]string, len(full.Entities))
+		for _, entity := range full.Entities {
+			labels[entity.ID] = entity.Label
+		}
+
+		cycles := [][]string{}
+		for _, cycle := range analysis.DetectCircularDependencies(full, graph.RelationshipTypeImports) {
+			// Match the Memgraph query, which only finds cycles of two or more entities
+			if len(cycle) < 2 {
+				continue
+			}
+			cycleLabels := make([]string, len(cycle))
+			for i, id := range cycle {
+				cycleLabels[i] = labels[id]
+			}
+			cycles = append(cycles, cycleLabels)
+		}
+		return cycles, nil
+	}
+
+	results, err := kg.database.Query(ctx, "MATCH path = (a)-[:IMPORTS*2..]->(a) RETURN nodes(path) as cycle", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find circular imports: %w", err)
+	}
+
+	// Every cycle is matched once per entity on it; keep one rotation of each
+	cycles := [][]string{}
+	seen := make(map[string]bool)
+	for _, result := range results {
+		nodes, ok := result["cycle"].([]interface{})
+		if !ok || len(nodes) < 2 {
+			continue
+		}
+
+		// The path ends where it started
+		labels := make(map[string]string)
+		var ids []string
+		for _, node := range nodes[:len(nodes)-1] {
+			if entity, ok := db.ToEntity(node); ok {
+				ids = append(ids, entity.ID)
+				labels[entity.ID] = entity.Label
+			}
+		}
+
+		ids = analysis.NormalizeCycle(ids)
+		key := strings.Join(ids, "|")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cycleLabels := make([]string, len(ids))
+		for i, id := range ids {
+			cycleLabels[i] = labels[id]
+		}
+		cycles = append(cycles, cycleLabels)
+	}
+
+	return cycles, nil
+}
+
+// ValidateGraph checks the consistency of the stored graph. Memgraph is checked
+// with Cypher queries, other databases by validating their stored entities and
+// relationships.
+func (kg *KnowledgeGraphGenerator) ValidateGraph(ctx context.Context) (*analysis.ValidationReport, error) {
+	if _, ok := kg.database.(*db.MemgraphDatabase); !ok {
+		// Exports drop relationships whose endpoints are missing, so validate
+		// the stored relationships against the entity IDs instead
+		entities, err := kg.GetEntities(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		relationships, err := kg.GetRelationships(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		return analysis.ValidateGraph(&graph.KnowledgeGraph{Entities: entities, Relationships: relationships}), nil
+	}
+
+	report := analysis.NewValidationReport()
+
+	// Each query returns the offending IDs in an "id" column
+	checks := []struct {
+		name   string
+		cypher string
+	}{
+		// Relationships always connect nodes in Memgraph, but the nodes may lack an entity ID
+		{analysis.CheckOrphanRelationships, "MATCH (a)-[r]->(b) WHERE a.id IS NULL OR b.id IS NULL RETURN r.id AS id"},
+		{analysis.CheckEmptyLabels, `MATCH (n) WHERE n.label IS NULL OR n.label = "" RETURN n.id AS id`},
+		// Entities are merged by ID and labels, so an ID used with different types creates several nodes
+		{analysis.CheckIDCollisions, "MATCH (n) WHERE n.id IS NOT NULL WITH n.id AS id, count(n) AS nodes WHERE nodes > 1 RETURN id"},
+		// The entity type is the node label, not a property
+		{analysis.CheckLabelCollisions, "MATCH (n) WHERE n.label IS NOT NULL AND n.id IS NOT NULL WITH toLower(trim(n.label)) AS label, labels(n)[0] AS type, count(DISTINCT n.id) AS ids WHERE ids > 1 RETURN type + ':' + label AS id"},
+	}
+	for _, check := range checks {
+		results, err := kg.database.Query(ctx, check.cypher, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate graph: %w", err)
+		}
+		for _, result := range results {
+			id, _ := result["id"].(string)
+			report.AddIssue(check.name, id)
+		}
+	}
+
+	results, err := kg.database.Query(ctx, "MATCH (n:`FILE`) RETURN n.id AS id, n.path AS path", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate graph: %w", err)
+	}
+	for _, result := range results {
+		id, _ := result["id"].(string)
+		report.CheckFilePath(id, result["path"])
+	}
+
+	return report, nil
+}
+
 // ClearDatabase clears all data from the database
-func (kg *KnowledgeGraphGenerator) ClearDatabase() error {
-	_, err := kg.database.Query("MATCH (n) DETACH DELETE n", nil)
+func (kg *KnowledgeGraphGenerator) ClearDatabase(ctx context.Context) error {
+	_, err := kg.database.Query(ctx, "MATCH (n) DETACH DELETE n", nil)
 	if err != nil {
 		return fmt.Errorf("failed to clear database: %w", err)
 	}
@@ -290,15 +668,63 @@ func (kg *KnowledgeGraphGenerator) ClearDatabase() error {
 	return nil
 }
 
-// deduplicateEntities removes duplicate entities based on label and type
+// ReplaceSourceFiles replaces the stored entities and relationships of files
+// with new ones in a single transaction. Files without new entities, e.g.
+// deleted files, are removed from the graph.
+func (kg *KnowledgeGraphGenerator) ReplaceSourceFiles(ctx context.Context, filePaths []string, entities []graph.Entity, relationships []graph.Relationship) error {
+	if err := kg.database.ReplaceSourceFiles(ctx, filePaths, entities, relationships); err != nil {
+		return fmt.Errorf("failed to replace the graph of %d files: %w", len(filePaths), err)
+	}
+	fmt.Printf("✅ Replaced the graph of %d files with %d entities and %d relationships\n",
+		len(filePaths), len(entities), len(relationships))
+	return nil
+}
+
+// GetEntityByID returns the stored entity with the given ID. The error wraps
+// db.ErrNotFound when the entity does not exist.
+func (kg *KnowledgeGraphGenerator) GetEntityByID(ctx context.Context, id string) (*graph.Entity, error) {
+	return kg.database.GetEntityByID(ctx, id)
+}
+
+// CreateEntity stores a single entity, updating it if it already exists
+func (kg *KnowledgeGraphGenerator) CreateEntity(ctx context.Context, entity graph.Entity) error {
+	if err := kg.database.CreateEntity(ctx, entity); err != nil {
+		return fmt.Errorf("failed to create entity %s: %w", entity.Label, err)
+	}
+	return nil
+}
+
+// CreateRelationship stores a single relationship, merging it if it already exists
+func (kg *KnowledgeGraphGenerator) CreateRelationship(ctx context.Context, relationship graph.Relationship) error {
+	if err := kg.database.CreateRelationship(ctx, relationship); err != nil {
+		return fmt.Errorf("failed to create relationship %s->%s (%s): %w",
+			relationship.Source, relationship.Target, relationship.Type, err)
+	}
+	return nil
+}
+
+// DeleteEntity deletes an entity and all of its relationships. The error wraps
+// db.ErrNotFound when the entity does not exist.
+func (kg *KnowledgeGraphGenerator) DeleteEntity(ctx context.Context, id string) error {
+	return kg.database.DeleteEntityByID(ctx, id)
+}
+
+// DeleteRelationship deletes a relationship. The error wraps db.ErrNotFound
+// when the relationship does not exist.
+func (kg *KnowledgeGraphGenerator) DeleteRelationship(ctx context.Context, id string) error {
+	return kg.database.DeleteRelationshipByID(ctx, id)
+}
+
+// deduplicateEntities removes duplicate entities based on their ID. Entities
+// with the same label and type but different IDs, e.g. functions of the same
+// name in different files, are all kept.
 func (kg *KnowledgeGraphGenerator) deduplicateEntities(entities []graph.Entity) []graph.Entity {
 	seen := make(map[string]bool)
 	var unique []graph.Entity
 
 	for _, entity := range entities {
-		key := fmt.Sprintf("%s-%s", strings.ToLower(entity.Label), entity.Type)
-		if !seen[key] {
-			seen[key] = true
+		if !seen[entity.ID] {
+			seen[entity.ID] = true
 			unique = append(unique, entity)
 		}
 	}
@@ -309,7 +735,23 @@ func (kg *KnowledgeGraphGenerator) deduplicateEntities(entities []graph.Entity)
 // Advanced querying methods
 
 // FindPathBetweenEntities finds paths between two entities
-func (kg *KnowledgeGraphGenerator) FindPathBetweenEntities(fromLabel, toLabel string) ([]db.QueryResult, error) {
+func (kg *KnowledgeGraphGenerator) FindPathBetweenEntities(ctx context.Context, fromLabel, toLabel string) ([]db.QueryResult, error) {
+	endpoints := make([][]db.Entity, 0, 2)
+	for _, label := range []string{fromLabel, toLabel} {
+		entities, err := kg.database.GetEntityByLabel(ctx, label, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up entity %s: %w", label, err)
+		}
+		if len(entities) == 0 {
+			return nil, fmt.Errorf("no entity with label %s", label)
+		}
+		endpoints = append(endpoints, entities)
+	}
+
+	if _, ok := kg.database.(*db.MemgraphDatabase); !ok {
+		return kg.findShortestPaths(ctx, endpoints[0], endpoints[1], 5)
+	}
+
 	cypher := `
 		MATCH (from {label: $fromLabel}), (to {label: $toLabel})
 		MATCH path = shortestPath((from)-[*]-(to))
@@ -321,11 +763,68 @@ func (kg *KnowledgeGraphGenerator) FindPathBetweenEntities(fromLabel, toLabel st
 		"fromLabel": fromLabel,
 		"toLabel":   toLabel,
 	}
-	return kg.QueryKnowledgeGraph(cypher, parameters)
+	return kg.QueryKnowledgeGraph(ctx, cypher, parameters)
+}
+
+// findShortestPaths searches the graph breadth-first, ignoring relationship direction,
+// for the shortest path from each of the from entities to any of the to entities.
+// Results hold the entities along the path as "path" and its length as "pathLength",
+// shortest first.
+func (kg *KnowledgeGraphGenerator) findShortestPaths(ctx context.Context, from, to []db.Entity, limit int) ([]db.QueryResult, error) {
+	targets := make(map[string]bool, len(to))
+	for _, entity := range to {
+		targets[entity.ID] = true
+	}
+
+	var results []db.QueryResult
+	for _, start := range from {
+		previous := map[string]db.Entity{start.ID: {}}
+		found := map[string]db.Entity{start.ID: start}
+		queue := []db.Entity{start}
+
+		var end *db.Entity
+		for len(queue) > 0 && end == nil {
+			current := queue[0]
+			queue = queue[1:]
+			if targets[current.ID] && current.ID != start.ID {
+				end = &current
+				break
+			}
+
+			neighbors, err := kg.connectedEntities(ctx, current.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find path: %w", err)
+			}
+			for _, neighbor := range neighbors {
+				if _, visited := found[neighbor.ID]; !visited {
+					found[neighbor.ID] = neighbor
+					previous[neighbor.ID] = current
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		if end == nil {
+			continue
+		}
+
+		var path []db.Entity
+		for id := end.ID; id != ""; id = previous[id].ID {
+			path = append([]db.Entity{found[id]}, path...)
+		}
+		results = append(results, db.QueryResult{"path": path, "pathLength": len(path) - 1})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i]["pathLength"].(int) < results[j]["pathLength"].(int)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
 }
 
 // FindInfluentialEntities finds entities with the most connections
-func (kg *KnowledgeGraphGenerator) FindInfluentialEntities(limit int) ([]db.QueryResult, error) {
+func (kg *KnowledgeGraphGenerator) FindInfluentialEntities(ctx context.Context, limit int) ([]db.QueryResult, error) {
 	cypher := `
 		MATCH (n)-[r]-()
 		WITH n, count(r) as connections
@@ -334,11 +833,15 @@ func (kg *KnowledgeGraphGenerator) FindInfluentialEntities(limit int) ([]db.Quer
 		LIMIT $limit
 	`
 	parameters := graph.Properties{"limit": limit}
-	return kg.QueryKnowledgeGraph(cypher, parameters)
+	return kg.QueryKnowledgeGraph(ctx, cypher, parameters)
 }
 
 // FindSimilarEntities finds entities similar to a given entity
-func (kg *KnowledgeGraphGenerator) FindSimilarEntities(entityID string, limit int) ([]db.QueryResult, error) {
+func (kg *KnowledgeGraphGenerator) FindSimilarEntities(ctx context.Context, entityID string, limit int) ([]db.QueryResult, error) {
+	if _, ok := kg.database.(*db.MemgraphDatabase); !ok {
+		return kg.findSimilarEntities(ctx, entityID, limit)
+	}
+
 	cypher := `
 		MATCH (target {id: $entityId})-[r1]-(common)-[r2]-(similar)
 		WHERE target <> similar
@@ -351,5 +854,46 @@ func (kg *KnowledgeGraphGenerator) FindSimilarEntities(entityID string, limit in
 		"entityId": entityID,
 		"limit":    limit,
 	}
-	return kg.QueryKnowledgeGraph(cypher, parameters)
+	return kg.QueryKnowledgeGraph(ctx, cypher, parameters)
+}
+
+// findSimilarEntities ranks the entities that share neighbors with an entity by
+// the number of neighbors they share, using neighbor lookups only
+func (kg *KnowledgeGraphGenerator) findSimilarEntities(ctx context.Context, entityID string, limit int) ([]db.QueryResult, error) {
+	common, err := kg.connectedEntities(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar entities: %w", err)
+	}
+
+	counts := make(map[string]int)
+	similar := make(map[string]db.Entity)
+	for _, neighbor := range common {
+		candidates, err := kg.connectedEntities(ctx, neighbor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find similar entities: %w", err)
+		}
+		for _, candidate := range candidates {
+			if candidate.ID == entityID {
+				continue
+			}
+			counts[candidate.ID]++
+			similar[candidate.ID] = candidate
+		}
+	}
+
+	results := make([]db.QueryResult, 0, len(similar))
+	for id, entity := range similar {
+		results = append(results, db.QueryResult{"similar": entity, "commonConnections": counts[id]})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		ci, cj := results[i]["commonConnections"].(int), results[j]["commonConnections"].(int)
+		if ci != cj {
+			return ci > cj
+		}
+		return results[i]["similar"].(db.Entity).ID < results[j]["similar"].(db.Entity).ID
+	})
+	if limit >= 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
 }
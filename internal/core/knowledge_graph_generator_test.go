@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"codegraphgen/db"
+	"codegraphgen/internal/analysis"
+	"codegraphgen/internal/core/graph"
+)
+
+// testDatabases returns an empty in-memory database, and an emptied Memgraph
+// and PostgreSQL database when MEMGRAPH_URI and POSTGRES_DSN name one. Tests
+// clear the Memgraph and PostgreSQL databases, so never point them at a
+// database holding a graph worth keeping.
+func testDatabases(t *testing.T) map[string]db.DatabaseConnection {
+	t.Helper()
+
+	databases := map[string]db.DatabaseConnection{"inmemory": db.NewInMemoryDatabase()}
+	if uri := os.Getenv("MEMGRAPH_URI"); uri != "" {
+		memgraph := db.NewMemgraphDatabase(uri, os.Getenv("MEMGRAPH_USER"), os.Getenv("MEMGRAPH_PASSWORD"))
+		connectTestDatabase(t, memgraph, memgraph.ClearDatabase)
+		databases["memgraph"] = memgraph
+	}
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		postgres := db.NewPostgresDatabase(dsn)
+		connectTestDatabase(t, postgres, postgres.ClearDatabase)
+		databases["postgres"] = postgres
+	}
+	return databases
+}
+
+// connectTestDatabase connects to a database and clears it before and after the test
+func connectTestDatabase(t *testing.T, database db.DatabaseConnection, clear func(context.Context) error) {
+	t.Helper()
+
+	if err := database.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if err := clear(context.Background()); err != nil {
+		t.Fatalf("ClearDatabase: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := clear(context.Background()); err != nil {
+			t.Errorf("ClearDatabase: %v", err)
+		}
+		database.Disconnect()
+	})
+}
+
+func TestGetRelationships(t *testing.T) {
+	main := graph.CreateEntity("main", graph.EntityTypeFunction, graph.Properties{"sourceFile": "main.go"})
+	run := graph.CreateEntity("run", graph.EntityTypeFunction, graph.Properties{"sourceFile": "run.go"})
+	call := graph.CreateRelationship(main.ID, run.ID, graph.RelationshipTypeCalls, nil)
+	guess := graph.CreateRelationship(run.ID, main.ID, graph.RelationshipTypeReferences, nil)
+	guess.Confidence = 0.4
+
+	for name, database := range testDatabases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			generator := NewKnowledgeGraphGenerator(NewTextProcessor(), database)
+			if err := generator.StoreKnowledgeGraph(ctx, []graph.Entity{main, run}, []graph.Relationship{call, guess}); err != nil {
+				t.Fatalf("StoreKnowledgeGraph: %v", err)
+			}
+
+			tests := []struct {
+				minConfidence float64
+				want          []string
+			}{
+				{minConfidence: 0, want: []string{call.ID, guess.ID}},
+				{minConfidence: 0.5, want: []string{call.ID}},
+			}
+			for _, tt := range tests {
+				relationships, err := generator.GetRelationships(ctx, tt.minConfidence)
+				if err != nil {
+					t.Fatalf("GetRelationships(%v): %v", tt.minConfidence, err)
+				}
+
+				var ids []string
+				for _, rel := range relationships {
+					ids = append(ids, rel.ID)
+					if rel.ID == call.ID && (rel.Source != main.ID || rel.Target != run.ID || rel.Type != graph.RelationshipTypeCalls) {
+						t.Errorf("GetRelationships(%v) returned %+v, want main CALLS run", tt.minConfidence, rel)
+					}
+				}
+				sort.Strings(ids)
+				want := append([]string(nil), tt.want...)
+				sort.Strings(want)
+				if !reflect.DeepEqual(ids, want) {
+					t.Errorf("GetRelationships(%v) = %v, want %v", tt.minConfidence, ids, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetGraphStatisticsConnectivity(t *testing.T) {
+	main := graph.CreateEntity("main", graph.EntityTypeFunction, nil)
+	run := graph.CreateEntity("run", graph.EntityTypeFunction, nil)
+	stop := graph.CreateEntity("stop", graph.EntityTypeFunction, nil)
+	unused := graph.CreateEntity("unused", graph.EntityTypeFunction, nil)
+	relationships := []graph.Relationship{
+		graph.CreateRelationship(main.ID, run.ID, graph.RelationshipTypeCalls, nil),
+		graph.CreateRelationship(main.ID, stop.ID, graph.RelationshipTypeCalls, nil),
+	}
+
+	for name, database := range testDatabases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			generator := NewKnowledgeGraphGenerator(NewTextProcessor(), database)
+			if err := generator.StoreKnowledgeGraph(ctx, []graph.Entity{main, run, stop, unused}, relationships); err != nil {
+				t.Fatalf("StoreKnowledgeGraph: %v", err)
+			}
+
+			stats, err := generator.GetGraphStatistics(ctx)
+			if err != nil {
+				t.Fatalf("GetGraphStatistics: %v", err)
+			}
+			if stats.IsolatedEntityCount != 1 {
+				t.Errorf("IsolatedEntityCount = %d, want 1", stats.IsolatedEntityCount)
+			}
+			if stats.MaxInDegree != 1 || stats.MaxOutDegree != 2 {
+				t.Errorf("MaxInDegree, MaxOutDegree = %d, %d, want 1, 2", stats.MaxInDegree, stats.MaxOutDegree)
+			}
+			if stats.LargestConnectedComponentSize != 3 {
+				t.Errorf("LargestConnectedComponentSize = %d, want 3", stats.LargestConnectedComponentSize)
+			}
+		})
+	}
+}
+
+func TestReplaceSourceFilesKeepsLinksFromOtherFiles(t *testing.T) {
+	dir := graph.CreateEntity("src", graph.EntityTypeDirectory, graph.Properties{"path": "src"})
+	fileA := graph.CreateEntity("a.go", graph.EntityTypeFile, graph.Properties{"path": "src/a.go"})
+	fileB := graph.CreateEntity("b.go", graph.EntityTypeFile, graph.Properties{"path": "src/b.go"})
+	main := graph.CreateEntity("main", graph.EntityTypeFunction, graph.Properties{"sourceFile": "src/a.go", "lineNumber": 3})
+	run := graph.CreateEntity("run", graph.EntityTypeFunction, graph.Properties{"sourceFile": "src/b.go", "lineNumber": 3})
+	entities := []graph.Entity{dir, fileA, fileB, main, run}
+	relationships := []graph.Relationship{
+		graph.CreateRelationship(dir.ID, fileA.ID, graph.RelationshipTypeContains, nil),
+		graph.CreateRelationship(dir.ID, fileB.ID, graph.RelationshipTypeContains, nil),
+		graph.CreateRelationship(fileA.ID, main.ID, graph.RelationshipTypeContains, nil),
+		graph.CreateRelationship(fileB.ID, run.ID, graph.RelationshipTypeContains, nil),
+		graph.CreateRelationship(main.ID, run.ID, graph.RelationshipTypeCalls, graph.Properties{"sourceFile": "src/a.go"}),
+	}
+
+	// Re-analyzing b.go finds run two lines further down, under a new ID
+	movedRun := graph.CreateEntity("run", graph.EntityTypeFunction, graph.Properties{"sourceFile": "src/b.go", "lineNumber": 5})
+	want := []string{
+		graph.CreateRelationship(dir.ID, fileA.ID, graph.RelationshipTypeContains, nil).ID,
+		graph.CreateRelationship(dir.ID, fileB.ID, graph.RelationshipTypeContains, nil).ID,
+		graph.CreateRelationship(fileA.ID, main.ID, graph.RelationshipTypeContains, nil).ID,
+		graph.CreateRelationship(fileB.ID, movedRun.ID, graph.RelationshipTypeContains, nil).ID,
+		graph.CreateRelationship(main.ID, movedRun.ID, graph.RelationshipTypeCalls, nil).ID,
+	}
+	sort.Strings(want)
+
+	for name, database := range testDatabases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			generator := NewKnowledgeGraphGenerator(NewTextProcessor(), database)
+			if err := generator.StoreKnowledgeGraph(ctx, entities, relationships); err != nil {
+				t.Fatalf("StoreKnowledgeGraph: %v", err)
+			}
+
+			err := generator.ReplaceSourceFiles(ctx, []string{"src/b.go"},
+				[]graph.Entity{fileB, movedRun},
+				[]graph.Relationship{graph.CreateRelationship(fileB.ID, movedRun.ID, graph.RelationshipTypeContains, nil)})
+			if err != nil {
+				t.Fatalf("ReplaceSourceFiles: %v", err)
+			}
+
+			stored, err := generator.GetRelationships(ctx, 0)
+			if err != nil {
+				t.Fatalf("GetRelationships: %v", err)
+			}
+			var ids []string
+			for _, rel := range stored {
+				ids = append(ids, rel.ID)
+			}
+			sort.Strings(ids)
+			if !reflect.DeepEqual(ids, want) {
+				t.Errorf("relationships after ReplaceSourceFiles = %v, want %v", ids, want)
+			}
+		})
+	}
+}
+
+// danglingDatabase is an in-memory database that also lists a relationship to
+// an entity that isn't stored, which the in-memory database itself rejects
+type danglingDatabase struct {
+	*db.InMemoryDatabase
+	dangling graph.Relationship
+}
+
+func (d *danglingDatabase) GetRelationships(ctx context.Context, minConfidence float64) ([]graph.Relationship, error) {
+	relationships, err := d.InMemoryDatabase.GetRelationships(ctx, minConfidence)
+	return append(relationships, d.dangling), err
+}
+
+func TestValidateGraphFindsOrphanRelationships(t *testing.T) {
+	main := graph.CreateEntity("main", graph.EntityTypeFunction, nil)
+	database := &danglingDatabase{
+		InMemoryDatabase: db.NewInMemoryDatabase(),
+		dangling:         graph.CreateRelationship(main.ID, "missing", graph.RelationshipTypeCalls, nil),
+	}
+	ctx := context.Background()
+	generator := NewKnowledgeGraphGenerator(NewTextProcessor(), database)
+	if err := generator.StoreKnowledgeGraph(ctx, []graph.Entity{main}, nil); err != nil {
+		t.Fatalf("StoreKnowledgeGraph: %v", err)
+	}
+
+	report, err := generator.ValidateGraph(ctx)
+	if err != nil {
+		t.Fatalf("ValidateGraph: %v", err)
+	}
+	for _, check := range report.Checks {
+		if check.Name == analysis.CheckOrphanRelationships {
+			if !reflect.DeepEqual(check.Samples, []string{database.dangling.ID}) {
+				t.Errorf("orphan relationships = %v, want %v", check.Samples, []string{database.dangling.ID})
+			}
+			return
+		}
+	}
+	t.Fatalf("report has no %s check", analysis.CheckOrphanRelationships)
+}
+
+// BenchmarkStoreKnowledgeGraphMemgraph compares writing a graph to Memgraph one
+// entity and relationship at a time with writing it in UNWIND batches. It runs
+// against the database named by MEMGRAPH_URI, which it clears.
+func BenchmarkStoreKnowledgeGraphMemgraph(b *testing.B) {
+	uri := os.Getenv("MEMGRAPH_URI")
+	if uri == "" {
+		b.Skip("MEMGRAPH_URI is not set")
+	}
+
+	const size = 2000
+	entities := make([]graph.Entity, 0, size)
+	relationships := make([]graph.Relationship, 0, size)
+	for i := 0; i < size; i++ {
+		entity := graph.CreateEntity(fmt.Sprintf("function%d", i), graph.EntityTypeFunction,
+			graph.Properties{"sourceFile": fmt.Sprintf("file%d.go", i/20), "lineNumber": i % 20})
+		entities = append(entities, entity)
+		if i > 0 {
+			relationships = append(relationships, graph.CreateRelationship(entities[i-1].ID, entity.ID, graph.RelationshipTypeCalls, nil))
+		}
+	}
+
+	// Storing reports its progress on stdout
+	stdout := os.Stdout
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		b.Fatal(err)
+	}
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+
+	memgraph := db.NewMemgraphDatabase(uri, os.Getenv("MEMGRAPH_USER"), os.Getenv("MEMGRAPH_PASSWORD"))
+	if err := memgraph.Connect(); err != nil {
+		b.Fatalf("Connect: %v", err)
+	}
+	defer memgraph.Disconnect()
+	generator := NewKnowledgeGraphGenerator(NewTextProcessor(), memgraph)
+
+	stores := []struct {
+		name  string
+		store func(ctx context.Context) error
+	}{
+		{name: "per-entity", store: func(ctx context.Context) error {
+			return generator.StoreKnowledgeGraph(ctx, entities, relationships)
+		}},
+		{name: "batched", store: func(ctx context.Context) error {
+			return generator.BatchStoreKnowledgeGraph(ctx, entities, relationships, DefaultBatchSize)
+		}},
+	}
+	for _, s := range stores {
+		b.Run(s.name, func(b *testing.B) {
+			ctx := context.Background()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				if err := memgraph.ClearDatabase(ctx); err != nil {
+					b.Fatalf("ClearDatabase: %v", err)
+				}
+				b.StartTimer()
+
+				if err := s.store(ctx); err != nil {
+					b.Fatalf("store: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watcher polls a directory for changes to the files the CodeProcessor analyzes
+type Watcher struct {
+	processor *CodeProcessor
+	rootPath  string
+	interval  time.Duration
+	modTimes  map[string]time.Time
+}
+
+// NewWatcher creates a watcher for rootPath that polls every interval
+func NewWatcher(processor *CodeProcessor, rootPath string, interval time.Duration) *Watcher {
+	return &Watcher{
+		processor: processor,
+		rootPath:  rootPath,
+		interval:  interval,
+	}
+}
+
+// Watch calls onChange with the sorted paths of added, modified and removed files
+// until ctx is cancelled. The first poll only records the current state.
+func (w *Watcher) Watch(ctx context.Context, onChange func(changed []string)) error {
+	modTimes, err := w.processor.snapshotModTimes(w.rootPath)
+	if err != nil {
+		return err
+	}
+	w.modTimes = modTimes
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current, err := w.processor.snapshotModTimes(w.rootPath)
+			if err != nil {
+				log.Printf("⚠️ Failed to scan %s: %v", w.rootPath, err)
+				continue
+			}
+
+			if changed := diffModTimes(w.modTimes, current); len(changed) > 0 {
+				w.modTimes = current
+				onChange(changed)
+			}
+		}
+	}
+}
+
+// snapshotModTimes records the modification time of every supported file under rootPath
+// without reading file contents
+func (cp *CodeProcessor) snapshotModTimes(rootPath string) (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != rootPath && cp.shouldSkipDirectory(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !cp.supportedExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			// The file was removed while walking
+			return nil
+		}
+		modTimes[path] = info.ModTime()
+		return nil
+	})
+
+	return modTimes, err
+}
+
+// diffModTimes returns the sorted paths that differ between two snapshots
+func diffModTimes(previous, current map[string]time.Time) []string {
+	var changed []string
+	for path, modTime := range current {
+		if previousTime, ok := previous[path]; !ok || !previousTime.Equal(modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// schemaOrgTypes maps entity types to the closest schema.org class.
+// Entity types without a schema.org counterpart only use the custom vocabulary.
+var schemaOrgTypes = map[graph.EntityType]string{
+	graph.EntityTypeClass:         "schema:SoftwareSourceCode",
+	graph.EntityTypeInterface:     "schema:SoftwareSourceCode",
+	graph.EntityTypeFunction:      "schema:SoftwareSourceCode",
+	graph.EntityTypeMethod:        "schema:SoftwareSourceCode",
+	graph.EntityTypeModule:        "schema:SoftwareSourceCode",
+	graph.EntityTypePackage:       "schema:SoftwareSourceCode",
+	graph.EntityTypeFile:          "schema:SoftwareSourceCode",
+	graph.EntityTypeDirectory:     "schema:Collection",
+	graph.EntityTypeDependency:    "schema:SoftwareApplication",
+	graph.EntityTypeComment:       "schema:Comment",
+	graph.EntityTypeDatabaseTable: "schema:Dataset",
+	graph.EntityTypeAPIEndpoint:   "schema:EntryPoint",
+}
+
+// ExportJSONLD serializes a knowledge graph as a JSON-LD document.
+// Entities become nodes identified by <baseURI>/entity/<id>; their types and properties
+// use the <baseURI>/vocab# vocabulary, with schema.org types added where one fits.
+// Relationships become links from the source node to the target node, named in
+// the <baseURI>/relationship# vocabulary under the rel: prefix so that they
+// don't overwrite properties of the same name, e.g. the references of SQL views.
+func ExportJSONLD(kg *graph.KnowledgeGraph, baseURI string) ([]byte, error) {
+	base := strings.TrimRight(baseURI, "/")
+	if _, err := url.ParseRequestURI(base); err != nil {
+		return nil, fmt.Errorf("invalid base URI %q: %w", baseURI, err)
+	}
+
+	entityURI := func(id string) string {
+		return base + "/entity/" + url.PathEscape(id)
+	}
+
+	nodes := make([]map[string]interface{}, 0, len(kg.Entities))
+	nodeByID := make(map[string]map[string]interface{}, len(kg.Entities))
+	for _, entity := range kg.Entities {
+		types := []string{jsonLDTerm(string(entity.Type), true)}
+		if schemaType, ok := schemaOrgTypes[entity.Type]; ok {
+			types = append([]string{schemaType}, types...)
+		}
+
+		node := map[string]interface{}{
+			"@id":         entityURI(entity.ID),
+			"@type":       types,
+			"schema:name": entity.Label,
+		}
+		for key, value := range entity.Properties {
+			if _, reserved := node[key]; !reserved && !strings.HasPrefix(key, "@") && !strings.HasPrefix(key, "rel:") {
+				node[key] = value
+			}
+		}
+
+		nodes = append(nodes, node)
+		nodeByID[entity.ID] = node
+	}
+
+	for _, rel := range kg.Relationships {
+		source, ok := nodeByID[rel.Source]
+		if !ok {
+			continue
+		}
+		term := "rel:" + jsonLDTerm(string(rel.Type), false)
+		links, _ := source[term].([]map[string]string)
+		source[term] = append(links, map[string]string{"@id": entityURI(rel.Target)})
+	}
+
+	document := map[string]interface{}{
+		"@context": map[string]interface{}{
+			"@vocab": base + "/vocab#",
+			"rel":    base + "/relationship#",
+			"schema": "https://schema.org/",
+		},
+		"@graph": nodes,
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON-LD: %w", err)
+	}
+	return data, nil
+}
+
+// jsonLDTerm converts an UPPER_SNAKE_CASE type name into a vocabulary term:
+// PascalCase for classes (DATABASE_TABLE -> DatabaseTable) and
+// camelCase for properties (DEPENDS_ON -> dependsOn)
+func jsonLDTerm(name string, class bool) string {
+	var term strings.Builder
+	for i, part := range strings.Split(strings.ToLower(name), "_") {
+		if part == "" {
+			continue
+		}
+		if i > 0 || class {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		term.WriteString(part)
+	}
+	return term.String()
+}
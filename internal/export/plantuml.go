@@ -0,0 +1,278 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// plantUMLArrows maps the relationship types drawn between classes to PlantUML arrows
+var plantUMLArrows = map[graph.RelationshipType]string{
+	graph.RelationshipTypeInheritsFrom: "--|>",
+	graph.RelationshipTypeExtends:      "--|>",
+	graph.RelationshipTypeImplements:   "..|>",
+	graph.RelationshipTypeDependsOn:    "..>",
+}
+
+// plantUMLType is a class or interface box of the diagram
+type plantUMLType struct {
+	entity  graph.Entity
+	alias   string
+	fields  []string
+	methods []string
+	// embeds are the types of embedded Go fields
+	embeds []string
+}
+
+// ExportPlantUML renders the classes and interfaces of a knowledge graph as a
+// PlantUML class diagram. Fields and methods are the entities the types CONTAIN,
+// types are grouped into a package per source file, and inheritance,
+// implementation and dependency relationships between them are drawn as arrows.
+// Types without such relationships get their inheritance arrows from their
+// extends and implements properties and embedded Go fields instead.
+func ExportPlantUML(kg *graph.KnowledgeGraph) (string, error) {
+	if kg == nil {
+		return "", fmt.Errorf("no knowledge graph to export")
+	}
+
+	entities := make(map[string]graph.Entity, len(kg.Entities))
+	for _, entity := range kg.Entities {
+		entities[entity.ID] = entity
+	}
+
+	// Collect the types in a stable order: by source file, then line
+	var types []*plantUMLType
+	for _, entity := range kg.Entities {
+		if entity.Type == graph.EntityTypeClass || entity.Type == graph.EntityTypeInterface {
+			types = append(types, &plantUMLType{entity: entity})
+		}
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		a, b := types[i].entity, types[j].entity
+		if fileA, fileB := plantUMLSourceFile(a), plantUMLSourceFile(b); fileA != fileB {
+			return fileA < fileB
+		}
+		return plantUMLLine(a) < plantUMLLine(b)
+	})
+	typesByID := make(map[string]*plantUMLType, len(types))
+	for i, t := range types {
+		t.alias = fmt.Sprintf("T%d", i+1)
+		typesByID[t.entity.ID] = t
+	}
+
+	// Members are contained by their type
+	for _, rel := range kg.Relationships {
+		t, ok := typesByID[rel.Source]
+		if !ok || rel.Type != graph.RelationshipTypeContains {
+			continue
+		}
+		member, ok := entities[rel.Target]
+		if !ok {
+			continue
+		}
+		switch member.Type {
+		case graph.EntityTypeProperty:
+			t.fields = append(t.fields, plantUMLField(member))
+			if embedded, _ := member.Properties["isEmbedded"].(bool); embedded {
+				fieldType, _ := member.Properties["type"].(string)
+				t.embeds = append(t.embeds, fieldType)
+			}
+		case graph.EntityTypeMethod, graph.EntityTypeFunction:
+			t.methods = append(t.methods, plantUMLMethod(member))
+		}
+	}
+	// Go interfaces list their method names instead of containing method entities
+	for _, t := range types {
+		if t.entity.Type == graph.EntityTypeInterface && len(t.methods) == 0 {
+			for _, method := range plantUMLStrings(t.entity.Properties["methods"]) {
+				t.methods = append(t.methods, method+"()")
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+
+	currentFile := ""
+	for _, t := range types {
+		if file := plantUMLSourceFile(t.entity); file != currentFile {
+			if currentFile != "" {
+				b.WriteString("}\n")
+			}
+			if file != "" {
+				fmt.Fprintf(&b, "package \"%s\" {\n", plantUMLName(file))
+			}
+			currentFile = file
+		}
+
+		keyword := "class"
+		if t.entity.Type == graph.EntityTypeInterface {
+			keyword = "interface"
+		}
+		fmt.Fprintf(&b, "%s \"%s\" as %s {\n", keyword, plantUMLName(t.entity.Label), t.alias)
+		for _, field := range t.fields {
+			fmt.Fprintf(&b, "  %s\n", field)
+		}
+		for _, method := range t.methods {
+			fmt.Fprintf(&b, "  %s\n", method)
+		}
+		b.WriteString("}\n")
+	}
+	if currentFile != "" {
+		b.WriteString("}\n")
+	}
+
+	arrows := make(map[string]bool)
+	drawArrow := func(source *plantUMLType, arrow string, target *plantUMLType) {
+		line := fmt.Sprintf("%s %s %s\n", source.alias, arrow, target.alias)
+		if !arrows[line] {
+			arrows[line] = true
+			b.WriteString(line)
+		}
+	}
+	inherits := make(map[string]bool)
+	for _, rel := range kg.Relationships {
+		arrow, ok := plantUMLArrows[rel.Type]
+		if !ok {
+			continue
+		}
+		source, sourceOK := typesByID[rel.Source]
+		target, targetOK := typesByID[rel.Target]
+		if sourceOK && targetOK {
+			drawArrow(source, arrow, target)
+			if rel.Type != graph.RelationshipTypeDependsOn {
+				inherits[source.entity.ID] = true
+			}
+		}
+	}
+
+	// Analyzers that don't create inheritance relationships record the names
+	// of the supertypes on the type
+	typesByName := make(map[string][]*plantUMLType)
+	for _, t := range types {
+		typesByName[t.entity.Label] = append(typesByName[t.entity.Label], t)
+	}
+	for _, t := range types {
+		if inherits[t.entity.ID] {
+			continue
+		}
+		supertypes := [][]string{
+			append(plantUMLStrings(t.entity.Properties["extends"]), t.embeds...),
+			plantUMLStrings(t.entity.Properties["implements"]),
+		}
+		for i, arrow := range []string{"--|>", "..|>"} {
+			for _, name := range supertypes[i] {
+				if target := plantUMLResolveType(typesByName, t, name); target != nil {
+					drawArrow(t, arrow, target)
+				}
+			}
+		}
+	}
+
+	b.WriteString("@enduml\n")
+	return b.String(), nil
+}
+
+// plantUMLResolveType returns the type of the diagram a supertype name like
+// "*pkg.Base" or "Base<T>" refers to from t: the type of that name in the
+// source file of t, or else the only type of that name in the language of t
+func plantUMLResolveType(typesByName map[string][]*plantUMLType, t *plantUMLType, name string) *plantUMLType {
+	name = strings.TrimLeft(strings.TrimSpace(name), "*")
+	if i := strings.IndexAny(name, "<[("); i >= 0 {
+		name = name[:i]
+	}
+	name = name[strings.LastIndex(name, ".")+1:]
+
+	language, _ := t.entity.Properties["language"].(string)
+	var candidates []*plantUMLType
+	for _, candidate := range typesByName[name] {
+		if candidateLanguage, _ := candidate.entity.Properties["language"].(string); candidateLanguage == language {
+			candidates = append(candidates, candidate)
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate != t && plantUMLSourceFile(candidate.entity) == plantUMLSourceFile(t.entity) {
+			return candidate
+		}
+	}
+	if len(candidates) == 1 && candidates[0] != t {
+		return candidates[0]
+	}
+	return nil
+}
+
+// plantUMLField renders a field as "+name : type"
+func plantUMLField(entity graph.Entity) string {
+	field := plantUMLVisibility(entity) + entity.Label
+	if fieldType, ok := entity.Properties["type"].(string); ok && fieldType != "" {
+		field += " : " + fieldType
+	}
+	return field
+}
+
+// plantUMLMethod renders a method as "+name() : returnType"
+func plantUMLMethod(entity graph.Entity) string {
+	method := plantUMLVisibility(entity) + entity.Label + "()"
+	returnTypes := plantUMLStrings(entity.Properties["returnTypes"])
+	if returnType, ok := entity.Properties["returnType"].(string); ok && returnType != "" {
+		returnTypes = []string{returnType}
+	}
+	if len(returnTypes) > 0 {
+		method += " : " + strings.Join(returnTypes, ", ")
+	}
+	return method
+}
+
+// plantUMLVisibility returns the PlantUML visibility marker of a member, if known
+func plantUMLVisibility(entity graph.Entity) string {
+	for _, key := range []string{"isExported", "isPublic"} {
+		if public, ok := entity.Properties[key].(bool); ok {
+			if public {
+				return "+"
+			}
+			return "-"
+		}
+	}
+	return ""
+}
+
+// plantUMLSourceFile returns the source file of an entity, or "" if it has none
+func plantUMLSourceFile(entity graph.Entity) string {
+	sourceFile, _ := entity.Properties["sourceFile"].(string)
+	return sourceFile
+}
+
+// plantUMLLine returns the line number of an entity, or 0 if it has none
+func plantUMLLine(entity graph.Entity) int {
+	switch line := entity.Properties["lineNumber"].(type) {
+	case int:
+		return line
+	case int64:
+		return int(line)
+	case float64:
+		return int(line)
+	}
+	return 0
+}
+
+// plantUMLStrings converts a list property to strings
+func plantUMLStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	}
+	return nil
+}
+
+// plantUMLName makes a name safe to use inside a quoted PlantUML name
+func plantUMLName(name string) string {
+	return strings.ReplaceAll(name, `"`, "'")
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// KnownHosts are the Git hosts repositories can be cloned from by URL
+var KnownHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// CloneDir checks that a URL is the HTTPS address of a repository on one of the
+// KnownHosts, such as https://github.com/user/repo, and returns the directory
+// under baseDir it is cloned to, such as baseDir/github.com/user/repo. URLs with
+// credentials, queries or fragments are rejected so that only public
+// repositories are cloned. The directory only depends on the repository, so
+// analyzing it again stores entities under the same paths and IDs.
+func CloneDir(baseDir, rawURL string) (string, error) {
+	host, segments, err := parseRepositoryURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{baseDir, host}, segments...)...), nil
+}
+
+// parseRepositoryURL validates a repository URL and returns its lower-case host
+// and path segments, the last being the repository name without .git
+func parseRepositoryURL(rawURL string) (string, []string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return "", nil, fmt.Errorf("only https URLs are supported")
+	}
+	if parsed.User != nil || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", nil, fmt.Errorf("URLs with credentials, queries or fragments are not supported")
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	known := false
+	for _, knownHost := range KnownHosts {
+		if host == knownHost {
+			known = true
+			break
+		}
+	}
+	if !known || parsed.Port() != "" {
+		return "", nil, fmt.Errorf("unsupported Git host %q: expected one of %s", parsed.Host, strings.Join(KnownHosts, ", "))
+	}
+
+	// GitLab groups may be nested, so the repository is the last of at least two segments
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 2 {
+		return "", nil, fmt.Errorf("URL %q does not name a repository", rawURL)
+	}
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." || strings.HasPrefix(segment, "-") {
+			return "", nil, fmt.Errorf("URL %q does not name a repository", rawURL)
+		}
+	}
+	segments[len(segments)-1] = strings.TrimSuffix(segments[len(segments)-1], ".git")
+	return host, segments, nil
+}
+
+// ErrCheckoutTooLarge is returned by ShallowClone for checkouts larger than the limit
+var ErrCheckoutTooLarge = errors.New("checkout too large")
+
+// ShallowClone clones the latest commit of a repository into dir, which must not
+// exist or be empty. Git never prompts for credentials, so private repositories
+// fail instead of blocking. When the files of the checkout add up to more than
+// maxSize bytes, the clone is removed and ErrCheckoutTooLarge returned; a
+// maxSize of 0 or less disables the limit.
+func ShallowClone(ctx context.Context, repositoryURL, dir string, maxSize int64) error {
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--no-tags", "--quiet", "--", repositoryURL, dir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	if _, err := cmd.Output(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("git clone failed: %w", ctxErr)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return fmt.Errorf("git clone failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	if maxSize <= 0 {
+		return nil
+	}
+	size, err := checkoutSize(dir)
+	if err != nil {
+		return fmt.Errorf("failed to measure checkout: %w", err)
+	}
+	if size > maxSize {
+		os.RemoveAll(dir)
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrCheckoutTooLarge, size, maxSize)
+	}
+	return nil
+}
+
+// checkoutSize returns the total size of the files of a checkout outside .git
+func checkoutSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ChangedFilesSince returns the files under repoPath that were added, modified or
+// deleted between commitRef and HEAD. Paths are joined with repoPath, the way
+// the code processor names the files it scans.
+func ChangedFilesSince(repoPath, commitRef string) ([]string, error) {
+	return diffFiles(repoPath, commitRef)
+}
+
+// DeletedFilesSince returns the files under repoPath that were deleted between
+// commitRef and HEAD. A renamed file counts as deleted under its old name.
+func DeletedFilesSince(repoPath, commitRef string) ([]string, error) {
+	return diffFiles(repoPath, commitRef, "--diff-filter=D")
+}
+
+// diffFiles runs git diff --name-only for commitRef..HEAD in repoPath. Renames
+// are reported as a deletion and an addition so that the old path is listed.
+func diffFiles(repoPath, commitRef string, options ...string) ([]string, error) {
+	if commitRef == "" || strings.HasPrefix(commitRef, "-") {
+		return nil, fmt.Errorf("invalid commit %q", commitRef)
+	}
+
+	args := []string{"-C", repoPath, "diff", "--name-only", "--relative", "--no-renames", "-z"}
+	args = append(args, options...)
+	args = append(args, commitRef+"..HEAD", "--")
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	var files []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			files = append(files, filepath.Join(repoPath, filepath.FromSlash(name)))
+		}
+	}
+	return files, nil
+}
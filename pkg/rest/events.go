@@ -0,0 +1,173 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"codegraphgen/internal/core"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GraphUpdatedEvent is pushed to /api/events subscribers after the changed
+// files of the watched directory have been re-analyzed
+type GraphUpdatedEvent struct {
+	ChangedFiles  []string `json:"changedFiles"`
+	Entities      int      `json:"entities"`
+	Relationships int      `json:"relationships"`
+}
+
+// eventBroker fans server-sent events out to all connected clients
+type eventBroker struct {
+	mutex   sync.Mutex
+	clients map[chan string]bool
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		clients: make(map[chan string]bool),
+	}
+}
+
+// subscribe registers a client and returns its event channel
+func (b *eventBroker) subscribe() chan string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	client := make(chan string, 8)
+	b.clients[client] = true
+	return client
+}
+
+// unsubscribe removes a client
+func (b *eventBroker) unsubscribe(client chan string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.clients, client)
+}
+
+// publish sends a named event with a JSON payload to every client.
+// Clients that are not keeping up miss the event rather than blocking the publisher.
+func (b *eventBroker) publish(event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("⚠️ Failed to encode %s event: %v", event, err)
+		return
+	}
+	message := fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for client := range b.clients {
+		select {
+		case client <- message:
+		default:
+		}
+	}
+}
+
+// eventsHandler streams server-sent events until the client disconnects
+func (s *Server) eventsHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		response := c.Response()
+		response.Header().Set(echo.HeaderContentType, "text/event-stream")
+		response.Header().Set(echo.HeaderCacheControl, "no-cache")
+		response.Header().Set(echo.HeaderConnection, "keep-alive")
+		response.WriteHeader(http.StatusOK)
+		response.Flush()
+
+		client := s.events.subscribe()
+		defer s.events.unsubscribe(client)
+
+		for {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case message := <-client:
+				if _, err := fmt.Fprint(response, message); err != nil {
+					return nil
+				}
+				response.Flush()
+			}
+		}
+	}
+}
+
+// watchCodebase analyzes the watched directory, then re-analyzes the files that
+// change and publishes a graph-updated event
+func (s *Server) watchCodebase(ctx context.Context) {
+	if err := s.analyzeWatchDir(ctx); err != nil {
+		log.Printf("⚠️ Initial analysis of %s failed: %v", s.watchDir, err)
+	}
+
+	watcher := core.NewWatcher(s.codeProcessor, s.watchDir, time.Second)
+	err := watcher.Watch(ctx, func(changed []string) {
+		log.Printf("👀 %d file(s) changed in %s", len(changed), s.watchDir)
+
+		kg, err := s.reanalyzeChangedFiles(ctx, changed)
+		if err != nil {
+			log.Printf("⚠️ Re-analysis of %s failed: %v", s.watchDir, err)
+			return
+		}
+
+		s.events.publish("graph-updated", GraphUpdatedEvent{
+			ChangedFiles:  changed,
+			Entities:      len(kg.Entities),
+			Relationships: len(kg.Relationships),
+		})
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Printf("⚠️ Stopped watching %s: %v", s.watchDir, err)
+	}
+}
+
+// analyzeWatchDir analyzes the whole watched directory and stores the result
+func (s *Server) analyzeWatchDir(ctx context.Context) error {
+	kg, err := s.analyzeCodebase(s.watchDir)
+	if err != nil {
+		return err
+	}
+
+	if err := s.generator.StoreKnowledgeGraph(ctx, kg.Entities, kg.Relationships); err != nil {
+		return fmt.Errorf("failed to store results: %w", err)
+	}
+	return nil
+}
+
+// reanalyzeChangedFiles analyzes the changed files of the watched directory that
+// still exist and replaces the stored graph of all changed files with the result,
+// so that code removed from changed files and the entities of deleted files
+// disappear from the graph. The replacement is a single transaction, so /api
+// readers never see the files missing or half stored. The returned graph holds
+// the new entities and relationships of the changed files.
+func (s *Server) reanalyzeChangedFiles(ctx context.Context, changed []string) (*graph.KnowledgeGraph, error) {
+	var existing []string
+	for _, path := range changed {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+
+	entities, relationships, err := s.codeProcessor.AnalyzeFiles(s.watchDir, existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process changed files: %w", err)
+	}
+
+	if err := s.generator.ReplaceSourceFiles(ctx, changed, entities, relationships); err != nil {
+		return nil, fmt.Errorf("failed to store results: %w", err)
+	}
+
+	return &graph.KnowledgeGraph{
+		Entities:      entities,
+		Relationships: relationships,
+	}, nil
+}
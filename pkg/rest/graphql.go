@@ -0,0 +1,300 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"codegraphgen/db"
+	"codegraphgen/internal/core/graph"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultGraphQLPageSize is the number of entities returned per page by the
+// entities query when no pageSize is given
+const defaultGraphQLPageSize = 100
+
+// graphqlIntrospectionQuery selects the types of the schema with their fields
+// and arguments. GET /api/graphql returns its result, so clients can discover
+// the available fields without a GraphQL client.
+const graphqlIntrospectionQuery = `{
+  __schema {
+    queryType { name }
+    types {
+      name
+      kind
+      description
+      fields {
+        name
+        description
+        args { name description type { ...TypeRef } defaultValue }
+        type { ...TypeRef }
+      }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name } } }
+}`
+
+// GraphQLRequest is the body of POST /api/graphql
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// graphqlMapType is an output scalar for property and statistics maps,
+// serialized as a JSON object
+var graphqlMapType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Map",
+	Description: "A JSON object",
+	Serialize: func(value interface{}) interface{} {
+		return value
+	},
+})
+
+var graphqlEntityType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Entity",
+	Description: "An entity of the knowledge graph, e.g. a function, class or file",
+	Fields: graphql.Fields{
+		"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"label":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"type":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The entity type, e.g. FUNCTION"},
+		"confidence": &graphql.Field{Type: graphql.Float},
+		"properties": &graphql.Field{Type: graphqlMapType},
+	},
+})
+
+var graphqlRelationshipType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Relationship",
+	Description: "A directed relationship between two entities",
+	Fields: graphql.Fields{
+		"id":     &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"source": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The ID of the source entity"},
+		"target": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The ID of the target entity"},
+		"type":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The relationship type, e.g. CALLS"},
+	},
+})
+
+var graphqlStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Stats",
+	Description: "Statistics of the knowledge graph",
+	Fields: graphql.Fields{
+		"totalEntities":                 &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"totalRelationships":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"entitiesByType":                &graphql.Field{Type: graphqlMapType},
+		"relationshipsByType":           &graphql.Field{Type: graphqlMapType},
+		"entitiesByLanguage":            &graphql.Field{Type: graphqlMapType},
+		"averageDegree":                 &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"density":                       &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"largestConnectedComponentSize": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"isolatedEntityCount":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"maxInDegree":                   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"maxOutDegree":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+// newGraphQLSchema returns the schema of the GraphQL API, resolving its queries
+// against the server's knowledge graph
+func (s *Server) newGraphQLSchema() (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"entity": &graphql.Field{
+				Type:        graphqlEntityType,
+				Description: "The entity with the given ID",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: s.resolveGraphQLEntity,
+			},
+			"entities": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphqlEntityType))),
+				Description: "A page of the entities ordered by ID, optionally of a single type",
+				Args: graphql.FieldConfigArgument{
+					"type":     &graphql.ArgumentConfig{Type: graphql.String},
+					"page":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"pageSize": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultGraphQLPageSize},
+				},
+				Resolve: s.resolveGraphQLEntities,
+			},
+			"relationships": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphqlRelationshipType))),
+				Description: "The relationships ordered by ID, optionally starting at an entity or of a single type",
+				Args: graphql.FieldConfigArgument{
+					"sourceId": &graphql.ArgumentConfig{Type: graphql.String},
+					"type":     &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: s.resolveGraphQLRelationships,
+			},
+			"stats": &graphql.Field{
+				Type:        graphqlStatsType,
+				Description: "The statistics of the graph",
+				Resolve:     s.resolveGraphQLStats,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// graphqlSchemaHandler returns the introspected schema: its types with their
+// fields, arguments and descriptions
+func (s *Server) graphqlSchemaHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		result := graphql.Do(graphql.Params{
+			Schema:        s.graphqlSchema,
+			RequestString: graphqlIntrospectionQuery,
+			Context:       c.Request().Context(),
+		})
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// graphqlHandler executes a GraphQL query against the knowledge graph. Errors
+// are reported in the errors list of the response, as usual for GraphQL.
+func (s *Server) graphqlHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req GraphQLRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, graphqlErrorResult("Invalid request body"))
+		}
+
+		if strings.TrimSpace(req.Query) == "" {
+			return c.JSON(http.StatusBadRequest, graphqlErrorResult("query is required"))
+		}
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		result := graphql.Do(graphql.Params{
+			Schema:         s.graphqlSchema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        ctx,
+		})
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// graphqlErrorResult returns a result without data reporting a request error
+func graphqlErrorResult(message string) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
+
+// resolveGraphQLEntity resolves the entity query
+func (s *Server) resolveGraphQLEntity(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(string)
+	entity, err := s.generator.GetEntityByID(p.Context, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return graphqlEntity(*entity), nil
+}
+
+// resolveGraphQLEntities resolves the entities query
+func (s *Server) resolveGraphQLEntities(p graphql.ResolveParams) (interface{}, error) {
+	page, _ := p.Args["page"].(int)
+	pageSize, _ := p.Args["pageSize"].(int)
+	if page < 1 || pageSize < 1 {
+		return nil, fmt.Errorf("page and pageSize must be positive")
+	}
+
+	var matched []graph.Entity
+	var err error
+	if entityType, _ := p.Args["type"].(string); entityType != "" {
+		matched, err = s.database.GetEntitiesByType(p.Context, graph.EntityType(strings.ToUpper(entityType)))
+	} else {
+		matched, err = s.generator.GetEntities(p.Context, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+
+	results := []map[string]interface{}{}
+	for i := (page - 1) * pageSize; i < len(matched) && i < page*pageSize; i++ {
+		results = append(results, graphqlEntity(matched[i]))
+	}
+	return results, nil
+}
+
+// resolveGraphQLRelationships resolves the relationships query
+func (s *Server) resolveGraphQLRelationships(p graphql.ResolveParams) (interface{}, error) {
+	var relationships []graph.Relationship
+	var err error
+	if sourceID, _ := p.Args["sourceId"].(string); sourceID != "" {
+		relationships, err = s.database.GetRelationshipsByEntityID(p.Context, sourceID, db.DirectionOut)
+	} else {
+		relationships, err = s.generator.GetRelationships(p.Context, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	relationshipType, _ := p.Args["type"].(string)
+	sort.Slice(relationships, func(i, j int) bool { return relationships[i].ID < relationships[j].ID })
+
+	results := []map[string]interface{}{}
+	for _, rel := range relationships {
+		if relationshipType == "" || strings.EqualFold(string(rel.Type), relationshipType) {
+			results = append(results, graphqlRelationship(rel))
+		}
+	}
+	return results, nil
+}
+
+// resolveGraphQLStats resolves the stats query
+func (s *Server) resolveGraphQLStats(p graphql.ResolveParams) (interface{}, error) {
+	stats, err := s.generator.GetGraphStatistics(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"totalEntities":                 stats.TotalEntities,
+		"totalRelationships":            stats.TotalRelationships,
+		"entitiesByType":                stats.EntitiesByType,
+		"relationshipsByType":           stats.RelationshipsByType,
+		"entitiesByLanguage":            stats.EntitiesByLanguage,
+		"averageDegree":                 stats.AverageDegree,
+		"density":                       stats.Density,
+		"largestConnectedComponentSize": stats.LargestConnectedComponentSize,
+		"isolatedEntityCount":           stats.IsolatedEntityCount,
+		"maxInDegree":                   stats.MaxInDegree,
+		"maxOutDegree":                  stats.MaxOutDegree,
+	}, nil
+}
+
+// graphqlEntity returns the fields of the Entity type for an entity
+func graphqlEntity(entity graph.Entity) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         entity.ID,
+		"label":      entity.Label,
+		"type":       string(entity.Type),
+		"confidence": entity.Confidence,
+		"properties": entity.Properties,
+	}
+}
+
+// graphqlRelationship returns the fields of the Relationship type for a relationship
+func graphqlRelationship(rel graph.Relationship) map[string]interface{} {
+	return map[string]interface{}{
+		"id":     rel.ID,
+		"source": rel.Source,
+		"target": rel.Target,
+		"type":   string(rel.Type),
+	}
+}
@@ -0,0 +1,192 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"codegraphgen/internal/core/graph"
+)
+
+// newGraphQLTestServer returns a server on the in-memory database holding two
+// functions and a call between them
+func newGraphQLTestServer(t *testing.T) (*Server, graph.Entity, graph.Entity) {
+	t.Helper()
+
+	server, err := NewServer(Config{})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	caller := graph.CreateEntity("main", graph.EntityTypeFunction, graph.Properties{"language": "go"})
+	callee := graph.CreateEntity("run", graph.EntityTypeFunction, graph.Properties{"language": "go"})
+	call := graph.CreateRelationship(caller.ID, callee.ID, graph.RelationshipTypeCalls, nil)
+
+	ctx := context.Background()
+	if err := server.generator.StoreKnowledgeGraph(ctx, []graph.Entity{caller, callee}, []graph.Relationship{call}); err != nil {
+		t.Fatalf("StoreKnowledgeGraph: %v", err)
+	}
+	return server, caller, callee
+}
+
+// postGraphQL sends a GraphQL request and decodes the response
+func postGraphQL(t *testing.T, server *Server, request GraphQLRequest) (int, map[string]interface{}) {
+	t.Helper()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+	}
+	return rec.Code, response
+}
+
+func TestGraphQLQueryWithVariablesAndFragments(t *testing.T) {
+	server, caller, callee := newGraphQLTestServer(t)
+
+	code, response := postGraphQL(t, server, GraphQLRequest{
+		Query: `query Calls($id: ID!, $source: String) {
+			entity(id: $id) { ...EntityFields }
+			relationships(sourceId: $source, type: "CALLS") { source target type }
+			stats { totalEntities }
+		}
+		fragment EntityFields on Entity { id label type }`,
+		Variables: map[string]interface{}{"id": caller.ID, "source": caller.ID},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	if errs, ok := response["errors"]; ok {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	data := response["data"].(map[string]interface{})
+	entity := data["entity"].(map[string]interface{})
+	if entity["id"] != caller.ID || entity["label"] != "main" || entity["type"] != "FUNCTION" {
+		t.Errorf("entity = %v, want main (%s)", entity, caller.ID)
+	}
+
+	relationships := data["relationships"].([]interface{})
+	if len(relationships) != 1 {
+		t.Fatalf("got %d relationships, want 1", len(relationships))
+	}
+	if target := relationships[0].(map[string]interface{})["target"]; target != callee.ID {
+		t.Errorf("relationship target = %v, want %s", target, callee.ID)
+	}
+
+	if total := data["stats"].(map[string]interface{})["totalEntities"]; total != float64(2) {
+		t.Errorf("totalEntities = %v, want 2", total)
+	}
+}
+
+func TestGraphQLEntitiesPagination(t *testing.T) {
+	server, _, _ := newGraphQLTestServer(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "default page", query: `{ entities { id } }`, want: 2},
+		{name: "first page", query: `{ entities(pageSize: 1) { id } }`, want: 1},
+		{name: "past the end", query: `{ entities(page: 3, pageSize: 1) { id } }`, want: 0},
+		{name: "by type", query: `{ entities(type: "class") { id } }`, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, response := postGraphQL(t, server, GraphQLRequest{Query: tt.query})
+			if errs, ok := response["errors"]; ok {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			entities := response["data"].(map[string]interface{})["entities"].([]interface{})
+			if len(entities) != tt.want {
+				t.Errorf("got %d entities, want %d", len(entities), tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server, _, _ := newGraphQLTestServer(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+	}{
+		{name: "empty query", query: " ", wantCode: http.StatusBadRequest},
+		{name: "unknown field", query: `{ functions { id } }`, wantCode: http.StatusOK},
+		{name: "syntax error", query: `{ entities { id }`, wantCode: http.StatusOK},
+		{name: "invalid page", query: `{ entities(page: 0) { id } }`, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := postGraphQL(t, server, GraphQLRequest{Query: tt.query})
+			if code != tt.wantCode {
+				t.Errorf("status = %d, want %d", code, tt.wantCode)
+			}
+			if errs, _ := response["errors"].([]interface{}); len(errs) == 0 {
+				t.Errorf("expected errors, got %v", response)
+			}
+		})
+	}
+}
+
+func TestGraphQLSchemaIntrospection(t *testing.T) {
+	server, _, _ := newGraphQLTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/graphql", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var response struct {
+		Data struct {
+			Schema struct {
+				QueryType struct {
+					Name string `json:"name"`
+				} `json:"queryType"`
+				Types []struct {
+					Name   string `json:"name"`
+					Fields []struct {
+						Name string `json:"name"`
+					} `json:"fields"`
+				} `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+	}
+
+	if name := response.Data.Schema.QueryType.Name; name != "Query" {
+		t.Errorf("query type = %q, want Query", name)
+	}
+	fields := make(map[string][]string)
+	for _, schemaType := range response.Data.Schema.Types {
+		for _, field := range schemaType.Fields {
+			fields[schemaType.Name] = append(fields[schemaType.Name], field.Name)
+		}
+	}
+	for _, name := range []string{"entity", "entities", "relationships", "stats"} {
+		if !strings.Contains(strings.Join(fields["Query"], ","), name) {
+			t.Errorf("Query fields %v lack %s", fields["Query"], name)
+		}
+	}
+	if len(fields["Entity"]) != 5 {
+		t.Errorf("Entity fields = %v, want 5 fields", fields["Entity"])
+	}
+}
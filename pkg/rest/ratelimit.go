@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterIdleTimeout is how long the limiter of a client is kept after its
+// last request
+const rateLimiterIdleTimeout = 10 * time.Minute
+
+// clientIPExtractor returns how the client IP of a request is determined. By
+// default it is the address of the connection; X-Forwarded-For is trusted only
+// when the request comes from one of trustedProxies, given as IP addresses or
+// CIDR ranges, since any client can send the header.
+func clientIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			proxy = fmt.Sprintf("%s/%d", ip, bits)
+		}
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// clientLimiter is the rate limiter of one client IP
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+// ipRateLimiter limits the request rate of each client IP with a token bucket of
+// its own
+type ipRateLimiter struct {
+	limit       rate.Limit
+	burst       int
+	clients     sync.Map // client IP -> *clientLimiter
+	lastCleanup atomic.Int64
+}
+
+// newIPRateLimiter creates a limiter allowing each client requestsPerSecond
+// requests per second, with bursts of up to burst requests
+func newIPRateLimiter(requestsPerSecond float64, burst int) *ipRateLimiter {
+	l := &ipRateLimiter{limit: rate.Limit(requestsPerSecond), burst: burst}
+	l.lastCleanup.Store(time.Now().UnixNano())
+	return l
+}
+
+// middleware rejects requests of clients over their limit with 429 Too Many
+// Requests and a Retry-After header. Health checks at healthPath are not limited.
+func (l *ipRateLimiter) middleware(healthPath string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Path() == healthPath {
+				return next(c)
+			}
+
+			now := time.Now()
+			reservation := l.client(c.RealIP(), now).ReserveN(now, 1)
+			if delay := reservation.DelayFrom(now); !reservation.OK() || delay > 0 {
+				reservation.CancelAt(now)
+				retryAfter := int(math.Ceil(delay.Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				return c.JSON(http.StatusTooManyRequests, AnalysisResponse{
+					Success: false,
+					Message: "Rate limit exceeded, retry later",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// client returns the limiter of a client IP, creating it on its first request
+func (l *ipRateLimiter) client(ip string, now time.Time) *rate.Limiter {
+	l.removeIdleClients(now)
+
+	value, ok := l.clients.Load(ip)
+	if !ok {
+		value, _ = l.clients.LoadOrStore(ip, &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)})
+	}
+	client := value.(*clientLimiter)
+	client.lastSeen.Store(now.UnixNano())
+	return client.limiter
+}
+
+// removeIdleClients forgets clients that haven't sent a request for
+// rateLimiterIdleTimeout, at most once per timeout, so the limiters of past
+// clients don't accumulate
+func (l *ipRateLimiter) removeIdleClients(now time.Time) {
+	last := l.lastCleanup.Load()
+	if now.UnixNano()-last < int64(rateLimiterIdleTimeout) || !l.lastCleanup.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	l.clients.Range(func(key, value interface{}) bool {
+		if now.UnixNano()-value.(*clientLimiter).lastSeen.Load() > int64(rateLimiterIdleTimeout) {
+			l.clients.Delete(key)
+		}
+		return true
+	})
+}
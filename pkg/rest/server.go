@@ -1,15 +1,28 @@
 package rest
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"codegraphgen/db"
+	"codegraphgen/internal/analysis"
 	"codegraphgen/internal/core"
 	"codegraphgen/internal/core/graph"
+	"codegraphgen/internal/git"
 
+	"github.com/graphql-go/graphql"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 // Server represents the REST API server
@@ -19,13 +32,102 @@ type Server struct {
 	database      db.DatabaseConnection
 	echo          *echo.Echo
 	port          int
+	watchDir      string
+	events        *eventBroker
+	stopWatching  context.CancelFunc
+	readOnly      bool
+	tls           TLSConfig
+	queryTimeout  time.Duration
+	basePath      string
+	allowRemote   bool
+	cloneMutex    sync.Mutex
+	graphqlSchema graphql.Schema
 }
 
+const (
+	// cloneTimeout bounds cloning a repository for /api/analyze/url
+	cloneTimeout = 2 * time.Minute
+	// maxCloneSize is the largest checkout in bytes /api/analyze/url analyzes
+	maxCloneSize = 256 << 20
+)
+
 // Config holds server configuration
 type Config struct {
 	Port        int
 	Verbose     bool
 	UseMemgraph bool
+
+	// Memgraph connection settings, used when UseMemgraph is set. Empty settings
+	// fall back to MEMGRAPH_URI, MEMGRAPH_USER and MEMGRAPH_PASSWORD, then to
+	// bolt://localhost:7687 without authentication.
+	MemgraphURI           string
+	MemgraphUser          string
+	MemgraphPassword      string
+	MemgraphTLS           bool
+	MemgraphTLSSkipVerify bool
+	MemgraphTLSConfig     *tls.Config
+
+	// PostgresDSN selects the PostgreSQL backend when set
+	PostgresDSN string
+
+	// WatchDir enables live mode: the directory is analyzed on startup and
+	// re-analyzed on every change, with a graph-updated event sent to /api/events
+	WatchDir string
+
+	// ReadOnly disables the analysis endpoints and write queries, for serving a
+	// graph populated elsewhere (e.g. by a CI job)
+	ReadOnly bool
+
+	// TLS serves HTTPS instead of HTTP when configured
+	TLS TLSConfig
+
+	// RateLimit is the number of requests per second allowed for each client IP,
+	// with bursts of up to RateBurst requests. 0 disables rate limiting.
+	RateLimit float64
+	RateBurst int
+
+	// TrustedProxies are the IP addresses or CIDR ranges of reverse proxies
+	// whose X-Forwarded-For header names the client IP. Without them the
+	// client IP is the address of the connection.
+	TrustedProxies []string
+
+	// CORSAllowOrigins are the origins browsers may call the API from. Without
+	// origins cross-origin requests are not allowed, unless CORSAllowAll is set.
+	CORSAllowOrigins []string
+	CORSAllowAll     bool
+
+	// QueryTimeout bounds each Memgraph or PostgreSQL query or write batch,
+	// and the database operations of a request other than storing analysis
+	// results. 0 disables the timeout; operations still end when the client
+	// disconnects.
+	QueryTimeout time.Duration
+
+	// Compress gzip-compresses responses for clients that accept it
+	Compress bool
+
+	// AllowRemote enables /api/analyze/url, which clones repositories from
+	// GitHub, GitLab or Bitbucket onto the server to analyze them
+	AllowRemote bool
+
+	// BasePath prefixes every route, e.g. /api-gateway/codegraphgen when the
+	// server is deployed behind a reverse proxy under that path
+	BasePath string
+
+	// MergeStrategy selects how entities stored again under an existing ID are
+	// merged with the stored entity. Empty selects db.MergeProperties.
+	MergeStrategy db.MergeStrategy
+}
+
+// TLSConfig selects how the server obtains its certificate: from CertFile and
+// KeyFile, or automatically from Let's Encrypt for AutoDomain
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	// AutoDomain enables automatic certificates for this hostname; issued
+	// certificates are cached in AutoCacheDir
+	AutoDomain   string
+	AutoCacheDir string
 }
 
 // NewServer creates a new server instance
@@ -34,15 +136,35 @@ func NewServer(config Config) (*Server, error) {
 	textProcessor := core.NewTextProcessor()
 	codeProcessor := core.NewCodeProcessor()
 
+	mergeStrategy, err := db.ParseMergeStrategy(string(config.MergeStrategy))
+	if err != nil {
+		return nil, err
+	}
+
 	var database db.DatabaseConnection
-	if config.UseMemgraph {
-		memgraphDB := db.NewMemgraphDatabase("bolt://localhost:7687", "", "")
+	switch {
+	case config.PostgresDSN != "":
+		postgresDB := db.NewPostgresDatabase(config.PostgresDSN).WithMergeStrategy(mergeStrategy).WithQueryTimeout(config.QueryTimeout)
+		if err := postgresDB.Connect(); err != nil {
+			return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		}
+		database = postgresDB
+	case config.UseMemgraph:
+		memgraphDB := db.NewMemgraphDatabase(
+			db.MemgraphSetting(config.MemgraphURI, "MEMGRAPH_URI", ""),
+			db.MemgraphSetting(config.MemgraphUser, "MEMGRAPH_USER", ""),
+			db.MemgraphSetting(config.MemgraphPassword, "MEMGRAPH_PASSWORD", ""),
+		)
+		memgraphDB.WithMergeStrategy(mergeStrategy).WithQueryTimeout(config.QueryTimeout)
+		memgraphDB.Encrypted = config.MemgraphTLS
+		memgraphDB.SkipVerify = config.MemgraphTLSSkipVerify
+		memgraphDB.TLSConfig = config.MemgraphTLSConfig
 		if err := memgraphDB.Connect(); err != nil {
 			return nil, fmt.Errorf("failed to connect to Memgraph: %w", err)
 		}
 		database = memgraphDB
-	} else {
-		database = db.NewInMemoryDatabase()
+	default:
+		database = db.NewInMemoryDatabase().WithMergeStrategy(mergeStrategy)
 		if err := database.Connect(); err != nil {
 			return nil, fmt.Errorf("failed to connect to in-memory database: %w", err)
 		}
@@ -50,13 +172,27 @@ func NewServer(config Config) (*Server, error) {
 
 	generator := core.NewKnowledgeGraphGenerator(textProcessor, database)
 
+	basePath := NormalizeBasePath(config.BasePath)
+
+	ipExtractor, err := clientIPExtractor(config.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create Echo instance
 	e := echo.New()
+	e.IPExtractor = ipExtractor
 
 	// Middleware
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.Use(middleware.CORSWithConfig(corsConfig(config)))
+	if config.Compress {
+		e.Use(middleware.Gzip())
+	}
+	if config.RateLimit > 0 {
+		e.Use(newIPRateLimiter(config.RateLimit, config.RateBurst).middleware(basePath + "/health"))
+	}
 
 	// Hide Echo banner if not verbose
 	if !config.Verbose {
@@ -69,6 +205,24 @@ func NewServer(config Config) (*Server, error) {
 		database:      database,
 		echo:          e,
 		port:          config.Port,
+		watchDir:      config.WatchDir,
+		events:        newEventBroker(),
+		readOnly:      config.ReadOnly,
+		tls:           config.TLS,
+		queryTimeout:  config.QueryTimeout,
+		basePath:      basePath,
+		allowRemote:   config.AllowRemote,
+	}
+
+	if config.TLS.AutoDomain != "" {
+		e.AutoTLSManager.Prompt = autocert.AcceptTOS
+		e.AutoTLSManager.HostPolicy = autocert.HostWhitelist(config.TLS.AutoDomain)
+		e.AutoTLSManager.Cache = autocert.DirCache(config.TLS.AutoCacheDir)
+	}
+
+	server.graphqlSchema, err = server.newGraphQLSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
 	}
 
 	server.setupRoutes()
@@ -76,38 +230,123 @@ func NewServer(config Config) (*Server, error) {
 	return server, nil
 }
 
-// setupRoutes configures all the API routes
+// queryContext returns the context for the database operations of a request,
+// which ends when the client disconnects or after the query timeout
+func (s *Server) queryContext(c echo.Context) (context.Context, context.CancelFunc) {
+	return db.WithQueryTimeout(c.Request().Context(), s.queryTimeout)
+}
+
+// corsConfig returns the CORS configuration for the configured origins. Echo
+// allows every origin when AllowOrigins is empty, so without origins every
+// origin is rejected explicitly.
+func corsConfig(config Config) middleware.CORSConfig {
+	switch {
+	case config.CORSAllowAll:
+		return middleware.DefaultCORSConfig
+	case len(config.CORSAllowOrigins) > 0:
+		return middleware.CORSConfig{AllowOrigins: config.CORSAllowOrigins}
+	default:
+		return middleware.CORSConfig{
+			AllowOriginFunc: func(origin string) (bool, error) { return false, nil },
+		}
+	}
+}
+
+// NormalizeBasePath returns a base path with a leading slash and without a
+// trailing one, or "" for the root
+func NormalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// setupRoutes configures all the API routes under the base path
 func (s *Server) setupRoutes() {
+	root := s.echo.Group(s.basePath)
+
 	// API group
-	api := s.echo.Group("/api")
+	api := root.Group("/api")
 
 	// Analysis endpoints
-	api.POST("/analyze/text", s.analyzeTextHandler())
-	api.POST("/analyze/file", s.analyzeFileHandler())
-	api.POST("/analyze/codebase", s.analyzeCodebaseHandler())
+	if s.readOnly {
+		api.POST("/analyze/*", s.readOnlyHandler())
+	} else {
+		api.POST("/analyze/text", s.analyzeTextHandler())
+		api.POST("/analyze/file", s.analyzeFileHandler())
+		api.POST("/analyze/codebase", s.analyzeCodebaseHandler())
+		api.POST("/analyze/url", s.analyzeURLHandler())
+	}
+
+	// Editing endpoints
+	if s.readOnly {
+		api.POST("/entities", s.readOnlyHandler())
+		api.DELETE("/entities/:id", s.readOnlyHandler())
+		api.POST("/relationships", s.readOnlyHandler())
+		api.DELETE("/relationships/:id", s.readOnlyHandler())
+	} else {
+		api.POST("/entities", s.createEntityHandler())
+		api.DELETE("/entities/:id", s.deleteEntityHandler())
+		api.POST("/relationships", s.createRelationshipHandler())
+		api.DELETE("/relationships/:id", s.deleteRelationshipHandler())
+	}
 
 	// Query endpoints
 	api.GET("/stats", s.getStatsHandler())
 	api.GET("/entities", s.getEntitiesHandler())
 	api.GET("/relationships", s.getRelationshipsHandler())
 	api.GET("/query", s.queryHandler())
+	api.GET("/subgraph", s.subgraphHandler())
+	api.GET("/complexity", s.complexityHandler())
+	api.POST("/graphql", s.graphqlHandler())
+	api.GET("/graphql", s.graphqlSchemaHandler())
+
+	// Analysis results
+	api.GET("/analysis/circular-imports", s.circularImportsHandler())
+
+	// Live updates
+	api.GET("/events", s.eventsHandler())
 
 	// Health check
-	s.echo.GET("/health", s.healthHandler())
+	root.GET("/health", s.healthHandler())
 
-	// API documentation endpoint
-	s.echo.GET("/", s.docsHandler())
+	// API documentation endpoint, also served at the base path without a trailing slash
+	root.GET("/", s.docsHandler())
+	if s.basePath != "" {
+		s.echo.GET(s.basePath, s.docsHandler())
+	}
 }
 
 // Start starts the server
 func (s *Server) Start() error {
-	return s.echo.Start(fmt.Sprintf(":%d", s.port))
+	if s.watchDir != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopWatching = cancel
+		go s.watchCodebase(ctx)
+	}
+
+	addr := fmt.Sprintf(":%d", s.port)
+	switch {
+	case s.tls.AutoDomain != "":
+		return s.echo.StartAutoTLS(addr)
+	case s.tls.CertFile != "" && s.tls.KeyFile != "":
+		return s.echo.StartTLS(addr, s.tls.CertFile, s.tls.KeyFile)
+	default:
+		return s.echo.Start(addr)
+	}
 }
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
-	if memgraphDB, ok := s.database.(*db.MemgraphDatabase); ok {
-		memgraphDB.Disconnect()
+	if s.stopWatching != nil {
+		s.stopWatching()
+	}
+	switch database := s.database.(type) {
+	case *db.MemgraphDatabase:
+		database.Disconnect()
+	case *db.PostgresDatabase:
+		database.Disconnect()
 	}
 	return nil
 }
@@ -125,6 +364,30 @@ type AnalyzeCodebaseRequest struct {
 	Directory string `json:"directory" validate:"required"`
 }
 
+type AnalyzeURLRequest struct {
+	URL string `json:"url" validate:"required"`
+}
+
+// CreateEntityRequest is the body of POST /api/entities. The ID is derived
+// from the label, type and properties when omitted.
+type CreateEntityRequest struct {
+	ID         string           `json:"id"`
+	Label      string           `json:"label" validate:"required"`
+	Type       graph.EntityType `json:"type" validate:"required"`
+	Properties graph.Properties `json:"properties"`
+	Confidence *float64         `json:"confidence"`
+}
+
+// CreateRelationshipRequest is the body of POST /api/relationships. The source
+// and target entities must exist.
+type CreateRelationshipRequest struct {
+	Source     string                 `json:"source" validate:"required"`
+	Target     string                 `json:"target" validate:"required"`
+	Type       graph.RelationshipType `json:"type" validate:"required"`
+	Properties graph.Properties       `json:"properties"`
+	Confidence *float64               `json:"confidence"`
+}
+
 type AnalysisResponse struct {
 	Success       bool                   `json:"success"`
 	Message       string                 `json:"message,omitempty"`
@@ -170,6 +433,14 @@ func (s *Server) analyzeTextHandler() echo.HandlerFunc {
 			})
 		}
 
+		minConfidence, err := minConfidenceParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
 		entities, relationships, err := s.analyzeText(req.Text)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
@@ -178,10 +449,12 @@ func (s *Server) analyzeTextHandler() echo.HandlerFunc {
 			})
 		}
 
+		kg := (&graph.KnowledgeGraph{Entities: entities, Relationships: relationships}).FilterByConfidence(minConfidence)
+
 		return c.JSON(http.StatusOK, AnalysisResponse{
 			Success:       true,
-			Entities:      entities,
-			Relationships: relationships,
+			Entities:      kg.Entities,
+			Relationships: kg.Relationships,
 		})
 	}
 }
@@ -203,6 +476,14 @@ func (s *Server) analyzeFileHandler() echo.HandlerFunc {
 			})
 		}
 
+		minConfidence, err := minConfidenceParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
 		kg, err := s.analyzeFile(req.FilePath)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
@@ -211,8 +492,8 @@ func (s *Server) analyzeFileHandler() echo.HandlerFunc {
 			})
 		}
 
-		// Store in database
-		err = s.generator.StoreKnowledgeGraph(kg.Entities, kg.Relationships)
+		// Store in database; the backend bounds each query or batch
+		err = s.generator.StoreKnowledgeGraph(c.Request().Context(), kg.Entities, kg.Relationships)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
 				Success: false,
@@ -220,6 +501,8 @@ func (s *Server) analyzeFileHandler() echo.HandlerFunc {
 			})
 		}
 
+		kg = kg.FilterByConfidence(minConfidence)
+
 		return c.JSON(http.StatusOK, AnalysisResponse{
 			Success:       true,
 			Entities:      kg.Entities,
@@ -245,6 +528,18 @@ func (s *Server) analyzeCodebaseHandler() echo.HandlerFunc {
 			})
 		}
 
+		minConfidence, err := minConfidenceParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		if c.QueryParam("stream") == "true" {
+			return s.streamCodebaseAnalysis(c, req.Directory, minConfidence)
+		}
+
 		kg, err := s.analyzeCodebase(req.Directory)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
@@ -253,8 +548,108 @@ func (s *Server) analyzeCodebaseHandler() echo.HandlerFunc {
 			})
 		}
 
-		// Store in database
-		err = s.generator.StoreKnowledgeGraph(kg.Entities, kg.Relationships)
+		// Store in database; the backend bounds each query or batch
+		err = s.generator.StoreKnowledgeGraph(c.Request().Context(), kg.Entities, kg.Relationships)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to store results: %v", err),
+			})
+		}
+
+		kg = kg.FilterByConfidence(minConfidence)
+
+		return c.JSON(http.StatusOK, AnalysisResponse{
+			Success:       true,
+			Entities:      kg.Entities,
+			Relationships: kg.Relationships,
+		})
+	}
+}
+
+// analyzeURLHandler clones a public repository into a directory derived from its URL,
+// analyzes it like analyzeCodebaseHandler and removes the clone. It is only
+// active when the server allows remote analysis.
+func (s *Server) analyzeURLHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !s.allowRemote {
+			return c.JSON(http.StatusForbidden, AnalysisResponse{
+				Success: false,
+				Message: "Remote analysis is disabled: start the server with --allow-remote",
+			})
+		}
+
+		var req AnalyzeURLRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "Invalid request format",
+			})
+		}
+
+		if req.URL == "" {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "URL field is required",
+			})
+		}
+
+		// Clone into a directory that only depends on the URL, so analyzing a
+		// repository again updates its entities instead of storing a copy
+		// under new paths. Its last element names the directory entity.
+		directory, err := git.CloneDir(filepath.Join(os.TempDir(), "codegraphgen-clones"), req.URL)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		minConfidence, err := minConfidenceParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		// One clone at a time, which also keeps requests for the same
+		// repository from sharing the directory
+		s.cloneMutex.Lock()
+		defer s.cloneMutex.Unlock()
+
+		if err := os.RemoveAll(directory); err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to prepare clone directory: %v", err),
+			})
+		}
+		defer os.RemoveAll(directory)
+
+		cloneCtx, cancel := context.WithTimeout(c.Request().Context(), cloneTimeout)
+		defer cancel()
+
+		if err := git.ShallowClone(cloneCtx, strings.TrimSpace(req.URL), directory, maxCloneSize); err != nil {
+			status := http.StatusBadGateway
+			if errors.Is(err, git.ErrCheckoutTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			return c.JSON(status, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to clone repository: %v", err),
+			})
+		}
+
+		kg, err := s.analyzeCodebase(directory)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Codebase analysis failed: %v", err),
+			})
+		}
+
+		// Store in database; the backend bounds each query or batch
+		err = s.generator.StoreKnowledgeGraph(c.Request().Context(), kg.Entities, kg.Relationships)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
 				Success: false,
@@ -262,6 +657,8 @@ func (s *Server) analyzeCodebaseHandler() echo.HandlerFunc {
 			})
 		}
 
+		kg = kg.FilterByConfidence(minConfidence)
+
 		return c.JSON(http.StatusOK, AnalysisResponse{
 			Success:       true,
 			Entities:      kg.Entities,
@@ -270,9 +667,22 @@ func (s *Server) analyzeCodebaseHandler() echo.HandlerFunc {
 	}
 }
 
+// readOnlyHandler rejects analysis requests when the server runs in read-only mode
+func (s *Server) readOnlyHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusMethodNotAllowed, AnalysisResponse{
+			Success: false,
+			Message: "Server is running in read-only mode",
+		})
+	}
+}
+
 func (s *Server) getStatsHandler() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		stats, err := s.generator.GetGraphStatistics()
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		stats, err := s.generator.GetGraphStatistics(ctx)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
 				Success: false,
@@ -289,19 +699,23 @@ func (s *Server) getStatsHandler() echo.HandlerFunc {
 
 func (s *Server) getEntitiesHandler() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		results, err := s.database.Query("MATCH (n) RETURN n", nil)
+		minConfidence, err := minConfidenceParam(c)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
 				Success: false,
-				Message: fmt.Sprintf("Failed to get entities: %v", err),
+				Message: err.Error(),
 			})
 		}
 
-		entities := make([]graph.Entity, 0)
-		for _, result := range results {
-			if entity, ok := result["n"].(graph.Entity); ok {
-				entities = append(entities, entity)
-			}
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		entities, err := s.generator.GetEntities(ctx, minConfidence)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to get entities: %v", err),
+			})
 		}
 
 		return c.JSON(http.StatusOK, AnalysisResponse{
@@ -313,7 +727,18 @@ func (s *Server) getEntitiesHandler() echo.HandlerFunc {
 
 func (s *Server) getRelationshipsHandler() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		results, err := s.database.Query("MATCH (a)-[r]->(b) RETURN r", nil)
+		minConfidence, err := minConfidenceParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		relationships, err := s.generator.GetRelationships(ctx, minConfidence)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
 				Success: false,
@@ -321,16 +746,169 @@ func (s *Server) getRelationshipsHandler() echo.HandlerFunc {
 			})
 		}
 
-		relationships := make([]graph.Relationship, 0)
-		for _, result := range results {
-			if rel, ok := result["r"].(graph.Relationship); ok {
-				relationships = append(relationships, rel)
+		return c.JSON(http.StatusOK, AnalysisResponse{
+			Success:       true,
+			Relationships: relationships,
+		})
+	}
+}
+
+func (s *Server) createEntityHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CreateEntityRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "Invalid request format",
+			})
+		}
+
+		if req.Label == "" || req.Type == "" {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "Label and type fields are required",
+			})
+		}
+
+		confidence, err := requestConfidence(req.Confidence)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		entity := graph.CreateEntity(req.Label, req.Type, req.Properties)
+		if req.ID != "" {
+			entity.ID = req.ID
+		}
+		entity.Confidence = confidence
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		if err := s.generator.CreateEntity(ctx, entity); err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to create entity: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusCreated, AnalysisResponse{
+			Success:  true,
+			Entities: []graph.Entity{entity},
+		})
+	}
+}
+
+func (s *Server) deleteEntityHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		if err := s.generator.DeleteEntity(ctx, id); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, db.ErrNotFound) {
+				status = http.StatusNotFound
 			}
+			return c.JSON(status, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to delete entity: %v", err),
+			})
 		}
 
 		return c.JSON(http.StatusOK, AnalysisResponse{
+			Success: true,
+			Message: fmt.Sprintf("Entity %s deleted", id),
+		})
+	}
+}
+
+func (s *Server) createRelationshipHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CreateRelationshipRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "Invalid request format",
+			})
+		}
+
+		if req.Source == "" || req.Target == "" || req.Type == "" {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "Source, target and type fields are required",
+			})
+		}
+
+		confidence, err := requestConfidence(req.Confidence)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		// Relationships between unknown entities would be dropped by Memgraph
+		// and dangle in the other backends
+		for _, id := range []string{req.Source, req.Target} {
+			if _, err := s.generator.GetEntityByID(ctx, id); err != nil {
+				if errors.Is(err, db.ErrNotFound) {
+					return c.JSON(http.StatusNotFound, AnalysisResponse{
+						Success: false,
+						Message: fmt.Sprintf("Entity %s not found", id),
+					})
+				}
+				return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+					Success: false,
+					Message: fmt.Sprintf("Failed to get entity %s: %v", id, err),
+				})
+			}
+		}
+
+		relationship := graph.CreateRelationship(req.Source, req.Target, req.Type, req.Properties)
+		relationship.Confidence = confidence
+
+		if err := s.generator.CreateRelationship(ctx, relationship); err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to create relationship: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusCreated, AnalysisResponse{
 			Success:       true,
-			Relationships: relationships,
+			Relationships: []graph.Relationship{relationship},
+		})
+	}
+}
+
+func (s *Server) deleteRelationshipHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		if err := s.generator.DeleteRelationship(ctx, id); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, db.ErrNotFound) {
+				status = http.StatusNotFound
+			}
+			return c.JSON(status, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to delete relationship: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, AnalysisResponse{
+			Success: true,
+			Message: fmt.Sprintf("Relationship %s deleted", id),
 		})
 	}
 }
@@ -345,7 +923,29 @@ func (s *Server) queryHandler() echo.HandlerFunc {
 			})
 		}
 
-		results, err := s.generator.QueryKnowledgeGraph(query, nil)
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		// In read-only mode queries that modify the graph are rejected
+		var results []db.QueryResult
+		var err error
+		if s.readOnly {
+			results, err = s.generator.ReadQueryKnowledgeGraph(ctx, query, nil)
+		} else {
+			results, err = s.generator.QueryKnowledgeGraph(ctx, query, nil)
+		}
+		if errors.Is(err, db.ErrWriteQuery) {
+			return c.JSON(http.StatusMethodNotAllowed, AnalysisResponse{
+				Success: false,
+				Message: "Write queries are not allowed in read-only mode",
+			})
+		}
+		if errors.Is(err, db.ErrUnsupportedQuery) {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Query failed: %v", err),
+			})
+		}
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
 				Success: false,
@@ -360,14 +960,165 @@ func (s *Server) queryHandler() echo.HandlerFunc {
 	}
 }
 
+func (s *Server) subgraphHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		entityID := c.QueryParam("id")
+		if entityID == "" {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: "Query parameter 'id' is required",
+			})
+		}
+
+		depth := 2
+		if depthParam := c.QueryParam("depth"); depthParam != "" {
+			parsed, err := strconv.Atoi(depthParam)
+			if err != nil || parsed < 0 {
+				return c.JSON(http.StatusBadRequest, AnalysisResponse{
+					Success: false,
+					Message: "Query parameter 'depth' must be a non-negative integer",
+				})
+			}
+			depth = parsed
+		}
+
+		minConfidence, err := minConfidenceParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, AnalysisResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		subgraph, err := s.generator.GetSubgraph(ctx, entityID, depth)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to get subgraph: %v", err),
+			})
+		}
+
+		if len(subgraph.Entities) == 0 {
+			return c.JSON(http.StatusNotFound, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Entity %s not found", entityID),
+			})
+		}
+
+		subgraph = subgraph.FilterByConfidence(minConfidence)
+
+		return c.JSON(http.StatusOK, AnalysisResponse{
+			Success:       true,
+			Entities:      subgraph.Entities,
+			Relationships: subgraph.Relationships,
+		})
+	}
+}
+
+// minConfidenceParam parses the optional minConfidence query parameter,
+// defaulting to 0 so that nothing is filtered
+func minConfidenceParam(c echo.Context) (float64, error) {
+	param := c.QueryParam("minConfidence")
+	if param == "" {
+		return 0, nil
+	}
+
+	minConfidence, err := strconv.ParseFloat(param, 64)
+	if err != nil || minConfidence < 0 || minConfidence > 1 {
+		return 0, fmt.Errorf("query parameter 'minConfidence' must be a number between 0 and 1")
+	}
+	return minConfidence, nil
+}
+
+// requestConfidence returns the confidence given in a request body, 1 when
+// it is omitted
+func requestConfidence(confidence *float64) (float64, error) {
+	if confidence == nil {
+		return 1.0, nil
+	}
+	if *confidence < 0 || *confidence > 1 {
+		return 0, fmt.Errorf("field 'confidence' must be a number between 0 and 1")
+	}
+	return *confidence, nil
+}
+
+func (s *Server) complexityHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		threshold := 0
+		if thresholdParam := c.QueryParam("threshold"); thresholdParam != "" {
+			parsed, err := strconv.Atoi(thresholdParam)
+			if err != nil || parsed < 0 {
+				return c.JSON(http.StatusBadRequest, AnalysisResponse{
+					Success: false,
+					Message: "Query parameter 'threshold' must be a non-negative integer",
+				})
+			}
+			threshold = parsed
+		}
+
+		limit := 50
+		if limitParam := c.QueryParam("limit"); limitParam != "" {
+			parsed, err := strconv.Atoi(limitParam)
+			if err != nil || parsed < 1 {
+				return c.JSON(http.StatusBadRequest, AnalysisResponse{
+					Success: false,
+					Message: "Query parameter 'limit' must be a positive integer",
+				})
+			}
+			limit = parsed
+		}
+
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		entities, err := s.generator.GetEntities(ctx, 0)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to get entities: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"success":   true,
+			"functions": analysis.RankByComplexity(entities, threshold, limit),
+		})
+	}
+}
+
+func (s *Server) circularImportsHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, cancel := s.queryContext(c)
+		defer cancel()
+
+		cycles, err := s.generator.FindCircularImports(ctx)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to find circular imports: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"success": true,
+			"cycles":  cycles,
+		})
+	}
+}
+
 func (s *Server) healthHandler() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		_, isMemgraph := s.database.(*db.MemgraphDatabase)
 		return c.JSON(http.StatusOK, map[string]string{
 			"status": "healthy",
 			"database": func() string {
-				if isMemgraph {
+				switch s.database.(type) {
+				case *db.MemgraphDatabase:
 					return "memgraph"
+				case *db.PostgresDatabase:
+					return "postgres"
 				}
 				return "in-memory"
 			}(),
@@ -384,11 +1135,22 @@ func (s *Server) docsHandler() echo.HandlerFunc {
 				{Method: "GET", Path: "/health", Description: "Health check endpoint"},
 				{Method: "POST", Path: "/api/analyze/text", Description: "Analyze text content"},
 				{Method: "POST", Path: "/api/analyze/file", Description: "Analyze a file"},
-				{Method: "POST", Path: "/api/analyze/codebase", Description: "Analyze a codebase directory"},
+				{Method: "POST", Path: "/api/analyze/codebase", Description: "Analyze a codebase directory (stream=true streams NDJSON progress events)"},
+				{Method: "POST", Path: "/api/analyze/url", Description: "Clone and analyze a GitHub, GitLab or Bitbucket repository (requires --allow-remote)"},
 				{Method: "GET", Path: "/api/stats", Description: "Get knowledge graph statistics"},
-				{Method: "GET", Path: "/api/entities", Description: "Get all entities"},
-				{Method: "GET", Path: "/api/relationships", Description: "Get all relationships"},
+				{Method: "GET", Path: "/api/entities", Description: "Get all entities (?minConfidence=0.8)"},
+				{Method: "POST", Path: "/api/entities", Description: "Create an entity (label, type, properties)"},
+				{Method: "DELETE", Path: "/api/entities/:id", Description: "Delete an entity and its relationships"},
+				{Method: "GET", Path: "/api/relationships", Description: "Get all relationships (?minConfidence=0.8)"},
+				{Method: "POST", Path: "/api/relationships", Description: "Create a relationship between existing entities (source, target, type)"},
+				{Method: "DELETE", Path: "/api/relationships/:id", Description: "Delete a relationship"},
 				{Method: "GET", Path: "/api/query", Description: "Execute a query against the graph"},
+				{Method: "GET", Path: "/api/subgraph", Description: "Get the neighborhood of an entity (?id=<id>&depth=2)"},
+				{Method: "POST", Path: "/api/graphql", Description: "Execute a GraphQL query (entity, entities, relationships, stats)"},
+				{Method: "GET", Path: "/api/graphql", Description: "Get the GraphQL schema by introspection"},
+				{Method: "GET", Path: "/api/complexity", Description: "Get functions by cyclomatic complexity (?threshold=10&limit=50)"},
+				{Method: "GET", Path: "/api/analysis/circular-imports", Description: "Find import cycles"},
+				{Method: "GET", Path: "/api/events", Description: "Server-sent graph-updated events (live mode)"},
 			},
 			Examples: map[string]ExampleDoc{
 				"analyze_text": {
@@ -403,6 +1165,11 @@ func (s *Server) docsHandler() echo.HandlerFunc {
 			},
 		}
 
+		// Clients behind a reverse proxy need the full paths
+		for i := range docs.Endpoints {
+			docs.Endpoints[i].Path = s.basePath + docs.Endpoints[i].Path
+		}
+
 		return c.JSON(http.StatusOK, docs)
 	}
 }
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"codegraphgen/internal/core/graph"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AnalysisProgressEvent is streamed by POST /api/analyze/codebase?stream=true
+// after each file has been analyzed
+type AnalysisProgressEvent struct {
+	Type          string `json:"type"`
+	File          string `json:"file"`
+	Entities      int    `json:"entities"`
+	Relationships int    `json:"relationships"`
+}
+
+// AnalysisDoneEvent is the last event of a streamed analysis that succeeded
+type AnalysisDoneEvent struct {
+	Type               string `json:"type"`
+	TotalEntities      int    `json:"totalEntities"`
+	TotalRelationships int    `json:"totalRelationships"`
+}
+
+// AnalysisErrorEvent is the last event of a streamed analysis that failed
+type AnalysisErrorEvent struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// streamCodebaseAnalysis analyzes and stores a codebase while writing
+// newline-delimited JSON events to the response: a progress event per file and
+// a final done or error event. The response is sent with chunked encoding so
+// that clients see progress without waiting for the whole analysis. Like the
+// response of the batch analysis, the counts leave out results below minConfidence.
+func (s *Server) streamCodebaseAnalysis(c echo.Context, directory string, minConfidence float64) error {
+	writer := c.Response().Writer
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, AnalysisResponse{
+			Success: false,
+			Message: "Streaming is not supported by this connection",
+		})
+	}
+
+	writer.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	writer.Header().Set(echo.HeaderCacheControl, "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(writer)
+	send := func(event interface{}) {
+		// A client that went away only loses the remaining events
+		if err := encoder.Encode(event); err != nil && err != io.EOF {
+			log.Printf("⚠️ Failed to stream analysis event: %v", err)
+			return
+		}
+		flusher.Flush()
+	}
+
+	// The shared code processor may be in use by other requests, so progress is
+	// reported by a copy of it for this request
+	processor := s.codeProcessor.Clone()
+	processor.SetProgressFunc(func(file graph.CodeFile, entities []graph.Entity, relationships []graph.Relationship) {
+		kg := (&graph.KnowledgeGraph{Entities: entities, Relationships: relationships}).FilterByConfidence(minConfidence)
+		send(AnalysisProgressEvent{
+			Type:          "progress",
+			File:          file.Path,
+			Entities:      len(kg.Entities),
+			Relationships: len(kg.Relationships),
+		})
+	})
+
+	entities, relationships, err := processor.AnalyzeCodebase(directory)
+	if err != nil {
+		send(AnalysisErrorEvent{Type: "error", Message: fmt.Sprintf("Codebase analysis failed: %v", err)})
+		return nil
+	}
+
+	if err := s.generator.StoreKnowledgeGraph(c.Request().Context(), entities, relationships); err != nil {
+		send(AnalysisErrorEvent{Type: "error", Message: fmt.Sprintf("Failed to store results: %v", err)})
+		return nil
+	}
+
+	kg := (&graph.KnowledgeGraph{Entities: entities, Relationships: relationships}).FilterByConfidence(minConfidence)
+	send(AnalysisDoneEvent{
+		Type:               "done",
+		TotalEntities:      len(kg.Entities),
+		TotalRelationships: len(kg.Relationships),
+	})
+	return nil
+}